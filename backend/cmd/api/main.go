@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"log"
+	"time"
+	_ "time/tzdata" // IANA zones for profile timezone validation on hosts without zoneinfo
 
 	"github.com/gin-gonic/gin"
 	"github.com/khchoi-tnh/timingle/internal/config"
@@ -11,6 +13,8 @@ import (
 	"github.com/khchoi-tnh/timingle/internal/middleware"
 	"github.com/khchoi-tnh/timingle/internal/repositories"
 	"github.com/khchoi-tnh/timingle/internal/services"
+	"github.com/khchoi-tnh/timingle/internal/sms"
+	"github.com/khchoi-tnh/timingle/internal/storage"
 	"github.com/khchoi-tnh/timingle/internal/websocket"
 	"github.com/khchoi-tnh/timingle/pkg/utils"
 )
@@ -56,16 +60,29 @@ func main() {
 	defer natsClient.Close()
 
 	// Create NATS Streams
-	if err := natsClient.CreateStreams(); err != nil {
+	if err := natsClient.CreateStreams(cfg.Calendar.ReconcileInterval); err != nil {
 		log.Fatalf("Failed to create NATS streams: %v", err)
 	}
 
 	// Initialize WebSocket Hub
-	hub := websocket.NewHub()
+	hub := websocket.NewHub(websocket.NewPresence(redisClient.Client))
 	go hub.Run()
 
-	// Initialize JWT manager
-	jwtManager := utils.NewJWTManager(cfg.JWT.Secret, cfg.JWT.AccessExpiry, cfg.JWT.RefreshExpiry)
+	// Initialize JWT manager (asymmetric keys when a key manifest is configured)
+	var jwtManager *utils.JWTManager
+	if cfg.JWT.KeysFile != "" {
+		keySet, err := utils.LoadKeySet(cfg.JWT.KeysFile)
+		if err != nil {
+			log.Fatalf("Failed to load JWT signing keys: %v", err)
+		}
+		if _, err := keySet.SigningKey(time.Now()); err != nil {
+			log.Fatalf("Failed to load JWT signing keys: %v", err)
+		}
+		jwtManager = utils.NewJWTManagerWithKeys(keySet, cfg.JWT.AccessExpiry, cfg.JWT.RefreshExpiry)
+		log.Println("✅ JWT signing keys loaded")
+	} else {
+		jwtManager = utils.NewJWTManager(cfg.JWT.Secret, cfg.JWT.AccessExpiry, cfg.JWT.RefreshExpiry)
+	}
 
 	// Initialize Google OAuth verifier with client secret for token refresh
 	googleVerifier := utils.NewGoogleOAuthVerifierWithSecret(
@@ -76,6 +93,14 @@ func main() {
 		cfg.OAuth.GoogleClientIDWeb,
 	)
 
+	// Initialize Apple identity token verifier
+	appleVerifier := utils.NewAppleOAuthVerifier(
+		cfg.OAuth.AppleAuthURL,
+		nil,
+		cfg.OAuth.AppleClientID,
+		cfg.OAuth.AppleServiceID,
+	)
+
 	// Initialize repositories
 	userRepo := repositories.NewUserRepository(postgresDB.DB)
 	eventRepo := repositories.NewEventRepository(postgresDB.DB)
@@ -83,24 +108,153 @@ func main() {
 	oauthRepo := repositories.NewOAuthRepository(postgresDB.DB)
 	chatRepo := repositories.NewChatRepository(scyllaDB.Session)
 	inviteRepo := repositories.NewInviteRepository(postgresDB.DB)
+	pollRepo := repositories.NewPollRepository(postgresDB.DB)
+	attendanceRepo := repositories.NewAttendanceRepository(postgresDB.DB)
+	reminderRepo := repositories.NewReminderRepository(postgresDB.DB)
+	deviceRepo := repositories.NewDeviceRepository(postgresDB.DB)
+	notificationRepo := repositories.NewNotificationRepository(postgresDB.DB)
+	friendRepo := repositories.NewFriendRepository(postgresDB.DB)
+	mediaRepo := repositories.NewMediaRepository(postgresDB.DB)
+	calendarSyncRepo := repositories.NewCalendarSyncRepository(postgresDB.DB)
+	calendarFeedRepo := repositories.NewCalendarFeedRepository(postgresDB.DB)
 
 	// Initialize services
-	authService := services.NewAuthService(userRepo, authRepo, oauthRepo, jwtManager, googleVerifier)
-	eventService := services.NewEventService(eventRepo, userRepo)
-	chatService := services.NewChatService(chatRepo, userRepo, eventService, hub, natsClient.JS)
-	calendarService := services.NewCalendarService(authService, eventRepo, oauthRepo)
-	inviteService := services.NewInviteService(inviteRepo, eventRepo, userRepo, cfg.Server.BaseURL)
+	// SMS provider for phone verification
+	var smsProvider sms.Provider
+	switch cfg.OTP.SMSProvider {
+	case "":
+		log.Fatalf("SMS_PROVIDER is required")
+	case "log":
+		if cfg.Server.GinMode == gin.ReleaseMode {
+			log.Fatalf("SMS_PROVIDER=log prints verification codes to the log and is not allowed in release mode")
+		}
+		log.Println("⚠️  Using logging SMS provider (verification codes are printed to the log)")
+		smsProvider = sms.NewLogProvider()
+	case "twilio":
+		twilio, err := sms.NewTwilioProvider(cfg.OTP.TwilioAccountSID, cfg.OTP.TwilioAuthToken, cfg.OTP.TwilioFromNumber, "", nil)
+		if err != nil {
+			log.Fatalf("Failed to initialize SMS provider: %v", err)
+		}
+		smsProvider = twilio
+	default:
+		log.Fatalf("Unsupported SMS provider: %s", cfg.OTP.SMSProvider)
+	}
+	// Codes are only stored as HMACs; a key shared with JWT_SECRET (which has a default) would not be secret
+	if len(cfg.OTP.Secret) < 32 || cfg.OTP.Secret == cfg.JWT.Secret {
+		log.Fatalf("OTP_SECRET is required (at least 32 characters, different from JWT_SECRET)")
+	}
+	// A check-in QR token forged with a known key would allow remote check-ins
+	if len(cfg.Attendance.QRSecret) < 32 || cfg.Attendance.QRSecret == cfg.JWT.Secret || cfg.Attendance.QRSecret == cfg.OTP.Secret {
+		log.Fatalf("CHECKIN_QR_SECRET is required (at least 32 characters, different from JWT_SECRET and OTP_SECRET)")
+	}
+	otpService := services.NewOTPService(redisClient.Client, smsProvider, cfg.OTP.Secret, services.OTPPolicy{
+		CodeTTL:          cfg.OTP.CodeTTL,
+		VerificationTTL:  cfg.OTP.VerificationTTL,
+		MaxAttempts:      cfg.OTP.MaxAttempts,
+		ResendInterval:   cfg.OTP.ResendInterval,
+		PhoneHourlyLimit: cfg.OTP.PhoneHourlyLimit,
+		IPHourlyLimit:    cfg.OTP.IPHourlyLimit,
+	})
+
+	authService := services.NewAuthService(userRepo, authRepo, oauthRepo, jwtManager, googleVerifier, appleVerifier, otpService)
+	notificationService := services.NewNotificationService(notificationRepo, hub)
+	calendarService := services.NewCalendarService(authService, eventRepo, userRepo, oauthRepo, calendarSyncRepo, natsClient.JS, cfg.Calendar.GoogleAPIURL)
+	eventService := services.NewEventService(eventRepo, userRepo, pollRepo, chatRepo, attendanceRepo, reminderRepo, friendRepo, inviteRepo, notificationService, calendarService, natsClient.JS)
+	// Media storage for uploaded images
+	var mediaStorage storage.Storage
+	switch cfg.Media.StorageBackend {
+	case "local":
+		localStorage, err := storage.NewLocalStorage(cfg.Media.LocalDir)
+		if err != nil {
+			log.Fatalf("Failed to initialize media storage: %v", err)
+		}
+		mediaStorage = localStorage
+	case "s3":
+		s3Storage, err := storage.NewS3Storage(storage.S3Config{
+			Endpoint:  cfg.Media.S3Endpoint,
+			Region:    cfg.Media.S3Region,
+			Bucket:    cfg.Media.S3Bucket,
+			AccessKey: cfg.Media.S3AccessKey,
+			SecretKey: cfg.Media.S3SecretKey,
+			PathStyle: cfg.Media.S3PathStyle,
+		}, nil)
+		if err != nil {
+			log.Fatalf("Failed to initialize media storage: %v", err)
+		}
+		mediaStorage = s3Storage
+	default:
+		log.Fatalf("Unsupported media storage: %s", cfg.Media.StorageBackend)
+	}
+	mediaService := services.NewMediaService(mediaRepo, eventService, mediaStorage, cfg.Server.BaseURL, services.MediaPolicy{
+		MaxUploadBytes: int64(cfg.Media.MaxUploadBytes),
+		MaxDimension:   cfg.Media.MaxDimension,
+		ThumbnailSize:  cfg.Media.ThumbnailSize,
+		URLExpiry:      cfg.Media.URLExpiry,
+	})
+	chatService := services.NewChatService(chatRepo, userRepo, eventService, mediaService, hub, natsClient.JS)
+	calendarSyncService := services.NewCalendarSyncService(calendarService, eventService, eventRepo, calendarSyncRepo, services.CalendarSyncPolicy{
+		WatchTTL:    cfg.Calendar.WatchTTL,
+		RenewWithin: cfg.Calendar.WatchRenewWithin,
+		WebhookURL:  cfg.Server.BaseURL + "/api/v1/calendar/webhook",
+	})
+	inviteService := services.NewInviteService(inviteRepo, eventRepo, userRepo, friendRepo, notificationService, cfg.Server.BaseURL)
+	calendarFeedService := services.NewCalendarFeedService(calendarFeedRepo, eventRepo, cfg.Server.BaseURL)
+	pollService := services.NewPollService(pollRepo, eventRepo, eventService, hub)
+	attendanceService := services.NewAttendanceService(attendanceRepo, eventRepo, eventService, cfg.Attendance.QRSecret)
+	deviceService := services.NewDeviceService(deviceRepo)
+	friendService := services.NewFriendService(friendRepo, userRepo, notificationService)
+	userService := services.NewUserService(userRepo, friendRepo)
+	contactService := services.NewContactService(userRepo, friendRepo, redisClient.Client, services.ContactSyncPolicy{
+		MaxHashesPerRequest: cfg.Contacts.MaxHashesPerRequest,
+		DailyLimit:          cfg.Contacts.DailyLimit,
+	})
+	accountService := services.NewAccountService(userRepo, oauthRepo, attendanceRepo, calendarSyncService, jwtManager, googleVerifier)
+	privacyService := services.NewPrivacyService(userRepo, eventRepo, authRepo, oauthRepo, chatRepo, calendarService, calendarSyncService, googleVerifier, redisClient.Client, services.PrivacyPolicy{
+		DeletionGrace:  cfg.Privacy.DeletionGrace,
+		ExportCooldown: cfg.Privacy.ExportCooldown,
+	})
+	reminderService := services.NewReminderService(reminderRepo, eventRepo, userRepo, eventService, natsClient.JS, cfg.Reminder.Lookahead)
+
+	workingHours, err := services.ParseWorkingHours(
+		cfg.Availability.WorkdayStart,
+		cfg.Availability.WorkdayEnd,
+		cfg.Availability.IncludeWeekends,
+	)
+	if err != nil {
+		log.Fatalf("Invalid availability config: %v", err)
+	}
+	availabilityService := services.NewAvailabilityService(eventService, userRepo, eventRepo, friendRepo, calendarService, workingHours, cfg.Availability.SlotStep)
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
+	otpHandler := handlers.NewOTPHandler(otpService)
 	eventHandler := handlers.NewEventHandler(eventService)
-	calendarHandler := handlers.NewCalendarHandler(calendarService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService, calendarSyncService)
+	calendarFeedHandler := handlers.NewCalendarFeedHandler(calendarFeedService)
 	wsHandler := handlers.NewWebSocketHandler(hub, chatService)
 	inviteHandler := handlers.NewInviteHandler(inviteService)
+	pollHandler := handlers.NewPollHandler(pollService)
+	availabilityHandler := handlers.NewAvailabilityHandler(availabilityService)
+	attendanceHandler := handlers.NewAttendanceHandler(attendanceService)
+	reminderHandler := handlers.NewReminderHandler(reminderService)
+	deviceHandler := handlers.NewDeviceHandler(deviceService)
+	accountHandler := handlers.NewAccountHandler(accountService)
+	friendHandler := handlers.NewFriendHandler(friendService)
+	contactHandler := handlers.NewContactHandler(contactService)
+	userHandler := handlers.NewUserHandler(userService)
+	privacyHandler := handlers.NewPrivacyHandler(privacyService)
+	mediaHandler := handlers.NewMediaHandler(mediaService, int64(cfg.Media.MaxUploadBytes))
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	jwksHandler := handlers.NewJWKSHandler(jwtManager)
 
 	// Setup router
 	router := gin.Default()
 
+	// Client IPs (OTP rate limits, session IPs) only come from X-Forwarded-For of trusted proxies
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
 	// Apply middleware
 	router.Use(middleware.CORSMiddleware())
 	router.Use(middleware.LoggerMiddleware())
@@ -113,17 +267,26 @@ func main() {
 		})
 	})
 
+	// Public signing keys for services verifying access tokens
+	router.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
+
+	// iCalendar subscription feeds (webcal), authenticated by the secret token in the URL
+	router.GET("/feeds/:file", calendarFeedHandler.Feed)
+
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
 		// Auth routes (public)
 		auth := v1.Group("/auth")
 		{
+			auth.POST("/otp/request", otpHandler.RequestCode)
+			auth.POST("/otp/verify", otpHandler.VerifyCode)
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
 			auth.POST("/refresh", authHandler.RefreshToken)
 			auth.POST("/google", authHandler.GoogleLogin)                   // Google OAuth login
 			auth.POST("/google/calendar", authHandler.GoogleCalendarLogin) // Google OAuth login with Calendar scope
+			auth.POST("/apple", authHandler.AppleLogin)                     // Sign in with Apple
 
 			// Protected auth routes
 			authProtected := auth.Group("")
@@ -131,6 +294,9 @@ func main() {
 			{
 				authProtected.POST("/logout", authHandler.Logout)
 				authProtected.GET("/me", authHandler.Me)
+				authProtected.GET("/sessions", authHandler.ListSessions)
+				authProtected.DELETE("/sessions/:id", authHandler.RevokeSession)
+				authProtected.POST("/sessions/revoke-others", authHandler.RevokeOtherSessions) // Log out everywhere else
 			}
 		}
 
@@ -146,11 +312,30 @@ func main() {
 
 			// Event actions
 			events.POST("/:id/participants", eventHandler.AddParticipant)
+			events.POST("/:id/invite-friends", eventHandler.InviteFriends)
 			events.DELETE("/:id/participants/:participant_id", eventHandler.RemoveParticipant)
 			events.POST("/:id/confirm-participation", eventHandler.ConfirmParticipation)
 			events.POST("/:id/confirm", eventHandler.ConfirmEvent)
 			events.POST("/:id/cancel", eventHandler.CancelEvent)
 			events.POST("/:id/done", eventHandler.MarkEventDone)
+			events.GET("/:id/history", eventHandler.GetEventHistory)
+			events.GET("/:id/ics", calendarFeedHandler.ExportEvent)
+
+			// Recurring event occurrences
+			events.POST("/:id/occurrences/cancel", eventHandler.CancelOccurrence)
+			events.POST("/:id/occurrences/respond", eventHandler.RespondToOccurrence)
+
+			// Time poll
+			events.POST("/:id/poll", pollHandler.CreatePoll)
+			events.GET("/:id/poll", pollHandler.GetPoll)
+			events.POST("/:id/poll/votes", pollHandler.Vote)
+
+			// Attendance (check-in, no-show)
+			events.POST("/:id/check-in", attendanceHandler.CheckIn)
+			events.GET("/:id/check-in/qr", attendanceHandler.GetCheckInQR)
+			events.PUT("/:id/geofence", attendanceHandler.SetGeofence)
+			events.GET("/:id/attendance", attendanceHandler.GetAttendance)
+			events.PUT("/:id/attendance/:user_id", attendanceHandler.RecordAttendance)
 
 			// Chat messages
 			events.GET("/:id/messages", wsHandler.GetMessages)
@@ -171,6 +356,85 @@ func main() {
 
 		// WebSocket route (protected)
 		v1.GET("/ws", middleware.AuthMiddleware(jwtManager, userRepo), wsHandler.HandleWebSocket)
+		v1.GET("/ws/user", middleware.AuthMiddleware(jwtManager, userRepo), wsHandler.HandleUserWebSocket)
+
+		// Notification inbox routes (protected)
+		notifications := v1.Group("/notifications")
+		notifications.Use(middleware.AuthMiddleware(jwtManager, userRepo))
+		{
+			notifications.GET("", notificationHandler.GetNotifications)
+			notifications.POST("/:id/read", notificationHandler.MarkRead)
+			notifications.POST("/read-all", notificationHandler.MarkAllRead)
+		}
+
+		// Current user routes (protected)
+		me := v1.Group("/me")
+		me.Use(middleware.AuthMiddleware(jwtManager, userRepo))
+		{
+			me.PATCH("", userHandler.UpdateMe)
+			me.DELETE("", privacyHandler.DeleteMe)
+			me.POST("/deletion/cancel", privacyHandler.CancelDeletion)
+			me.POST("/export", privacyHandler.Export)
+			me.GET("/reminders", reminderHandler.GetPreferences)
+			me.PUT("/reminders", reminderHandler.UpdatePreferences)
+			me.POST("/devices", deviceHandler.RegisterDevice)
+			me.DELETE("/devices", deviceHandler.UnregisterDevice)
+			me.GET("/accounts", accountHandler.GetLinkedAccounts)
+			me.POST("/accounts/google", accountHandler.LinkGoogle)
+			me.POST("/accounts/merge", accountHandler.MergeAccounts)
+			me.DELETE("/accounts/:provider", accountHandler.UnlinkProvider)
+			me.POST("/calendar-feed", calendarFeedHandler.CreateFeed)
+			me.GET("/calendar-feed", calendarFeedHandler.GetFeed)
+			me.DELETE("/calendar-feed", calendarFeedHandler.RevokeFeed)
+		}
+
+		// User profile routes (protected)
+		users := v1.Group("/users")
+		users.Use(middleware.AuthMiddleware(jwtManager, userRepo))
+		{
+			users.GET("/:id", userHandler.GetUser)
+		}
+
+		// Friend routes (protected)
+		friends := v1.Group("/friends")
+		friends.Use(middleware.AuthMiddleware(jwtManager, userRepo))
+		{
+			friends.GET("", friendHandler.ListFriends)
+			friends.DELETE("/:user_id", friendHandler.RemoveFriend)
+			friends.GET("/requests", friendHandler.ListRequests)
+			friends.POST("/requests", friendHandler.SendRequest)
+			friends.POST("/requests/:user_id/accept", friendHandler.AcceptRequest)
+			friends.POST("/requests/:user_id/decline", friendHandler.DeclineRequest)
+			friends.DELETE("/requests/:user_id", friendHandler.CancelRequest)
+			friends.GET("/blocked", friendHandler.ListBlocked)
+			friends.POST("/blocked/:user_id", friendHandler.Block)
+			friends.DELETE("/blocked/:user_id", friendHandler.Unblock)
+		}
+
+		// Media routes (protected)
+		media := v1.Group("/media")
+		media.Use(middleware.AuthMiddleware(jwtManager, userRepo))
+		{
+			media.POST("", mediaHandler.Upload)
+			media.POST("/uploads", mediaHandler.CreateUpload)
+			media.POST("/:id/complete", mediaHandler.CompleteUpload)
+			media.GET("/:id", mediaHandler.GetMedia)
+			media.GET("/:id/thumbnail", mediaHandler.GetThumbnail)
+		}
+
+		// Contact sync routes (protected)
+		contacts := v1.Group("/contacts")
+		contacts.Use(middleware.AuthMiddleware(jwtManager, userRepo))
+		{
+			contacts.POST("/sync", contactHandler.SyncContacts)
+		}
+
+		// Availability routes (protected)
+		availability := v1.Group("/availability")
+		availability.Use(middleware.AuthMiddleware(jwtManager, userRepo))
+		{
+			availability.POST("/slots", availabilityHandler.FindSlots)
+		}
 
 		// Calendar routes (protected)
 		calendar := v1.Group("/calendar")
@@ -179,7 +443,12 @@ func main() {
 			calendar.GET("/status", calendarHandler.CheckCalendarAccess)
 			calendar.GET("/events", calendarHandler.GetCalendarEvents)
 			calendar.POST("/sync/:event_id", calendarHandler.SyncEventToCalendar)
+			calendar.POST("/watch", calendarHandler.Watch)
+			calendar.DELETE("/watch", calendarHandler.Unwatch)
 		}
+
+		// Google Calendar push notifications (public, verified by channel token)
+		v1.POST("/calendar/webhook", calendarHandler.HandleWebhook)
 	}
 
 	// Start server
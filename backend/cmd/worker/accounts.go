@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/khchoi-tnh/timingle/internal/services"
+)
+
+// runAccountPurge erases accounts whose deletion grace period has ended, every interval until ctx is done
+// Anonymizing re-checks the schedule in a transaction, so concurrent worker replicas do no harm
+func runAccountPurge(ctx context.Context, privacyService *services.PrivacyService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := privacyService.PurgeDue(ctx, time.Now())
+		if err != nil {
+			log.Printf("Failed to purge deleted accounts: %v", err)
+		} else if purged > 0 {
+			log.Printf("🗑️ Erased %d deleted accounts", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/khchoi-tnh/timingle/internal/services"
+)
+
+// runCalendarWatchRenewal re-registers Google Calendar push channels before they expire, every interval until ctx is done
+// Google caps channel lifetimes, so a channel left alone silently stops sending notifications
+func runCalendarWatchRenewal(ctx context.Context, calendarSyncService *services.CalendarSyncService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		renewed, err := calendarSyncService.RenewExpiring(ctx, time.Now())
+		if err != nil {
+			log.Printf("Failed to renew calendar channels: %v", err)
+		} else if renewed > 0 {
+			log.Printf("📅 Renewed %d calendar channels", renewed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runCalendarReconciliation queues a full calendar reconciliation for every user with Calendar access, every interval until ctx is done
+// Copies whose update jobs were lost or gave up are caught up, and missed push notifications are pulled
+func runCalendarReconciliation(ctx context.Context, calendarService *services.CalendarService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		queued, err := calendarService.EnqueueReconciliation(time.Now(), interval)
+		if err != nil {
+			log.Printf("Failed to queue calendar reconciliation: %v", err)
+		} else if queued > 0 {
+			log.Printf("📅 Queued calendar reconciliation for %d users", queued)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -1,17 +1,23 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/gin-gonic/gin"
 	"github.com/nats-io/nats.go"
 	"github.com/khchoi-tnh/timingle/internal/config"
 	"github.com/khchoi-tnh/timingle/internal/db"
 	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/notifications"
 	"github.com/khchoi-tnh/timingle/internal/repositories"
+	"github.com/khchoi-tnh/timingle/internal/services"
+	"github.com/khchoi-tnh/timingle/internal/websocket"
+	"github.com/khchoi-tnh/timingle/pkg/utils"
 )
 
 func main() {
@@ -21,6 +27,13 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Connect to PostgreSQL
+	postgresDB, err := db.NewPostgresDB(cfg.GetPostgresConnectionString())
+	if err != nil {
+		log.Fatalf("Failed to connect to PostgreSQL: %v", err)
+	}
+	defer postgresDB.Close()
+
 	// Connect to ScyllaDB
 	scyllaDB, err := db.NewScyllaDB(cfg.ScyllaDB.Hosts, cfg.ScyllaDB.Keyspace)
 	if err != nil {
@@ -30,6 +43,13 @@ func main() {
 
 	chatRepo := repositories.NewChatRepository(scyllaDB.Session)
 
+	// Connect to Redis (WebSocket presence for push suppression)
+	redisClient, err := db.NewRedisClient(cfg.Redis.Host, cfg.Redis.Port, cfg.Redis.Password, cfg.Redis.DB)
+	if err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	defer redisClient.Close()
+
 	// Connect to NATS
 	natsClient, err := db.NewNATSClient(cfg.NATS.URL)
 	if err != nil {
@@ -37,6 +57,10 @@ func main() {
 	}
 	defer natsClient.Close()
 
+	if err := natsClient.CreateStreams(cfg.Calendar.ReconcileInterval); err != nil {
+		log.Fatalf("Failed to create NATS streams: %v", err)
+	}
+
 	// Create JetStream consumer
 	sub, err := natsClient.JS.Subscribe("chat.message.*", func(msg *nats.Msg) {
 		// Parse message
@@ -63,6 +87,70 @@ func main() {
 	}
 	defer sub.Unsubscribe()
 
+	// Event reminder scheduler
+	userRepo := repositories.NewUserRepository(postgresDB.DB)
+	eventRepo := repositories.NewEventRepository(postgresDB.DB)
+	pollRepo := repositories.NewPollRepository(postgresDB.DB)
+	attendanceRepo := repositories.NewAttendanceRepository(postgresDB.DB)
+	reminderRepo := repositories.NewReminderRepository(postgresDB.DB)
+	notificationRepo := repositories.NewNotificationRepository(postgresDB.DB)
+	friendRepo := repositories.NewFriendRepository(postgresDB.DB)
+	inviteRepo := repositories.NewInviteRepository(postgresDB.DB)
+	authRepo := repositories.NewAuthRepository(postgresDB.DB)
+	oauthRepo := repositories.NewOAuthRepository(postgresDB.DB)
+	calendarSyncRepo := repositories.NewCalendarSyncRepository(postgresDB.DB)
+
+	// Google Calendar: event changes are written to members' calendar copies
+	googleVerifier := utils.NewGoogleOAuthVerifierWithSecret(cfg.OAuth.GoogleClientIDWeb, cfg.OAuth.GoogleClientSecret)
+	authService := services.NewAuthService(userRepo, authRepo, oauthRepo, nil, googleVerifier, nil, nil)
+
+	notificationService := services.NewNotificationService(notificationRepo, nil)
+	calendarService := services.NewCalendarService(authService, eventRepo, userRepo, oauthRepo, calendarSyncRepo, natsClient.JS, cfg.Calendar.GoogleAPIURL)
+	eventService := services.NewEventService(eventRepo, userRepo, pollRepo, chatRepo, attendanceRepo, reminderRepo, friendRepo, inviteRepo, notificationService, calendarService, natsClient.JS)
+	reminderService := services.NewReminderService(reminderRepo, eventRepo, userRepo, eventService, natsClient.JS, cfg.Reminder.Lookahead)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runReminderScheduler(ctx, reminderService, cfg.Reminder.ScanInterval)
+
+	// Google Calendar: run sync jobs, renew push channels before Google stops them, reconcile periodically
+	calendarSyncService := services.NewCalendarSyncService(calendarService, eventService, eventRepo, calendarSyncRepo, services.CalendarSyncPolicy{
+		WatchTTL:    cfg.Calendar.WatchTTL,
+		RenewWithin: cfg.Calendar.WatchRenewWithin,
+		WebhookURL:  cfg.Server.BaseURL + "/api/v1/calendar/webhook",
+	})
+	go runCalendarWatchRenewal(ctx, calendarSyncService, cfg.Calendar.WatchRenewInterval)
+	go runCalendarReconciliation(ctx, calendarService, cfg.Calendar.ReconcileInterval)
+	calendarConsumer := services.NewCalendarJobConsumer(natsClient.JS, calendarService, calendarSyncService, services.CalendarJobPolicy{
+		MaxAttempts: cfg.Calendar.JobMaxAttempts,
+		BackoffBase: cfg.Calendar.JobBackoffBase,
+		BackoffMax:  cfg.Calendar.JobBackoffMax,
+	})
+	if err := calendarConsumer.Start(); err != nil {
+		log.Fatalf("Failed to start calendar sync consumer: %v", err)
+	}
+	defer calendarConsumer.Stop()
+
+	// Account deletion: erase accounts past their grace period
+	privacyService := services.NewPrivacyService(userRepo, eventRepo, authRepo, oauthRepo, chatRepo, calendarService, calendarSyncService, googleVerifier, redisClient.Client, services.PrivacyPolicy{
+		DeletionGrace:  cfg.Privacy.DeletionGrace,
+		ExportCooldown: cfg.Privacy.ExportCooldown,
+	})
+	go runAccountPurge(ctx, privacyService, cfg.Privacy.PurgeInterval)
+
+	// Push notifications
+	providers, err := newPushProviders(ctx, cfg.Push, cfg.Server.GinMode == gin.ReleaseMode)
+	if err != nil {
+		log.Fatalf("Failed to initialize push providers: %v", err)
+	}
+	deviceRepo := repositories.NewDeviceRepository(postgresDB.DB)
+	notifier := notifications.NewNotifier(deviceRepo, providers...)
+	pushConsumer := notifications.NewConsumer(natsClient.JS, notifier, eventRepo, websocket.NewPresence(redisClient.Client))
+	if err := pushConsumer.Start(); err != nil {
+		log.Fatalf("Failed to start push consumer: %v", err)
+	}
+	defer pushConsumer.Stop()
+
 	log.Println("🚀 Chat worker started. Listening for messages...")
 
 	// Graceful shutdown
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/khchoi-tnh/timingle/internal/config"
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/notifications"
+)
+
+// newPushProviders creates the FCM and APNs providers, falling back to a
+// logging fake for any provider without credentials (local development).
+// In release mode missing credentials are an error instead.
+func newPushProviders(ctx context.Context, cfg config.PushConfig, release bool) ([]notifications.Provider, error) {
+	providers := []notifications.Provider{}
+
+	if cfg.FCMProjectID != "" && cfg.FCMCredentialsFile != "" {
+		fcm, err := notifications.NewFCMProviderFromCredentials(ctx, cfg.FCMProjectID, cfg.FCMCredentialsFile, cfg.FCMBaseURL)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, fcm)
+	} else if release {
+		return nil, fmt.Errorf("FCM_PROJECT_ID and FCM_CREDENTIALS_FILE are required in release mode")
+	} else {
+		log.Println("⚠️  FCM not configured, using fake push provider")
+		providers = append(providers, notifications.NewFakeProvider(models.PushProviderFCM))
+	}
+
+	if cfg.APNsKeyFile != "" && cfg.APNsKeyID != "" && cfg.APNsTeamID != "" && cfg.APNsTopic != "" {
+		apns, err := notifications.NewAPNsProviderFromFile(cfg.APNsKeyFile, cfg.APNsKeyID, cfg.APNsTeamID, cfg.APNsTopic, cfg.APNsBaseURL)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, apns)
+	} else if release {
+		return nil, fmt.Errorf("APNS_KEY_FILE, APNS_KEY_ID, APNS_TEAM_ID and APNS_TOPIC are required in release mode")
+	} else {
+		log.Println("⚠️  APNs not configured, using fake push provider")
+		providers = append(providers, notifications.NewFakeProvider(models.PushProviderAPNs))
+	}
+
+	return providers, nil
+}
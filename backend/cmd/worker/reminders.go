@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/khchoi-tnh/timingle/internal/services"
+)
+
+// runReminderScheduler plans and publishes event reminders every interval until ctx is done
+// Several worker replicas may run it at once; the reminder table serializes them
+func runReminderScheduler(ctx context.Context, reminderService *services.ReminderService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now()
+
+		planned, err := reminderService.PlanReminders(now)
+		if err != nil {
+			log.Printf("Failed to plan reminders: %v", err)
+		} else if planned > 0 {
+			log.Printf("⏰ Planned %d reminders", planned)
+		}
+
+		sent, err := reminderService.DispatchDue(now)
+		if err != nil {
+			log.Printf("Failed to dispatch reminders: %v", err)
+		}
+		if sent > 0 {
+			log.Printf("✅ Published %d reminders", sent)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
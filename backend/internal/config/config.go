@@ -4,26 +4,127 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Config holds all application configuration
 type Config struct {
-	Server   ServerConfig
-	Postgres PostgresConfig
-	Redis    RedisConfig
-	NATS     NATSConfig
-	ScyllaDB ScyllaDBConfig
-	JWT      JWTConfig
-	OAuth    OAuthConfig
+	Server       ServerConfig
+	Postgres     PostgresConfig
+	Redis        RedisConfig
+	NATS         NATSConfig
+	ScyllaDB     ScyllaDBConfig
+	JWT          JWTConfig
+	OAuth        OAuthConfig
+	Availability AvailabilityConfig
+	Attendance   AttendanceConfig
+	Reminder     ReminderConfig
+	Push         PushConfig
+	OTP          OTPConfig
+	Contacts     ContactsConfig
+	Media        MediaConfig
+	Privacy      PrivacyConfig
+	Calendar     CalendarConfig
 }
 
 // OAuthConfig holds OAuth provider configuration
 type OAuthConfig struct {
-	GoogleClientID        string // Android client ID
-	GoogleClientIDiOS     string // iOS client ID
-	GoogleClientIDWeb     string // Web client ID (used for ID token verification)
-	GoogleClientSecret    string // Web client secret (for token refresh)
+	GoogleClientID     string // Android client ID
+	GoogleClientIDiOS  string // iOS client ID
+	GoogleClientIDWeb  string // Web client ID (used for ID token verification)
+	GoogleClientSecret string // Web client secret (for token refresh)
+	AppleClientID      string // iOS bundle ID
+	AppleServiceID     string // Services ID (Sign in with Apple on web/Android)
+	AppleAuthURL       string // Identity token issuer and JWKS host
+}
+
+// AvailabilityConfig holds default working hours for free-time suggestions
+type AvailabilityConfig struct {
+	WorkdayStart    string // "HH:MM" in each user's timezone
+	WorkdayEnd      string // "HH:MM" in each user's timezone
+	IncludeWeekends bool
+	SlotStep        time.Duration // Granularity of candidate start times
+}
+
+// AttendanceConfig holds check-in configuration
+type AttendanceConfig struct {
+	QRSecret string // Required HMAC key of rotating check-in QR tokens, separate from JWT_SECRET
+}
+
+// ReminderConfig holds event reminder scheduler configuration (cmd/worker)
+type ReminderConfig struct {
+	ScanInterval time.Duration // How often due reminders are planned and published
+	Lookahead    time.Duration // How far ahead reminders are planned (must exceed the largest reminder offset)
+}
+
+// PushConfig holds push notification provider configuration (cmd/worker)
+// A provider without credentials is replaced by a logging fake
+type PushConfig struct {
+	FCMProjectID       string
+	FCMCredentialsFile string // Service account JSON with the firebase.messaging scope
+	FCMBaseURL         string
+	APNsKeyFile        string // .p8 token signing key
+	APNsKeyID          string
+	APNsTeamID         string
+	APNsTopic          string // iOS bundle ID
+	APNsBaseURL        string // Sandbox for development builds
+}
+
+// OTPConfig holds SMS one-time password configuration
+type OTPConfig struct {
+	CodeTTL          time.Duration // How long a sent code can be verified
+	VerificationTTL  time.Duration // How long a verified phone can be used to register or log in
+	MaxAttempts      int           // Wrong codes allowed before the code is invalidated
+	ResendInterval   time.Duration // Minimum time between codes for the same phone
+	PhoneHourlyLimit int           // Codes per phone per hour
+	IPHourlyLimit    int           // Codes per client IP per hour
+	Secret           string        // Required HMAC key of stored codes, separate from JWT_SECRET
+	SMSProvider      string        // Required: "twilio", or "log" to print codes to the server log (development only)
+	TwilioAccountSID string
+	TwilioAuthToken  string
+	TwilioFromNumber string // Sending number in E.164 form
+}
+
+// ContactsConfig holds contact sync limits
+type ContactsConfig struct {
+	MaxHashesPerRequest int // Phone hashes accepted in one sync request
+	DailyLimit          int // Distinct phone hashes a user can look up per 24h
+}
+
+// MediaConfig holds media upload and storage configuration
+type MediaConfig struct {
+	StorageBackend string // "local" (files under LocalDir) or "s3" (S3-compatible, e.g. MinIO)
+	LocalDir       string
+	S3Endpoint     string
+	S3Region       string
+	S3Bucket       string
+	S3AccessKey    string
+	S3SecretKey    string
+	S3PathStyle    bool // Required by MinIO
+	MaxUploadBytes int
+	MaxDimension   int           // Longer side of stored images in pixels
+	ThumbnailSize  int           // Longer side of thumbnails in pixels
+	URLExpiry      time.Duration // Lifetime of presigned upload/download URLs
+}
+
+// PrivacyConfig holds personal data export and account deletion configuration
+type PrivacyConfig struct {
+	DeletionGrace  time.Duration // Time a requested account deletion can be canceled
+	PurgeInterval  time.Duration // How often the worker erases accounts past their grace period (cmd/worker)
+	ExportCooldown time.Duration // Minimum time between data exports of a user
+}
+
+// CalendarConfig holds Google Calendar sync configuration
+type CalendarConfig struct {
+	GoogleAPIURL       string        // Calendar API base URL, overridable to point at a local stand-in
+	WatchTTL           time.Duration // Requested lifetime of push notification channels
+	WatchRenewInterval time.Duration // How often the worker renews expiring channels (cmd/worker)
+	WatchRenewWithin   time.Duration // Channels expiring within this window are renewed
+	JobMaxAttempts     int           // Deliveries of a failing sync job before it is dropped
+	JobBackoffBase     time.Duration // Delay before the first retry of a sync job, doubled per attempt
+	JobBackoffMax      time.Duration // Upper bound of the sync job retry delay
+	ReconcileInterval  time.Duration // How often the worker queues a full reconciliation per user (cmd/worker)
 }
 
 // ServerConfig holds server-specific configuration
@@ -31,6 +132,8 @@ type ServerConfig struct {
 	Port    string
 	GinMode string
 	BaseURL string // Base URL for generating invite links
+	// Proxies (IPs or CIDRs) whose X-Forwarded-For is trusted for the client IP; empty trusts none
+	TrustedProxies []string
 }
 
 // PostgresConfig holds PostgreSQL connection configuration
@@ -64,7 +167,8 @@ type ScyllaDBConfig struct {
 
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
-	Secret        string
+	Secret        string // HS256 shared secret, used when KeysFile is empty
+	KeysFile      string // JSON manifest of RS256/EdDSA signing keys and their rotation schedule
 	AccessExpiry  time.Duration
 	RefreshExpiry time.Duration
 }
@@ -73,9 +177,10 @@ type JWTConfig struct {
 func Load() (*Config, error) {
 	config := &Config{
 		Server: ServerConfig{
-			Port:    getEnv("PORT", "8080"),
-			GinMode: getEnv("GIN_MODE", "debug"),
-			BaseURL: getEnv("BASE_URL", "https://timingle.app"),
+			Port:           getEnv("PORT", "8080"),
+			GinMode:        getEnv("GIN_MODE", "debug"),
+			BaseURL:        getEnv("BASE_URL", "https://timingle.app"),
+			TrustedProxies: getEnvAsList("TRUSTED_PROXIES"),
 		},
 		Postgres: PostgresConfig{
 			Host:     getEnv("POSTGRES_HOST", "localhost"),
@@ -100,14 +205,87 @@ func Load() (*Config, error) {
 		},
 		JWT: JWTConfig{
 			Secret:        getEnv("JWT_SECRET", "your-secret-key-change-this-in-production"),
+			KeysFile:      getEnv("JWT_KEYS_FILE", ""),
 			AccessExpiry:  getEnvAsDuration("JWT_ACCESS_EXPIRY", "15m"),
 			RefreshExpiry: getEnvAsDuration("JWT_REFRESH_EXPIRY", "168h"),
 		},
 		OAuth: OAuthConfig{
-			GoogleClientID:        getEnv("GOOGLE_CLIENT_ID_AND", ""),
-			GoogleClientIDiOS:     getEnv("GOOGLE_CLIENT_ID_IOS", ""),
-			GoogleClientIDWeb:     getEnv("GOOGLE_CLIENT_ID_WEB", ""),
-			GoogleClientSecret:    getEnv("GOOGLE_CLIENT_SECRET", ""),
+			GoogleClientID:     getEnv("GOOGLE_CLIENT_ID_AND", ""),
+			GoogleClientIDiOS:  getEnv("GOOGLE_CLIENT_ID_IOS", ""),
+			GoogleClientIDWeb:  getEnv("GOOGLE_CLIENT_ID_WEB", ""),
+			GoogleClientSecret: getEnv("GOOGLE_CLIENT_SECRET", ""),
+			AppleClientID:      getEnv("APPLE_CLIENT_ID", ""),
+			AppleServiceID:     getEnv("APPLE_SERVICE_ID", ""),
+			AppleAuthURL:       getEnv("APPLE_AUTH_URL", "https://appleid.apple.com"),
+		},
+		Availability: AvailabilityConfig{
+			WorkdayStart:    getEnv("AVAILABILITY_WORKDAY_START", "09:00"),
+			WorkdayEnd:      getEnv("AVAILABILITY_WORKDAY_END", "18:00"),
+			IncludeWeekends: getEnvAsBool("AVAILABILITY_INCLUDE_WEEKENDS", false),
+			SlotStep:        getEnvAsDuration("AVAILABILITY_SLOT_STEP", "30m"),
+		},
+		Attendance: AttendanceConfig{
+			QRSecret: getEnv("CHECKIN_QR_SECRET", ""),
+		},
+		Reminder: ReminderConfig{
+			ScanInterval: getEnvAsDuration("REMINDER_SCAN_INTERVAL", "1m"),
+			Lookahead:    getEnvAsDuration("REMINDER_LOOKAHEAD", "192h"),
+		},
+		OTP: OTPConfig{
+			CodeTTL:          getEnvAsDuration("OTP_CODE_TTL", "5m"),
+			VerificationTTL:  getEnvAsDuration("OTP_VERIFICATION_TTL", "10m"),
+			MaxAttempts:      getEnvAsInt("OTP_MAX_ATTEMPTS", 5),
+			ResendInterval:   getEnvAsDuration("OTP_RESEND_INTERVAL", "60s"),
+			PhoneHourlyLimit: getEnvAsInt("OTP_PHONE_HOURLY_LIMIT", 5),
+			IPHourlyLimit:    getEnvAsInt("OTP_IP_HOURLY_LIMIT", 20),
+			Secret:           getEnv("OTP_SECRET", ""),
+			SMSProvider:      getEnv("SMS_PROVIDER", ""),
+			TwilioAccountSID: getEnv("TWILIO_ACCOUNT_SID", ""),
+			TwilioAuthToken:  getEnv("TWILIO_AUTH_TOKEN", ""),
+			TwilioFromNumber: getEnv("TWILIO_PHONE_NUMBER", ""),
+		},
+		Contacts: ContactsConfig{
+			MaxHashesPerRequest: getEnvAsInt("CONTACT_SYNC_MAX_HASHES", 1000),
+			DailyLimit:          getEnvAsInt("CONTACT_SYNC_DAILY_LIMIT", 3000),
+		},
+		Media: MediaConfig{
+			StorageBackend: getEnv("MEDIA_STORAGE", "local"),
+			LocalDir:       getEnv("MEDIA_LOCAL_DIR", "./data/media"),
+			S3Endpoint:     getEnv("S3_ENDPOINT", ""),
+			S3Region:       getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:       getEnv("S3_BUCKET", ""),
+			S3AccessKey:    getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey:    getEnv("S3_SECRET_KEY", ""),
+			S3PathStyle:    getEnvAsBool("S3_PATH_STYLE", false),
+			MaxUploadBytes: getEnvAsInt("MEDIA_MAX_UPLOAD_BYTES", 10<<20),
+			MaxDimension:   getEnvAsInt("MEDIA_MAX_DIMENSION", 2048),
+			ThumbnailSize:  getEnvAsInt("MEDIA_THUMBNAIL_SIZE", 320),
+			URLExpiry:      getEnvAsDuration("MEDIA_URL_EXPIRY", "15m"),
+		},
+		Calendar: CalendarConfig{
+			GoogleAPIURL:       getEnv("GOOGLE_CALENDAR_API_URL", "https://www.googleapis.com/calendar/v3/"),
+			WatchTTL:           getEnvAsDuration("CALENDAR_WATCH_TTL", "168h"),
+			WatchRenewInterval: getEnvAsDuration("CALENDAR_WATCH_RENEW_INTERVAL", "1h"),
+			WatchRenewWithin:   getEnvAsDuration("CALENDAR_WATCH_RENEW_WITHIN", "24h"),
+			JobMaxAttempts:     getEnvAsInt("CALENDAR_SYNC_MAX_ATTEMPTS", 8),
+			JobBackoffBase:     getEnvAsDuration("CALENDAR_SYNC_BACKOFF_BASE", "30s"),
+			JobBackoffMax:      getEnvAsDuration("CALENDAR_SYNC_BACKOFF_MAX", "1h"),
+			ReconcileInterval:  getEnvAsDuration("CALENDAR_RECONCILE_INTERVAL", "6h"),
+		},
+		Privacy: PrivacyConfig{
+			DeletionGrace:  getEnvAsDuration("ACCOUNT_DELETION_GRACE", "720h"),
+			PurgeInterval:  getEnvAsDuration("ACCOUNT_PURGE_INTERVAL", "1h"),
+			ExportCooldown: getEnvAsDuration("DATA_EXPORT_COOLDOWN", "1h"),
+		},
+		Push: PushConfig{
+			FCMProjectID:       getEnv("FCM_PROJECT_ID", ""),
+			FCMCredentialsFile: getEnv("FCM_CREDENTIALS_FILE", ""),
+			FCMBaseURL:         getEnv("FCM_BASE_URL", "https://fcm.googleapis.com"),
+			APNsKeyFile:        getEnv("APNS_KEY_FILE", ""),
+			APNsKeyID:          getEnv("APNS_KEY_ID", ""),
+			APNsTeamID:         getEnv("APNS_TEAM_ID", ""),
+			APNsTopic:          getEnv("APNS_TOPIC", ""),
+			APNsBaseURL:        getEnv("APNS_BASE_URL", "https://api.sandbox.push.apple.com"),
 		},
 	}
 
@@ -148,6 +326,26 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvAsBool reads an environment variable as bool or returns a default value
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvAsList reads a comma-separated environment variable, skipping empty items
+func getEnvAsList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 // getEnvAsDuration reads an environment variable as duration or returns a default value
 func getEnvAsDuration(key, defaultValue string) time.Duration {
 	valueStr := getEnv(key, defaultValue)
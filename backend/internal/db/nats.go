@@ -42,7 +42,9 @@ func (n *NATSClient) Close() {
 }
 
 // CreateStreams creates JetStream streams (run once during initialization)
-func (n *NATSClient) CreateStreams() error {
+// calendarDedupWindow is how long CALENDAR_SYNC remembers message IDs; it must cover the
+// calendar reconciliation interval so a period's jobs are queued once across workers
+func (n *NATSClient) CreateStreams(calendarDedupWindow time.Duration) error {
 	// Chat messages stream
 	_, err := n.JS.AddStream(&nats.StreamConfig{
 		Name:     "CHAT_MESSAGES",
@@ -65,6 +67,24 @@ func (n *NATSClient) CreateStreams() error {
 		return fmt.Errorf("failed to create EVENTS stream: %w", err)
 	}
 
+	// Calendar sync jobs stream (work queue: a job is removed once the worker acknowledges it)
+	calendarSync := &nats.StreamConfig{
+		Name:       "CALENDAR_SYNC",
+		Subjects:   []string{"calendar.sync.*"},
+		Retention:  nats.WorkQueuePolicy,
+		MaxAge:     7 * 24 * time.Hour, // 7 days retention
+		Duplicates: min(calendarDedupWindow, 7*24*time.Hour),
+		Storage:    nats.FileStorage,
+	}
+	_, err = n.JS.AddStream(calendarSync)
+	if err == nats.ErrStreamNameAlreadyInUse {
+		// An existing stream keeps its old duplicate window unless updated
+		_, err = n.JS.UpdateStream(calendarSync)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create CALENDAR_SYNC stream: %w", err)
+	}
+
 	log.Println("✅ NATS JetStream streams created/verified")
 
 	return nil
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/services"
+)
+
+// AccountHandler handles linked login method HTTP requests
+type AccountHandler struct {
+	accountService *services.AccountService
+}
+
+// NewAccountHandler creates a new account handler
+func NewAccountHandler(accountService *services.AccountService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+	}
+}
+
+// GetLinkedAccounts returns the login methods of the current user
+// GET /api/v1/me/accounts
+func (h *AccountHandler) GetLinkedAccounts(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	accounts, err := h.accountService.GetLinkedAccounts(userID.(int64))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, accounts)
+}
+
+// LinkGoogle links a Google account to the current user
+// POST /api/v1/me/accounts/google
+// Request body: { "id_token": "..." }
+func (h *AccountHandler) LinkGoogle(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req models.GoogleLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	account, err := h.accountService.LinkGoogle(c.Request.Context(), userID.(int64), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+// UnlinkProvider removes a linked OAuth account from the current user
+// DELETE /api/v1/me/accounts/:provider
+func (h *AccountHandler) UnlinkProvider(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	provider := models.OAuthProvider(c.Param("provider"))
+	if err := h.accountService.UnlinkProvider(userID.(int64), provider); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "account unlinked successfully"})
+}
+
+// MergeAccounts merges another timingle account into the current user
+// POST /api/v1/me/accounts/merge
+// Request body: { "access_token": "<access token of the other account>" }
+func (h *AccountHandler) MergeAccounts(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req models.MergeAccountsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.accountService.MergeAccounts(c.Request.Context(), userID.(int64), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/services"
+)
+
+// AttendanceHandler handles check-in and attendance HTTP requests
+type AttendanceHandler struct {
+	attendanceService *services.AttendanceService
+}
+
+// NewAttendanceHandler creates a new attendance handler
+func NewAttendanceHandler(attendanceService *services.AttendanceService) *AttendanceHandler {
+	return &AttendanceHandler{
+		attendanceService: attendanceService,
+	}
+}
+
+// CheckIn records the current user's check-in by QR scan or geofence ping
+// POST /api/v1/events/:id/check-in
+func (h *AttendanceHandler) CheckIn(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	var req models.CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	attendance, err := h.attendanceService.CheckIn(eventID, userID.(int64), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, attendance)
+}
+
+// GetCheckInQR returns the current check-in QR token (creator only); it rotates every 30 seconds
+// GET /api/v1/events/:id/check-in/qr
+func (h *AttendanceHandler) GetCheckInQR(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	qr, err := h.attendanceService.GetCheckInQR(eventID, userID.(int64))
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, qr)
+}
+
+// SetGeofence sets the check-in location of an event (creator only)
+// PUT /api/v1/events/:id/geofence
+func (h *AttendanceHandler) SetGeofence(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	var req models.SetGeofenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	geofence, err := h.attendanceService.SetGeofence(eventID, userID.(int64), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, geofence)
+}
+
+// GetAttendance returns the attendance records of an event
+// GET /api/v1/events/:id/attendance
+func (h *AttendanceHandler) GetAttendance(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	records, err := h.attendanceService.GetAttendance(eventID, userID.(int64))
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"attendance": records,
+		"count":      len(records),
+	})
+}
+
+// RecordAttendance records a participant's attendance (creator only)
+// PUT /api/v1/events/:id/attendance/:user_id
+func (h *AttendanceHandler) RecordAttendance(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	participantID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	var req models.RecordAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	attendance, err := h.attendanceService.RecordAttendance(eventID, userID.(int64), participantID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, attendance)
+}
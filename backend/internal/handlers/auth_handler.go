@@ -2,12 +2,16 @@ package handlers
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/khchoi-tnh/timingle/internal/models"
 	"github.com/khchoi-tnh/timingle/internal/services"
 )
 
+// maxUserAgentLength caps the User-Agent stored with a session
+const maxUserAgentLength = 512
+
 // AuthHandler handles authentication HTTP requests
 type AuthHandler struct {
 	authService *services.AuthService
@@ -29,7 +33,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	response, err := h.authService.Register(&req)
+	response, err := h.authService.Register(&req, sessionMetadata(c))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -47,7 +51,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	response, err := h.authService.Login(&req)
+	response, err := h.authService.Login(&req, sessionMetadata(c))
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
@@ -65,7 +69,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	response, err := h.authService.RefreshToken(&req)
+	response, err := h.authService.RefreshToken(&req, sessionMetadata(c))
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
@@ -74,7 +78,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-// Logout handles user logout
+// Logout handles user logout of the current session
 // POST /api/v1/auth/logout
 func (h *AuthHandler) Logout(c *gin.Context) {
 	userID, exists := c.Get("userID")
@@ -83,7 +87,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		return
 	}
 
-	err := h.authService.Logout(userID.(int64))
+	err := h.authService.Logout(userID.(int64), c.GetString("sessionID"))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -92,6 +96,47 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "logged out successfully"})
 }
 
+// ListSessions returns the devices the current user is logged in on
+// GET /api/v1/auth/sessions
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	sessions, err := h.authService.ListSessions(userID.(int64), c.GetString("sessionID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+// RevokeSession logs the current user out of one session
+// DELETE /api/v1/auth/sessions/:id
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	if err := h.authService.RevokeSession(userID.(int64), c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "session revoked successfully"})
+}
+
+// RevokeOtherSessions logs the current user out everywhere except this session
+// POST /api/v1/auth/sessions/revoke-others
+func (h *AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	revoked, err := h.authService.RevokeOtherSessions(userID.(int64), c.GetString("sessionID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "other sessions revoked successfully", "revoked": revoked})
+}
+
 // Me returns current user info
 // GET /api/v1/auth/me
 func (h *AuthHandler) Me(c *gin.Context) {
@@ -115,7 +160,28 @@ func (h *AuthHandler) GoogleLogin(c *gin.Context) {
 		return
 	}
 
-	response, err := h.authService.GoogleLogin(c.Request.Context(), &req)
+	response, err := h.authService.GoogleLogin(c.Request.Context(), &req, sessionMetadata(c))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// AppleLogin handles Sign in with Apple login
+// POST /api/v1/auth/apple
+// Request body: { "id_token": "...", "nonce": "...", "given_name": "...", "family_name": "..." }
+// Response: AuthResponse with access_token, refresh_token, user
+// Apple only provides the name on the first sign-in, so clients should send it when present
+func (h *AuthHandler) AppleLogin(c *gin.Context) {
+	var req models.AppleLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.authService.AppleLogin(c.Request.Context(), &req, sessionMetadata(c))
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
@@ -136,7 +202,7 @@ func (h *AuthHandler) GoogleCalendarLogin(c *gin.Context) {
 		return
 	}
 
-	response, err := h.authService.GoogleLoginWithCalendar(c.Request.Context(), &req)
+	response, err := h.authService.GoogleLoginWithCalendar(c.Request.Context(), &req, sessionMetadata(c))
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
@@ -144,3 +210,26 @@ func (h *AuthHandler) GoogleCalendarLogin(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// sessionMetadata describes the client of a login or token refresh request
+// The platform comes from the X-Client-Platform header (ANDROID, IOS, WEB)
+func sessionMetadata(c *gin.Context) *models.SessionMetadata {
+	meta := &models.SessionMetadata{}
+
+	if platform := strings.ToUpper(c.GetHeader("X-Client-Platform")); models.SessionPlatforms[platform] {
+		meta.Platform = &platform
+	}
+
+	if userAgent := c.Request.UserAgent(); userAgent != "" {
+		if len(userAgent) > maxUserAgentLength {
+			userAgent = userAgent[:maxUserAgentLength]
+		}
+		meta.UserAgent = &userAgent
+	}
+
+	if ip := c.ClientIP(); ip != "" {
+		meta.IPAddress = &ip
+	}
+
+	return meta
+}
@@ -18,7 +18,7 @@ type MockAuthService struct {
 	RegisterFunc     func(*models.RegisterRequest) (*models.AuthResponse, error)
 	LoginFunc        func(*models.LoginRequest) (*models.AuthResponse, error)
 	RefreshTokenFunc func(*models.RefreshTokenRequest) (*models.AuthResponse, error)
-	LogoutFunc       func(int64) error
+	LogoutFunc       func(int64, string) error
 }
 
 func (m *MockAuthService) Register(req *models.RegisterRequest) (*models.AuthResponse, error) {
@@ -42,9 +42,9 @@ func (m *MockAuthService) RefreshToken(req *models.RefreshTokenRequest) (*models
 	return nil, errors.New("not implemented")
 }
 
-func (m *MockAuthService) Logout(userID int64) error {
+func (m *MockAuthService) Logout(userID int64, sessionID string) error {
 	if m.LogoutFunc != nil {
-		return m.LogoutFunc(userID)
+		return m.LogoutFunc(userID, sessionID)
 	}
 	return nil
 }
@@ -80,8 +80,9 @@ func TestAuthHandler_Register(t *testing.T) {
 		{
 			name: "successful registration",
 			requestBody: map[string]string{
-				"phone": "01012345678",
-				"name":  "Test User",
+				"phone":              "01012345678",
+				"name":               "Test User",
+				"verification_token": "verified",
 			},
 			setupMock: func(m *MockAuthService) {
 				m.RegisterFunc = func(req *models.RegisterRequest) (*models.AuthResponse, error) {
@@ -113,11 +114,21 @@ func TestAuthHandler_Register(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
-			name: "service error",
+			name: "missing verification token",
 			requestBody: map[string]string{
 				"phone": "01012345678",
 				"name":  "Test User",
 			},
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "service error",
+			requestBody: map[string]string{
+				"phone":              "01012345678",
+				"name":               "Test User",
+				"verification_token": "verified",
+			},
 			setupMock: func(m *MockAuthService) {
 				m.RegisterFunc = func(req *models.RegisterRequest) (*models.AuthResponse, error) {
 					return nil, errors.New("phone already exists")
@@ -181,7 +192,8 @@ func TestAuthHandler_Login(t *testing.T) {
 		{
 			name: "successful login",
 			requestBody: map[string]string{
-				"phone": "01012345678",
+				"phone":              "01012345678",
+				"verification_token": "verified",
 			},
 			setupMock: func(m *MockAuthService) {
 				m.LoginFunc = func(req *models.LoginRequest) (*models.AuthResponse, error) {
@@ -197,7 +209,8 @@ func TestAuthHandler_Login(t *testing.T) {
 		{
 			name: "user not found",
 			requestBody: map[string]string{
-				"phone": "01099999999",
+				"phone":              "01099999999",
+				"verification_token": "verified",
 			},
 			setupMock: func(m *MockAuthService) {
 				m.LoginFunc = func(req *models.LoginRequest) (*models.AuthResponse, error) {
@@ -206,6 +219,14 @@ func TestAuthHandler_Login(t *testing.T) {
 			},
 			expectedStatus: http.StatusUnauthorized,
 		},
+		{
+			name: "missing verification token",
+			requestBody: map[string]string{
+				"phone": "01012345678",
+			},
+			setupMock:      func(m *MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -255,7 +276,10 @@ func TestAuthHandler_Logout(t *testing.T) {
 			name:   "successful logout",
 			userID: int64(1),
 			setupMock: func(m *MockAuthService) {
-				m.LogoutFunc = func(userID int64) error {
+				m.LogoutFunc = func(userID int64, sessionID string) error {
+					if sessionID != "session-1" {
+						return errors.New("expected current session to be revoked")
+					}
 					return nil
 				}
 			},
@@ -271,7 +295,7 @@ func TestAuthHandler_Logout(t *testing.T) {
 			name:   "logout error",
 			userID: int64(1),
 			setupMock: func(m *MockAuthService) {
-				m.LogoutFunc = func(userID int64) error {
+				m.LogoutFunc = func(userID int64, sessionID string) error {
 					return errors.New("logout failed")
 				}
 			},
@@ -288,6 +312,7 @@ func TestAuthHandler_Logout(t *testing.T) {
 			router.POST("/logout", func(c *gin.Context) {
 				if tt.userID != nil {
 					c.Set("userID", tt.userID)
+					c.Set("sessionID", "session-1")
 				}
 
 				userID, exists := c.Get("userID")
@@ -296,7 +321,7 @@ func TestAuthHandler_Logout(t *testing.T) {
 					return
 				}
 
-				err := mockService.Logout(userID.(int64))
+				err := mockService.Logout(userID.(int64), c.GetString("sessionID"))
 				if err != nil {
 					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 					return
@@ -368,6 +393,65 @@ func TestAuthHandler_Me(t *testing.T) {
 	}
 }
 
+func TestSessionMetadata(t *testing.T) {
+	tests := []struct {
+		name         string
+		platform     string
+		userAgent    string
+		wantPlatform *string
+		wantUALength int
+	}{
+		{
+			name:         "known platform is normalized",
+			platform:     "ios",
+			userAgent:    "timingle/1.0 (iPhone)",
+			wantPlatform: strPtr("IOS"),
+			wantUALength: len("timingle/1.0 (iPhone)"),
+		},
+		{
+			name:         "unknown platform is dropped",
+			platform:     "toaster",
+			userAgent:    "curl/8.0",
+			wantPlatform: nil,
+			wantUALength: len("curl/8.0"),
+		},
+		{
+			name:         "long user agent is truncated",
+			platform:     "",
+			userAgent:    string(bytes.Repeat([]byte("a"), maxUserAgentLength+100)),
+			wantPlatform: nil,
+			wantUALength: maxUserAgentLength,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var meta *models.SessionMetadata
+			router := setupAuthRouter()
+			router.POST("/login", func(c *gin.Context) {
+				meta = sessionMetadata(c)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("POST", "/login", nil)
+			req.Header.Set("X-Client-Platform", tt.platform)
+			req.Header.Set("User-Agent", tt.userAgent)
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			if (meta.Platform == nil) != (tt.wantPlatform == nil) ||
+				(meta.Platform != nil && *meta.Platform != *tt.wantPlatform) {
+				t.Errorf("Expected platform %v, got %v", tt.wantPlatform, meta.Platform)
+			}
+			if meta.UserAgent == nil || len(*meta.UserAgent) != tt.wantUALength {
+				t.Errorf("Expected user agent of length %d, got %v", tt.wantUALength, meta.UserAgent)
+			}
+			if meta.IPAddress == nil {
+				t.Errorf("Expected client IP to be recorded")
+			}
+		})
+	}
+}
+
 // Helper function to create string pointer
 func strPtr(s string) *string {
 	return &s
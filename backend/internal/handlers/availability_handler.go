@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/services"
+)
+
+// AvailabilityHandler handles free-time search HTTP requests
+type AvailabilityHandler struct {
+	availabilityService *services.AvailabilityService
+}
+
+// NewAvailabilityHandler creates a new availability handler
+func NewAvailabilityHandler(availabilityService *services.AvailabilityService) *AvailabilityHandler {
+	return &AvailabilityHandler{
+		availabilityService: availabilityService,
+	}
+}
+
+// FindSlots returns ranked slots when the current user and all participants are free
+// POST /api/v1/availability/slots
+func (h *AvailabilityHandler) FindSlots(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req models.FindSlotsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	slots, err := h.availabilityService.FindCommonSlots(c.Request.Context(), userID.(int64), &req)
+	if errors.Is(err, services.ErrAvailabilityNotAllowed) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"slots": slots,
+		"count": len(slots),
+	})
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khchoi-tnh/timingle/internal/services"
+)
+
+const icsContentType = "text/calendar; charset=utf-8"
+
+// CalendarFeedHandler handles iCalendar export and subscription feed HTTP requests
+type CalendarFeedHandler struct {
+	feedService *services.CalendarFeedService
+}
+
+// NewCalendarFeedHandler creates a new calendar feed handler
+func NewCalendarFeedHandler(feedService *services.CalendarFeedService) *CalendarFeedHandler {
+	return &CalendarFeedHandler{
+		feedService: feedService,
+	}
+}
+
+// ExportEvent downloads a single event as an .ics file
+// GET /api/v1/events/:id/ics
+func (h *CalendarFeedHandler) ExportEvent(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	data, err := h.feedService.EventICS(eventID, userID.(int64))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("timingle-event-%d.ics", eventID)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, icsContentType, data)
+}
+
+// CreateFeed creates the current user's subscription feed URL, replacing the previous one
+// POST /api/v1/me/calendar-feed
+func (h *CalendarFeedHandler) CreateFeed(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	response, err := h.feedService.CreateFeed(userID.(int64))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetFeed returns whether the current user has a subscription feed
+// GET /api/v1/me/calendar-feed
+func (h *CalendarFeedHandler) GetFeed(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	response, err := h.feedService.GetFeed(userID.(int64))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeFeed revokes the current user's subscription feed URL
+// DELETE /api/v1/me/calendar-feed
+func (h *CalendarFeedHandler) RevokeFeed(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	err := h.feedService.RevokeFeed(userID.(int64))
+	if errors.Is(err, services.ErrCalendarFeedNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "calendar feed revoked"})
+}
+
+// Feed serves a subscription feed to calendar apps; the secret token in the URL authenticates it
+// GET /feeds/:token.ics
+func (h *CalendarFeedHandler) Feed(c *gin.Context) {
+	token, ok := strings.CutSuffix(c.Param("file"), ".ics")
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": services.ErrCalendarFeedNotFound.Error()})
+		return
+	}
+
+	data, err := h.feedService.Feed(token)
+	if errors.Is(err, services.ErrCalendarFeedNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="timingle.ics"`)
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, icsContentType, data)
+}
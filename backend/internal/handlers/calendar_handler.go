@@ -1,23 +1,27 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/khchoi-tnh/timingle/internal/models"
 	"github.com/khchoi-tnh/timingle/internal/services"
 )
 
 // CalendarHandler handles Google Calendar HTTP requests
 type CalendarHandler struct {
-	calendarService *services.CalendarService
+	calendarService     *services.CalendarService
+	calendarSyncService *services.CalendarSyncService
 }
 
 // NewCalendarHandler creates a new calendar handler
-func NewCalendarHandler(calendarService *services.CalendarService) *CalendarHandler {
+func NewCalendarHandler(calendarService *services.CalendarService, calendarSyncService *services.CalendarSyncService) *CalendarHandler {
 	return &CalendarHandler{
-		calendarService: calendarService,
+		calendarService:     calendarService,
+		calendarSyncService: calendarSyncService,
 	}
 }
 
@@ -109,7 +113,7 @@ func (h *CalendarHandler) SyncEventToCalendar(c *gin.Context) {
 	})
 }
 
-// CheckCalendarAccess checks if user has Google Calendar access
+// CheckCalendarAccess returns the user's Google Calendar access and sync status
 // GET /api/v1/calendar/status
 func (h *CalendarHandler) CheckCalendarAccess(c *gin.Context) {
 	userID, exists := c.Get("userID")
@@ -118,13 +122,65 @@ func (h *CalendarHandler) CheckCalendarAccess(c *gin.Context) {
 		return
 	}
 
-	hasAccess, err := h.calendarService.HasCalendarAccess(c.Request.Context(), userID.(int64))
+	status, err := h.calendarService.GetSyncStatus(userID.(int64))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	c.JSON(http.StatusOK, status)
+}
+
+// Watch turns on two-way sync: changes made in the user's Google Calendar are pulled back
+// POST /api/v1/calendar/watch
+func (h *CalendarHandler) Watch(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	channel, err := h.calendarSyncService.Watch(c.Request.Context(), userID.(int64))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"has_calendar_access": hasAccess,
+		"message": "calendar sync enabled",
+		"channel": channel,
 	})
 }
+
+// Unwatch turns off pulling changes from the user's Google Calendar
+// DELETE /api/v1/calendar/watch
+func (h *CalendarHandler) Unwatch(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	if err := h.calendarSyncService.Unwatch(c.Request.Context(), userID.(int64)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "calendar sync disabled"})
+}
+
+// HandleWebhook receives Google Calendar push notifications (no JWT; verified by the channel token)
+// POST /api/v1/calendar/webhook
+func (h *CalendarHandler) HandleWebhook(c *gin.Context) {
+	notification := &models.CalendarNotification{
+		ChannelID:     c.GetHeader("X-Goog-Channel-ID"),
+		Token:         c.GetHeader("X-Goog-Channel-Token"),
+		ResourceID:    c.GetHeader("X-Goog-Resource-ID"),
+		ResourceState: c.GetHeader("X-Goog-Resource-State"),
+	}
+
+	err := h.calendarSyncService.HandleNotification(notification)
+	if errors.Is(err, services.ErrUnknownCalendarChannel) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		// Google retries failed notifications with backoff
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "notification accepted"})
+}
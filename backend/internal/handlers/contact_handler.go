@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/services"
+)
+
+// ContactHandler handles contact sync HTTP requests
+type ContactHandler struct {
+	contactService *services.ContactService
+}
+
+// NewContactHandler creates a new contact handler
+func NewContactHandler(contactService *services.ContactService) *ContactHandler {
+	return &ContactHandler{
+		contactService: contactService,
+	}
+}
+
+// SyncContacts finds timingle users among the device's address book
+// POST /api/v1/contacts/sync
+// Request body: { "phone_hashes": ["<hex SHA-256 of E.164 number>", ...] }
+func (h *ContactHandler) SyncContacts(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req models.ContactSyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.contactService.Sync(c.Request.Context(), userID.(int64), &req)
+	if errors.Is(err, services.ErrContactSyncRateLimited) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/services"
+)
+
+// DeviceHandler handles push device registration HTTP requests
+type DeviceHandler struct {
+	deviceService *services.DeviceService
+}
+
+// NewDeviceHandler creates a new device handler
+func NewDeviceHandler(deviceService *services.DeviceService) *DeviceHandler {
+	return &DeviceHandler{
+		deviceService: deviceService,
+	}
+}
+
+// RegisterDevice registers a push token for the current user
+// POST /api/v1/me/devices
+func (h *DeviceHandler) RegisterDevice(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req models.RegisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	device, err := h.deviceService.RegisterDevice(userID.(int64), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, device)
+}
+
+// UnregisterDevice removes a push token of the current user
+// DELETE /api/v1/me/devices
+func (h *DeviceHandler) UnregisterDevice(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req models.UnregisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.deviceService.UnregisterDevice(userID.(int64), &req); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "device unregistered successfully"})
+}
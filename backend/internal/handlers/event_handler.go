@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/khchoi-tnh/timingle/internal/models"
@@ -42,20 +44,33 @@ func (h *EventHandler) CreateEvent(c *gin.Context) {
 }
 
 // GetEvent handles getting a single event
-// GET /api/v1/events/:id
+// GET /api/v1/events/:id?tz=viewer
+// tz returns times in the viewer's profile timezone ("viewer") or an IANA zone
 func (h *EventHandler) GetEvent(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
 	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
 		return
 	}
 
+	loc, err := h.eventService.ViewerZone(userID.(int64), c.Query("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	response, err := h.eventService.GetEvent(eventID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
+	if loc != nil {
+		response.In(loc)
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -105,18 +120,40 @@ func (h *EventHandler) DeleteEvent(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "event deleted successfully"})
 }
 
+// maxEventWindow limits how far recurring events are expanded in a single request
+const maxEventWindow = 366 * 24 * time.Hour
+
 // GetUserEvents handles getting all events for current user
-// GET /api/v1/events
+// GET /api/v1/events?status=CONFIRMED&start_time=2024-01-01T00:00:00Z&end_time=2024-02-01T00:00:00Z&tz=viewer
+// When start_time is given, recurring events are expanded into occurrences within the window
 func (h *EventHandler) GetUserEvents(c *gin.Context) {
 	userID, _ := c.Get("userID")
 	status := c.Query("status") // optional filter
 
-	response, err := h.eventService.GetUserEvents(userID.(int64), status)
+	window, err := parseEventWindow(c.Query("start_time"), c.Query("end_time"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	loc, err := h.eventService.ViewerZone(userID.(int64), c.Query("tz"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	response, err := h.eventService.GetUserEvents(userID.(int64), status, window)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if loc != nil {
+		for _, event := range response {
+			event.In(loc)
+		}
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -148,6 +185,33 @@ func (h *EventHandler) AddParticipant(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "participant added successfully"})
 }
 
+// InviteFriends handles inviting friends to an event
+// POST /api/v1/events/:id/invite-friends
+// Request body: { "friend_ids": [2, 3] }
+func (h *EventHandler) InviteFriends(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	var req models.InviteFriendsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.eventService.InviteFriends(eventID, userID.(int64), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // RemoveParticipant handles removing a participant from event
 // DELETE /api/v1/events/:id/participants/:participant_id
 func (h *EventHandler) RemoveParticipant(c *gin.Context) {
@@ -205,7 +269,16 @@ func (h *EventHandler) ConfirmEvent(c *gin.Context) {
 		return
 	}
 
-	err = h.eventService.ConfirmEvent(eventID, userID.(int64))
+	// Body is optional
+	var req models.ConfirmEventRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	err = h.eventService.ConfirmEvent(eventID, userID.(int64), &req)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -214,6 +287,28 @@ func (h *EventHandler) ConfirmEvent(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "event confirmed"})
 }
 
+// GetEventHistory returns the change history of an event, newest first
+// GET /api/v1/events/:id/history?limit=50&cursor=...
+func (h *EventHandler) GetEventHistory(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	page, err := h.eventService.GetEventHistory(eventID, userID.(int64), limit, c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
 // CancelEvent handles canceling an event
 // POST /api/v1/events/:id/cancel
 func (h *EventHandler) CancelEvent(c *gin.Context) {
@@ -253,3 +348,88 @@ func (h *EventHandler) MarkEventDone(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "event marked as done"})
 }
+
+// parseEventWindow parses the optional start_time/end_time query parameters
+// end_time defaults to one month after start_time
+func parseEventWindow(startStr, endStr string) (*models.TimeWindow, error) {
+	if startStr == "" {
+		if endStr != "" {
+			return nil, fmt.Errorf("start_time is required when end_time is given")
+		}
+		return nil, nil
+	}
+
+	startTime, err := time.Parse(time.RFC3339, startStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start_time format, use RFC3339")
+	}
+
+	endTime := startTime.AddDate(0, 1, 0)
+	if endStr != "" {
+		endTime, err = time.Parse(time.RFC3339, endStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end_time format, use RFC3339")
+		}
+	}
+
+	if !endTime.After(startTime) {
+		return nil, fmt.Errorf("end_time must be after start_time")
+	}
+	if endTime.Sub(startTime) > maxEventWindow {
+		return nil, fmt.Errorf("time window must not exceed 366 days")
+	}
+
+	return &models.TimeWindow{Start: startTime, End: endTime}, nil
+}
+
+// CancelOccurrence handles canceling a single occurrence of a recurring event
+// POST /api/v1/events/:id/occurrences/cancel
+func (h *EventHandler) CancelOccurrence(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	var req models.OccurrenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.eventService.CancelOccurrence(eventID, userID.(int64), req.OccurrenceStart)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "occurrence canceled"})
+}
+
+// RespondToOccurrence handles a participant's response to a single occurrence
+// POST /api/v1/events/:id/occurrences/respond
+func (h *EventHandler) RespondToOccurrence(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	var req models.RespondOccurrenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.eventService.RespondToOccurrence(eventID, userID.(int64), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "response recorded"})
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/services"
+)
+
+// FriendHandler handles friend HTTP requests
+type FriendHandler struct {
+	friendService *services.FriendService
+}
+
+// NewFriendHandler creates a new friend handler
+func NewFriendHandler(friendService *services.FriendService) *FriendHandler {
+	return &FriendHandler{
+		friendService: friendService,
+	}
+}
+
+// ListFriends returns the current user's friends
+// GET /api/v1/friends
+func (h *FriendHandler) ListFriends(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	friends, err := h.friendService.ListFriends(userID.(int64))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"friends": friends})
+}
+
+// RemoveFriend ends a friendship
+// DELETE /api/v1/friends/:user_id
+func (h *FriendHandler) RemoveFriend(c *gin.Context) {
+	h.handleUserAction(c, h.friendService.RemoveFriend, "friend removed successfully")
+}
+
+// ListRequests returns pending friend requests received and sent
+// GET /api/v1/friends/requests
+func (h *FriendHandler) ListRequests(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	requests, err := h.friendService.ListRequests(userID.(int64))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+// SendRequest sends a friend request
+// POST /api/v1/friends/requests
+// Request body: { "user_id": 123 }
+func (h *FriendHandler) SendRequest(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req models.SendFriendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	status, err := h.friendService.SendRequest(userID.(int64), req.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// A request to someone who already asked the user accepts theirs
+	c.JSON(http.StatusOK, gin.H{"status": status})
+}
+
+// AcceptRequest accepts a received friend request
+// POST /api/v1/friends/requests/:user_id/accept
+func (h *FriendHandler) AcceptRequest(c *gin.Context) {
+	h.handleUserAction(c, h.friendService.AcceptRequest, "friend request accepted successfully")
+}
+
+// DeclineRequest declines a received friend request
+// POST /api/v1/friends/requests/:user_id/decline
+func (h *FriendHandler) DeclineRequest(c *gin.Context) {
+	h.handleUserAction(c, h.friendService.DeclineRequest, "friend request declined successfully")
+}
+
+// CancelRequest cancels a sent friend request
+// DELETE /api/v1/friends/requests/:user_id
+func (h *FriendHandler) CancelRequest(c *gin.Context) {
+	h.handleUserAction(c, h.friendService.CancelRequest, "friend request canceled successfully")
+}
+
+// ListBlocked returns the users the current user has blocked
+// GET /api/v1/friends/blocked
+func (h *FriendHandler) ListBlocked(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	blocked, err := h.friendService.ListBlocked(userID.(int64))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"blocked": blocked})
+}
+
+// Block blocks a user
+// POST /api/v1/friends/blocked/:user_id
+func (h *FriendHandler) Block(c *gin.Context) {
+	h.handleUserAction(c, h.friendService.Block, "user blocked successfully")
+}
+
+// Unblock unblocks a user
+// DELETE /api/v1/friends/blocked/:user_id
+func (h *FriendHandler) Unblock(c *gin.Context) {
+	h.handleUserAction(c, h.friendService.Unblock, "user unblocked successfully")
+}
+
+// handleUserAction runs an action of the current user on the :user_id user
+func (h *FriendHandler) handleUserAction(c *gin.Context, action func(userID, otherID int64) error, message string) {
+	userID, _ := c.Get("userID")
+
+	otherID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	if err := action(userID.(int64), otherID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
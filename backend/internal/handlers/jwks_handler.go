@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khchoi-tnh/timingle/pkg/utils"
+)
+
+// jwksMaxAge is how long verifiers may cache the key set
+// Keys are published before they start signing, so a short cache is enough
+const jwksMaxAge = "public, max-age=300"
+
+// JWKSHandler serves the public keys access tokens are signed with
+type JWKSHandler struct {
+	jwtManager *utils.JWTManager
+}
+
+// NewJWKSHandler creates a new JWKS handler
+func NewJWKSHandler(jwtManager *utils.JWTManager) *JWKSHandler {
+	return &JWKSHandler{
+		jwtManager: jwtManager,
+	}
+}
+
+// GetJWKS returns the JSON Web Key Set of the current and upcoming signing keys
+// GET /.well-known/jwks.json
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", jwksMaxAge)
+	c.JSON(http.StatusOK, h.jwtManager.JWKS())
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/services"
+)
+
+// multipartOverhead is the room left for multipart headers and form fields on top of the file size limit
+const multipartOverhead = 1 << 20
+
+// MediaHandler handles image upload and download HTTP requests
+type MediaHandler struct {
+	mediaService   *services.MediaService
+	maxUploadBytes int64
+}
+
+// NewMediaHandler creates a new media handler
+func NewMediaHandler(mediaService *services.MediaService, maxUploadBytes int64) *MediaHandler {
+	return &MediaHandler{
+		mediaService:   mediaService,
+		maxUploadBytes: maxUploadBytes,
+	}
+}
+
+// Upload uploads an image through the API
+// POST /api/v1/media
+// Multipart form: file=<image>, purpose=AVATAR|CHAT, event_id=<id> (CHAT only)
+func (h *MediaHandler) Upload(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxUploadBytes+multipartOverhead)
+	file, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": services.ErrMediaTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+
+	var eventID *int64
+	if value := c.PostForm("event_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+			return
+		}
+		eventID = &id
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+		return
+	}
+	defer reader.Close()
+
+	purpose := models.MediaPurpose(c.PostForm("purpose"))
+	media, err := h.mediaService.Upload(c.Request.Context(), userID.(int64), purpose, eventID, reader)
+	if err != nil {
+		c.JSON(mediaErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, media)
+}
+
+// CreateUpload returns a presigned URL to upload an image to storage directly
+// POST /api/v1/media/uploads
+// Request body: { "purpose": "CHAT", "event_id": 1, "content_type": "image/jpeg", "size_bytes": 123456 }
+func (h *MediaHandler) CreateUpload(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req models.CreateUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	upload, err := h.mediaService.CreateUpload(c.Request.Context(), userID.(int64), &req)
+	if err != nil {
+		c.JSON(mediaErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, upload)
+}
+
+// CompleteUpload processes an image uploaded to a presigned URL
+// POST /api/v1/media/:id/complete
+func (h *MediaHandler) CompleteUpload(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	mediaID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid media ID"})
+		return
+	}
+
+	media, err := h.mediaService.CompleteUpload(c.Request.Context(), userID.(int64), mediaID)
+	if err != nil {
+		c.JSON(mediaErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// GetMedia returns an image
+// GET /api/v1/media/:id
+func (h *MediaHandler) GetMedia(c *gin.Context) {
+	h.serveMedia(c, false)
+}
+
+// GetThumbnail returns an image's thumbnail
+// GET /api/v1/media/:id/thumbnail
+func (h *MediaHandler) GetThumbnail(c *gin.Context) {
+	h.serveMedia(c, true)
+}
+
+// serveMedia redirects to a presigned URL or streams the object from storage
+func (h *MediaHandler) serveMedia(c *gin.Context, thumbnail bool) {
+	userID, _ := c.Get("userID")
+
+	mediaID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid media ID"})
+		return
+	}
+
+	content, err := h.mediaService.GetContent(c.Request.Context(), userID.(int64), mediaID, thumbnail)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "media not found"})
+		return
+	}
+
+	// Responses depend on the viewer's membership, so shared caches must not keep them
+	c.Header("Cache-Control", "private, max-age=300")
+	if content.RedirectURL != "" {
+		c.Redirect(http.StatusFound, content.RedirectURL)
+		return
+	}
+
+	defer content.Body.Close()
+	c.DataFromReader(http.StatusOK, -1, content.ContentType, content.Body, map[string]string{
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// mediaErrorStatus maps media service errors to HTTP status codes
+func mediaErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrMediaTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, services.ErrUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, services.ErrPresignNotSupported):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusBadRequest
+	}
+}
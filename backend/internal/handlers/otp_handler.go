@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/services"
+)
+
+// OTPHandler handles SMS verification HTTP requests
+type OTPHandler struct {
+	otpService *services.OTPService
+}
+
+// NewOTPHandler creates a new OTP handler
+func NewOTPHandler(otpService *services.OTPService) *OTPHandler {
+	return &OTPHandler{
+		otpService: otpService,
+	}
+}
+
+// RequestCode sends a verification code by SMS
+// POST /api/v1/auth/otp/request
+// Request body: { "phone": "01012345678" }
+func (h *OTPHandler) RequestCode(c *gin.Context) {
+	var req models.OTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.otpService.RequestCode(c.Request.Context(), &req, c.ClientIP())
+	if errors.Is(err, services.ErrOTPRateLimited) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// VerifyCode checks a verification code
+// POST /api/v1/auth/otp/verify
+// Request body: { "phone": "01012345678", "code": "123456" }
+// Response: verification_token to pass to /auth/register or /auth/login
+func (h *OTPHandler) VerifyCode(c *gin.Context) {
+	var req models.OTPVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.otpService.VerifyCode(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/services"
+)
+
+// PollHandler handles event time poll HTTP requests
+type PollHandler struct {
+	pollService *services.PollService
+}
+
+// NewPollHandler creates a new poll handler
+func NewPollHandler(pollService *services.PollService) *PollHandler {
+	return &PollHandler{
+		pollService: pollService,
+	}
+}
+
+// CreatePoll proposes candidate time slots for an event
+// POST /api/v1/events/:id/poll
+func (h *PollHandler) CreatePoll(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	var req models.CreatePollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	poll, err := h.pollService.CreatePoll(eventID, userID.(int64), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, poll)
+}
+
+// GetPoll returns candidate slots with their vote tally
+// GET /api/v1/events/:id/poll
+func (h *PollHandler) GetPoll(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	poll, err := h.pollService.GetPoll(eventID, userID.(int64))
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, poll)
+}
+
+// Vote records the current user's votes on candidate slots
+// POST /api/v1/events/:id/poll/votes
+func (h *PollHandler) Vote(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	var req models.VotePollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	poll, err := h.pollService.Vote(eventID, userID.(int64), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, poll)
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khchoi-tnh/timingle/internal/services"
+)
+
+// PrivacyHandler handles personal data export and account deletion HTTP requests
+type PrivacyHandler struct {
+	privacyService *services.PrivacyService
+}
+
+// NewPrivacyHandler creates a new privacy handler
+func NewPrivacyHandler(privacyService *services.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{
+		privacyService: privacyService,
+	}
+}
+
+// Export downloads the current user's personal data as a zip archive
+// POST /api/v1/me/export
+func (h *PrivacyHandler) Export(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	archive, err := h.privacyService.Export(c.Request.Context(), userID.(int64))
+	if errors.Is(err, services.ErrExportRateLimited) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("timingle-export-%s.zip", time.Now().UTC().Format("20060102"))
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+// DeleteMe schedules the current user's account deletion after the grace period
+// DELETE /api/v1/me
+func (h *PrivacyHandler) DeleteMe(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	response, err := h.privacyService.RequestDeletion(userID.(int64))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+// CancelDeletion cancels the current user's scheduled account deletion
+// POST /api/v1/me/deletion/cancel
+func (h *PrivacyHandler) CancelDeletion(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	if err := h.privacyService.CancelDeletion(userID.(int64)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "account deletion canceled successfully"})
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/services"
+)
+
+// ReminderHandler handles reminder preference HTTP requests
+type ReminderHandler struct {
+	reminderService *services.ReminderService
+}
+
+// NewReminderHandler creates a new reminder handler
+func NewReminderHandler(reminderService *services.ReminderService) *ReminderHandler {
+	return &ReminderHandler{
+		reminderService: reminderService,
+	}
+}
+
+// GetPreferences returns the current user's reminder offsets
+// GET /api/v1/me/reminders
+func (h *ReminderHandler) GetPreferences(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	minutes, err := h.reminderService.GetPreferences(userID.(int64))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"minutes": minutes})
+}
+
+// UpdatePreferences replaces the current user's reminder offsets
+// PUT /api/v1/me/reminders
+func (h *ReminderHandler) UpdatePreferences(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req models.UpdateReminderPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	minutes, err := h.reminderService.UpdatePreferences(userID.(int64), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"minutes": minutes})
+}
@@ -82,6 +82,32 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 	go client.WritePump()
 }
 
+// HandleUserWebSocket handles the user-level WebSocket channel (notifications)
+// The channel is receive-only; incoming messages are ignored
+// GET /ws/user
+func (h *WebSocketHandler) HandleUserWebSocket(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	// Upgrade to WebSocket
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade failed: %v", err)
+		return
+	}
+
+	client := ws.NewClient(h.hub, conn, userID.(int64), 0)
+
+	// Register client
+	h.hub.RegisterClient(client)
+
+	go client.ReadPump(func([]byte) {})
+	go client.WritePump()
+}
+
 func (h *WebSocketHandler) handleIncomingMessage(userID, eventID int64, data []byte) {
 	var wsMsg models.WSMessage
 	if err := json.Unmarshal(data, &wsMsg); err != nil {
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/khchoi-tnh/timingle/internal/models"
 	"github.com/khchoi-tnh/timingle/internal/repositories"
 	"github.com/khchoi-tnh/timingle/pkg/utils"
 )
@@ -46,10 +47,47 @@ func AuthMiddleware(jwtManager *utils.JWTManager, userRepo *repositories.UserRep
 			return
 		}
 
+		// Suspended and deleted accounts keep valid tokens until they expire
+		if !user.IsActive() {
+			c.JSON(http.StatusForbidden, gin.H{"error": inactiveUserError(user)})
+			c.Abort()
+			return
+		}
+
 		// Set user info in context
 		c.Set("userID", user.ID)
 		c.Set("user", user)
 		c.Set("role", user.Role)
+		c.Set("sessionID", claims.SessionID)
+
+		c.Next()
+	}
+}
+
+// inactiveUserError returns the error message for a suspended or deleted user
+func inactiveUserError(user *models.User) string {
+	if user.Status == models.UserStatusDeleted {
+		return "account deleted"
+	}
+	return "account suspended"
+}
+
+// RequireRole creates a middleware allowing only users with one of the given roles
+// It must run after AuthMiddleware, which sets the role in the context
+func RequireRole(roles ...models.UserRole) gin.HandlerFunc {
+	allowed := make(map[models.UserRole]bool, len(roles))
+	for _, role := range roles {
+		allowed[role] = true
+	}
+
+	return func(c *gin.Context) {
+		role, _ := c.Get("role")
+		userRole, _ := role.(models.UserRole)
+		if !allowed[userRole] {
+			c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+			c.Abort()
+			return
+		}
 
 		c.Next()
 	}
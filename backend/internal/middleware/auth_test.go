@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		role       interface{} // Set by AuthMiddleware; nil when it did not run
+		wantStatus int
+	}{
+		{name: "admin allowed", role: models.UserRoleAdmin, wantStatus: http.StatusOK},
+		{name: "super admin allowed", role: models.UserRoleSuperAdmin, wantStatus: http.StatusOK},
+		{name: "user forbidden", role: models.UserRoleUser, wantStatus: http.StatusForbidden},
+		{name: "missing role forbidden", role: nil, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				if tt.role != nil {
+					c.Set("role", tt.role)
+				}
+			})
+			admin := router.Group("/admin", RequireRole(models.UserRoleAdmin, models.UserRoleSuperAdmin))
+			admin.GET("/ping", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/ping", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
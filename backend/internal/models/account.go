@@ -0,0 +1,19 @@
+package models
+
+// LinkedAccountsResponse lists the ways a user can log in
+type LinkedAccountsResponse struct {
+	Phone    *string                 `json:"phone,omitempty"` // Set when SMS OTP login is available
+	Accounts []*OAuthAccountResponse `json:"accounts"`
+}
+
+// MergeAccountsRequest asks to merge another timingle account into the current one
+// The access token proves the caller can log in to the other account
+type MergeAccountsRequest struct {
+	AccessToken string `json:"access_token" binding:"required"`
+}
+
+// MergeAccountsResponse represents the result of an account merge
+type MergeAccountsResponse struct {
+	MergedUserID int64         `json:"merged_user_id"`
+	User         *UserResponse `json:"user"`
+}
@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+// AttendanceStatus represents a participant's attendance at an event
+type AttendanceStatus string
+
+const (
+	AttendanceCheckedIn AttendanceStatus = "CHECKED_IN"
+	AttendanceLate      AttendanceStatus = "LATE"
+	AttendanceNoShow    AttendanceStatus = "NO_SHOW"
+	AttendanceExcused   AttendanceStatus = "EXCUSED"
+)
+
+// IsValid checks if the attendance status is valid
+func (s AttendanceStatus) IsValid() bool {
+	switch s {
+	case AttendanceCheckedIn, AttendanceLate, AttendanceNoShow, AttendanceExcused:
+		return true
+	}
+	return false
+}
+
+// CheckInMethod represents how an attendance record was created
+type CheckInMethod string
+
+const (
+	CheckInMethodCreator  CheckInMethod = "CREATOR"  // Confirmed by the event creator
+	CheckInMethodQR       CheckInMethod = "QR"       // Participant scanned the event QR code
+	CheckInMethodGeofence CheckInMethod = "GEOFENCE" // Participant was inside the event geofence
+	CheckInMethodSystem   CheckInMethod = "SYSTEM"   // Marked automatically when the event is done
+)
+
+// EventAttendance represents a participant's attendance record for an event
+type EventAttendance struct {
+	EventID         int64            `json:"event_id" db:"event_id"`
+	UserID          int64            `json:"user_id" db:"user_id"`
+	OccurrenceStart *time.Time       `json:"occurrence_start,omitempty" db:"occurrence_start"` // Set for occurrences of recurring events
+	Status          AttendanceStatus `json:"status" db:"status"`
+	Method          *CheckInMethod   `json:"method,omitempty" db:"method"`
+	CheckedInAt     *time.Time       `json:"checked_in_at,omitempty" db:"checked_in_at"`
+	RecordedBy      *int64           `json:"recorded_by,omitempty" db:"recorded_by"`
+	Note            *string          `json:"note,omitempty" db:"note"`
+	CreatedAt       time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// EventGeofence represents the area in which participants can check in by location
+type EventGeofence struct {
+	EventID      int64     `json:"event_id" db:"event_id"`
+	Latitude     float64   `json:"latitude" db:"latitude"`
+	Longitude    float64   `json:"longitude" db:"longitude"`
+	RadiusMeters int       `json:"radius_meters" db:"radius_meters"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CheckInRequest represents a participant's self check-in
+type CheckInRequest struct {
+	Method          CheckInMethod `json:"method" binding:"required"` // QR or GEOFENCE
+	QRToken         string        `json:"qr_token,omitempty"`
+	Latitude        *float64      `json:"latitude,omitempty"`
+	Longitude       *float64      `json:"longitude,omitempty"`
+	OccurrenceStart *time.Time    `json:"occurrence_start,omitempty"` // Required for recurring events
+}
+
+// RecordAttendanceRequest represents the creator recording a participant's attendance
+type RecordAttendanceRequest struct {
+	Status          AttendanceStatus `json:"status" binding:"required"`
+	Note            *string          `json:"note,omitempty"`
+	OccurrenceStart *time.Time       `json:"occurrence_start,omitempty"` // Required for recurring events
+}
+
+// SetGeofenceRequest represents a request to set the check-in area of an event
+type SetGeofenceRequest struct {
+	Latitude     *float64 `json:"latitude" binding:"required"`
+	Longitude    *float64 `json:"longitude" binding:"required"`
+	RadiusMeters int      `json:"radius_meters,omitempty"`
+}
+
+// CheckInQRResponse contains the token to encode in the event's check-in QR code
+// The token rotates; the creator's screen fetches a new one when it expires.
+type CheckInQRResponse struct {
+	EventID   int64     `json:"event_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
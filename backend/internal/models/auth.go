@@ -5,19 +5,28 @@ import (
 )
 
 // RefreshToken represents a refresh token in the database
+// Only the SHA-256 hash of the token is stored. Each use rotates the token
+// within its family; a rotated token presented again revokes the family.
+// A family is one login session, and carries the device it was used from.
 type RefreshToken struct {
-	ID        int64     `json:"id" db:"id"`
-	UserID    int64     `json:"user_id" db:"user_id"`
-	Token     string    `json:"token" db:"token"`
-	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID         int64      `json:"id" db:"id"`
+	UserID     int64      `json:"user_id" db:"user_id"`
+	TokenHash  string     `json:"-" db:"token_hash"`
+	FamilyID   string     `json:"family_id" db:"family_id"`
+	Platform   *string    `json:"platform,omitempty" db:"platform"` // ANDROID, IOS, WEB
+	UserAgent  *string    `json:"user_agent,omitempty" db:"user_agent"`
+	IPAddress  *string    `json:"ip_address,omitempty" db:"ip_address"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	RotatedAt  *time.Time `json:"rotated_at,omitempty" db:"rotated_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	LastUsedAt time.Time  `json:"last_used_at" db:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
 }
 
 // LoginRequest represents login request (using phone-based authentication)
 type LoginRequest struct {
-	Phone string `json:"phone" binding:"required"`
-	// In production, this would include verification code
-	// VerificationCode string `json:"verification_code" binding:"required"`
+	Phone             string `json:"phone" binding:"required"`
+	VerificationToken string `json:"verification_token" binding:"required"` // From POST /auth/otp/verify
 }
 
 // AuthResponse represents authentication response with tokens
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// FindSlotsRequest represents a request for common free time across users
+type FindSlotsRequest struct {
+	ParticipantIDs  []int64   `json:"participant_ids" binding:"required"`
+	DurationMinutes int       `json:"duration_minutes" binding:"required,min=5,max=1440"`
+	RangeStart      time.Time `json:"range_start" binding:"required"`
+	RangeEnd        time.Time `json:"range_end" binding:"required"`
+	WorkdayStart    *string   `json:"workday_start,omitempty"` // "HH:MM", overrides server default
+	WorkdayEnd      *string   `json:"workday_end,omitempty"`   // "HH:MM", overrides server default
+	Limit           int       `json:"limit,omitempty"`
+}
+
+// CandidateSlot represents a suggested time when every participant is free
+// Score is higher for slots closer to the middle of everyone's working day
+type CandidateSlot struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Score     float64   `json:"score"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// CalendarFeed is a user's secret iCalendar subscription feed
+// Only the SHA-256 of the token is stored; the URL is shown once when the feed is created
+type CalendarFeed struct {
+	UserID         int64      `json:"user_id" db:"user_id"`
+	TokenHash      string     `json:"-" db:"token_hash"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty" db:"last_accessed_at"`
+}
+
+// CalendarFeedResponse describes a user's subscription feed
+// URL and WebcalURL are only set right after the feed is created
+type CalendarFeedResponse struct {
+	Active         bool       `json:"active"`
+	URL            string     `json:"url,omitempty"`        // https://.../feeds/{token}.ics
+	WebcalURL      string     `json:"webcal_url,omitempty"` // webcal://.../feeds/{token}.ics (opens the calendar app)
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+}
@@ -0,0 +1,97 @@
+package models
+
+import "time"
+
+// CalendarSyncChannel is a user's Google Calendar push notification channel and incremental sync state
+type CalendarSyncChannel struct {
+	ID           int64         `json:"id" db:"id"`
+	UserID       int64         `json:"user_id" db:"user_id"`
+	Provider     OAuthProvider `json:"provider" db:"provider"`
+	CalendarID   string        `json:"calendar_id" db:"calendar_id"`
+	ChannelID    string        `json:"-" db:"channel_id"`
+	ResourceID   *string       `json:"-" db:"resource_id"`
+	Token        string        `json:"-" db:"token"` // Echoed by Google in X-Goog-Channel-Token
+	ExpiresAt    time.Time     `json:"expires_at" db:"expires_at"`
+	SyncToken    *string       `json:"-" db:"sync_token"` // nil until the first full sync
+	LastSyncedAt *time.Time    `json:"last_synced_at,omitempty" db:"last_synced_at"`
+	CreatedAt    time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// CalendarSyncState is the state of a participant's calendar copy of an event
+type CalendarSyncState string
+
+const (
+	CalendarSyncStateSynced CalendarSyncState = "SYNCED" // The copy matches the event
+	CalendarSyncStateFailed CalendarSyncState = "FAILED" // The last change could not be applied to the copy
+)
+
+// CalendarEventMapping links a timingle event to one participant's copy of it in an external calendar
+type CalendarEventMapping struct {
+	ID              int64             `json:"id" db:"id"`
+	EventID         int64             `json:"event_id" db:"event_id"`
+	UserID          int64             `json:"user_id" db:"user_id"`
+	Provider        OAuthProvider     `json:"provider" db:"provider"`
+	CalendarID      string            `json:"calendar_id" db:"calendar_id"`
+	ExternalEventID string            `json:"external_event_id" db:"external_event_id"`
+	SyncState       CalendarSyncState `json:"sync_state" db:"sync_state"`
+	ETag            *string           `json:"-" db:"etag"` // ETag of the copy as last written by timingle
+	LastSyncedAt    *time.Time        `json:"last_synced_at,omitempty" db:"last_synced_at"`
+	LastError       *string           `json:"last_error,omitempty" db:"last_error"`
+	ErrorCount      int               `json:"error_count" db:"error_count"`
+	CreatedAt       time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// CalendarNotification is a Google Calendar push notification, read from the X-Goog-* headers
+type CalendarNotification struct {
+	ChannelID     string // X-Goog-Channel-ID
+	Token         string // X-Goog-Channel-Token
+	ResourceID    string // X-Goog-Resource-ID
+	ResourceState string // X-Goog-Resource-State: sync (channel created), exists, not_exists
+}
+
+// CalendarSyncResult summarizes one incremental sync of a user's calendar
+type CalendarSyncResult struct {
+	Changed  int `json:"changed"`  // Google events returned by the sync
+	Applied  int `json:"applied"`  // timingle events updated from Google
+	Unlinked int `json:"unlinked"` // Copies deleted in Google, no longer kept in sync
+}
+
+// CalendarSyncJobKind is the kind of work a calendar sync job does
+type CalendarSyncJobKind string
+
+const (
+	CalendarSyncJobUpdateCopy CalendarSyncJobKind = "UPDATE_COPY" // Write an event change to one member's copy
+	CalendarSyncJobDeleteCopy CalendarSyncJobKind = "DELETE_COPY" // Delete one member's copy of a deleted event
+	CalendarSyncJobPull       CalendarSyncJobKind = "PULL"        // Pull changes from a user's calendar (sync token)
+	CalendarSyncJobReconcile  CalendarSyncJobKind = "RECONCILE"   // Bring all of a user's copies up to date, then pull
+)
+
+// CalendarSyncJob is a calendar sync job on the CALENDAR_SYNC stream, run by the worker
+type CalendarSyncJob struct {
+	Kind    CalendarSyncJobKind `json:"kind"`
+	UserID  int64               `json:"user_id"`
+	EventID int64               `json:"event_id,omitempty"`
+	// The copy to delete for DELETE_COPY; its mapping is deleted with the event
+	CalendarID      string `json:"calendar_id,omitempty"`
+	ExternalEventID string `json:"external_event_id,omitempty"`
+}
+
+// CalendarCopyCounts counts a user's event copies by sync state
+type CalendarCopyCounts struct {
+	Synced int `json:"synced"`
+	Failed int `json:"failed"`
+}
+
+// CalendarSyncStatus is a user's calendar sync status
+type CalendarSyncStatus struct {
+	HasCalendarAccess bool               `json:"has_calendar_access"`
+	NeedsReconsent    bool               `json:"needs_reconsent"` // Google access was revoked; sign in with Google again
+	WatchEnabled      bool               `json:"watch_enabled"`   // Changes made in Google are pulled back
+	WatchExpiresAt    *time.Time         `json:"watch_expires_at,omitempty"`
+	LastPulledAt      *time.Time         `json:"last_pulled_at,omitempty"`
+	LastSyncedAt      *time.Time         `json:"last_synced_at,omitempty"` // Last successful write to a copy
+	Copies            CalendarCopyCounts `json:"copies"`
+	LastError         *string            `json:"last_error,omitempty"` // Error of the most recently failed copy
+}
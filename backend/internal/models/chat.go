@@ -30,13 +30,31 @@ type EventHistoryEntry struct {
 	ChangeID   uuid.UUID         `json:"change_id"`
 	ActorID    int64             `json:"actor_id"`
 	ActorName  string            `json:"actor_name"`
-	ChangeType string            `json:"change_type"` // CREATED, UPDATED, CONFIRMED, CANCELED
+	ChangeType string            `json:"change_type"` // See HistoryChange* constants
 	FieldName  string            `json:"field_name"`
 	OldValue   string            `json:"old_value"`
 	NewValue   string            `json:"new_value"`
 	Metadata   map[string]string `json:"metadata,omitempty"`
 }
 
+// Event history change types
+const (
+	HistoryChangeCreated            = "CREATED"
+	HistoryChangeUpdated            = "UPDATED"
+	HistoryChangeParticipantAdded   = "PARTICIPANT_ADDED"
+	HistoryChangeParticipantRemoved = "PARTICIPANT_REMOVED"
+	HistoryChangeConfirmed          = "CONFIRMED"
+	HistoryChangeCanceled           = "CANCELED"
+	HistoryChangeDone               = "DONE"
+)
+
+// EventHistoryPage represents a page of event history, newest first
+// NextCursor is empty on the last page
+type EventHistoryPage struct {
+	Entries    []*EventHistoryEntry `json:"entries"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
 // SendMessageRequest represents a request to send a chat message
 type SendMessageRequest struct {
 	EventID     int64      `json:"event_id" binding:"required"`
@@ -54,7 +72,9 @@ type GetMessagesRequest struct {
 
 // WSMessage represents a WebSocket message format
 type WSMessage struct {
-	Type    string      `json:"type"` // "message", "typing", "system"
-	Message string      `json:"message,omitempty"`
-	ReplyTo *uuid.UUID  `json:"reply_to,omitempty"`
+	Type        string     `json:"type"` // "message", "typing", "system"
+	Message     string     `json:"message,omitempty"`
+	MessageType string     `json:"message_type,omitempty"` // text (default), image
+	Attachments []string   `json:"attachments,omitempty"`  // Uploaded media IDs (message_type image)
+	ReplyTo     *uuid.UUID `json:"reply_to,omitempty"`
 }
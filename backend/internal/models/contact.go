@@ -0,0 +1,31 @@
+package models
+
+// ContactRelation describes how a matched contact relates to the current user
+type ContactRelation string
+
+const (
+	ContactRelationNone            ContactRelation = "NONE"
+	ContactRelationFriend          ContactRelation = "FRIEND"
+	ContactRelationRequestSent     ContactRelation = "REQUEST_SENT"
+	ContactRelationRequestReceived ContactRelation = "REQUEST_RECEIVED"
+	ContactRelationBlocked         ContactRelation = "BLOCKED" // Blocked by the current user
+)
+
+// ContactSyncRequest uploads address book numbers as lowercase hex SHA-256 of their E.164 form
+type ContactSyncRequest struct {
+	PhoneHashes []string `json:"phone_hashes" binding:"required,min=1"`
+}
+
+// ContactMatch is a timingle user found among the uploaded contacts
+type ContactMatch struct {
+	PhoneHash       string          `json:"phone_hash"`
+	UserID          int64           `json:"user_id"`
+	Name            *string         `json:"name,omitempty"`
+	ProfileImageURL *string         `json:"profile_image_url,omitempty"`
+	Relation        ContactRelation `json:"relation"`
+}
+
+// ContactSyncResponse lists the uploaded contacts that use timingle
+type ContactSyncResponse struct {
+	Matches []*ContactMatch `json:"matches"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// PushProvider represents a push notification delivery service
+type PushProvider string
+
+const (
+	PushProviderFCM  PushProvider = "FCM"
+	PushProviderAPNs PushProvider = "APNS"
+)
+
+// IsValid checks if the push provider is supported
+func (p PushProvider) IsValid() bool {
+	return p == PushProviderFCM || p == PushProviderAPNs
+}
+
+// DeviceToken represents a device registered for push notifications
+type DeviceToken struct {
+	ID         int64        `json:"id" db:"id"`
+	UserID     int64        `json:"user_id" db:"user_id"`
+	Provider   PushProvider `json:"provider" db:"provider"`
+	Platform   *string      `json:"platform,omitempty" db:"platform"` // ANDROID, IOS, WEB
+	Token      string       `json:"token" db:"token"`
+	CreatedAt  time.Time    `json:"created_at" db:"created_at"`
+	LastSeenAt time.Time    `json:"last_seen_at" db:"last_seen_at"`
+}
+
+// RegisterDeviceRequest represents a device token registration
+type RegisterDeviceRequest struct {
+	Token    string       `json:"token" binding:"required"`
+	Provider PushProvider `json:"provider" binding:"required"` // FCM, APNS
+	Platform *string      `json:"platform,omitempty"`          // ANDROID, IOS, WEB
+}
+
+// UnregisterDeviceRequest represents a device token removal (e.g. on logout)
+type UnregisterDeviceRequest struct {
+	Token string `json:"token" binding:"required"`
+}
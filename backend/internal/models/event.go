@@ -14,19 +14,133 @@ const (
 	EventStatusDone      EventStatus = "DONE"
 )
 
+// RecurrenceScope selects which occurrences of a recurring event an update applies to
+type RecurrenceScope string
+
+const (
+	RecurrenceScopeThis             RecurrenceScope = "THIS"
+	RecurrenceScopeThisAndFollowing RecurrenceScope = "THIS_AND_FOLLOWING"
+	RecurrenceScopeAll              RecurrenceScope = "ALL"
+)
+
 // Event represents an event/appointment in the system
 type Event struct {
-	ID               int64       `json:"id" db:"id"`
-	Title            string      `json:"title" db:"title"`
-	Description      *string     `json:"description,omitempty" db:"description"`
-	StartTime        time.Time   `json:"start_time" db:"start_time"`
-	EndTime          time.Time   `json:"end_time" db:"end_time"`
-	Location         *string     `json:"location,omitempty" db:"location"`
-	CreatorID        int64       `json:"creator_id" db:"creator_id"`
-	Status           EventStatus `json:"status" db:"status"`
-	GoogleCalendarID *string     `json:"google_calendar_id,omitempty" db:"google_calendar_id"` // Google Calendar 연동 ID
-	CreatedAt        time.Time   `json:"created_at" db:"created_at"`
-	UpdatedAt        time.Time   `json:"updated_at" db:"updated_at"`
+	ID          int64       `json:"id" db:"id"`
+	Title       string      `json:"title" db:"title"`
+	Description *string     `json:"description,omitempty" db:"description"`
+	StartTime   time.Time   `json:"start_time" db:"start_time"`
+	EndTime     time.Time   `json:"end_time" db:"end_time"`
+	Location    *string     `json:"location,omitempty" db:"location"`
+	CreatorID   int64       `json:"creator_id" db:"creator_id"`
+	Status      EventStatus `json:"status" db:"status"`
+	// 시간대 / 종일 일정
+	Timezone string `json:"timezone" db:"timezone"` // IANA zone recurrences are expanded in
+	AllDay   bool   `json:"all_day" db:"all_day"`   // StartTime/EndTime are UTC midnights of the dates (end exclusive)
+	// 반복 일정 (RFC 5545 RRULE/EXDATE)
+	RecurrenceRule    *string     `json:"recurrence_rule,omitempty" db:"recurrence_rule"`
+	RecurrenceExDates []time.Time `json:"recurrence_exdates,omitempty" db:"recurrence_exdates"`
+	ParentEventID     *int64      `json:"parent_event_id,omitempty" db:"parent_event_id"` // "이후 모든 일정" 수정 시 분리된 원본 시리즈
+	CreatedAt         time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// IsRecurring reports whether the event has a recurrence rule
+func (e *Event) IsRecurring() bool {
+	return e.RecurrenceRule != nil && *e.RecurrenceRule != ""
+}
+
+// Zone returns the event's time zone, UTC when unknown
+func (e *Event) Zone() *time.Location {
+	return LoadZone(e.Timezone)
+}
+
+// SeriesStart returns the start time recurrences are anchored at: the wall-clock
+// start in the event's zone, so a 09:00 series stays at 09:00 across DST changes.
+// All-day events are anchored at UTC midnight, which has no DST.
+func (e *Event) SeriesStart() time.Time {
+	if e.AllDay {
+		return e.StartTime.UTC()
+	}
+	return e.StartTime.In(e.Zone())
+}
+
+// StartIn returns when an (occurrence) start begins for someone in loc
+// All-day events begin at local midnight of their date; timed events at the same instant everywhere
+func (e *Event) StartIn(start time.Time, loc *time.Location) time.Time {
+	if !e.AllDay {
+		return start
+	}
+	year, month, day := start.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+// LoadZone loads an IANA time zone, falling back to UTC for empty or unknown names
+func LoadZone(name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// DateOf returns the calendar date of t (in t's own location) as UTC midnight,
+// the form all-day event times are stored in
+func DateOf(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// IsExcluded reports whether an occurrence start is listed in EXDATE
+func (e *Event) IsExcluded(occurrenceStart time.Time) bool {
+	for _, exdate := range e.RecurrenceExDates {
+		if exdate.Equal(occurrenceStart) {
+			return true
+		}
+	}
+	return false
+}
+
+// EventOccurrenceOverride represents a change to a single occurrence of a recurring event
+// OccurrenceStart is the original start time of the occurrence (RECURRENCE-ID)
+type EventOccurrenceOverride struct {
+	EventID         int64      `json:"event_id" db:"event_id"`
+	OccurrenceStart time.Time  `json:"occurrence_start" db:"occurrence_start"`
+	Title           *string    `json:"title,omitempty" db:"title"`
+	Description     *string    `json:"description,omitempty" db:"description"`
+	StartTime       *time.Time `json:"start_time,omitempty" db:"start_time"`
+	EndTime         *time.Time `json:"end_time,omitempty" db:"end_time"`
+	Location        *string    `json:"location,omitempty" db:"location"`
+	IsCanceled      bool       `json:"is_canceled" db:"is_canceled"`
+	UpdatedBy       *int64     `json:"updated_by,omitempty" db:"updated_by"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// OccurrenceResponse represents a participant's response to a single occurrence
+type OccurrenceResponse struct {
+	EventID         int64     `json:"event_id" db:"event_id"`
+	OccurrenceStart time.Time `json:"occurrence_start" db:"occurrence_start"`
+	UserID          int64     `json:"user_id" db:"user_id"`
+	Status          string    `json:"status" db:"status"` // ACCEPTED, DECLINED
+	RespondedAt     time.Time `json:"responded_at" db:"responded_at"`
+}
+
+// TimeWindow represents a [Start, End) time range used to list events
+type TimeWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Overlaps reports whether [start, end) overlaps the window
+func (w *TimeWindow) Overlaps(start, end time.Time) bool {
+	if !end.After(start) {
+		// Zero-length events overlap if they start inside the window
+		return !start.Before(w.Start) && start.Before(w.End)
+	}
+	return start.Before(w.End) && end.After(w.Start)
 }
 
 // EventParticipant represents a participant in an event
@@ -39,37 +153,67 @@ type EventParticipant struct {
 
 // CreateEventRequest represents event creation request
 type CreateEventRequest struct {
-	Title         string    `json:"title" binding:"required"`
-	Description   *string   `json:"description,omitempty"`
-	StartTime     time.Time `json:"start_time" binding:"required"`
-	EndTime       time.Time `json:"end_time" binding:"required"`
-	Location      *string   `json:"location,omitempty"`
-	ParticipantIDs []int64  `json:"participant_ids,omitempty"`
+	Title             string      `json:"title" binding:"required"`
+	Description       *string     `json:"description,omitempty"`
+	StartTime         time.Time   `json:"start_time" binding:"required"`
+	EndTime           time.Time   `json:"end_time" binding:"required"`
+	Location          *string     `json:"location,omitempty"`
+	Timezone          *string     `json:"timezone,omitempty"` // IANA zone, defaults to the creator's profile timezone
+	AllDay            bool        `json:"all_day,omitempty"`  // only the dates of start_time/end_time are used; end is exclusive
+	ParticipantIDs    []int64     `json:"participant_ids,omitempty"`
+	RecurrenceRule    *string     `json:"recurrence_rule,omitempty"`    // e.g. "FREQ=WEEKLY;BYDAY=TU"
+	RecurrenceExDates []time.Time `json:"recurrence_exdates,omitempty"` // excluded occurrence starts
 }
 
 // UpdateEventRequest represents event update request
 type UpdateEventRequest struct {
-	Title       *string     `json:"title,omitempty"`
-	Description *string     `json:"description,omitempty"`
-	StartTime   *time.Time  `json:"start_time,omitempty"`
-	EndTime     *time.Time  `json:"end_time,omitempty"`
-	Location    *string     `json:"location,omitempty"`
+	Title       *string      `json:"title,omitempty"`
+	Description *string      `json:"description,omitempty"`
+	StartTime   *time.Time   `json:"start_time,omitempty"`
+	EndTime     *time.Time   `json:"end_time,omitempty"`
+	Location    *string      `json:"location,omitempty"`
 	Status      *EventStatus `json:"status,omitempty"`
+	Timezone    *string      `json:"timezone,omitempty"`
+	AllDay      *bool        `json:"all_day,omitempty"`
+	// 반복 일정 수정 범위
+	RecurrenceRule    *string          `json:"recurrence_rule,omitempty"` // empty string removes recurrence
+	RecurrenceExDates []time.Time      `json:"recurrence_exdates,omitempty"`
+	Scope             *RecurrenceScope `json:"scope,omitempty"`            // THIS, THIS_AND_FOLLOWING, ALL (default)
+	OccurrenceStart   *time.Time       `json:"occurrence_start,omitempty"` // required for THIS / THIS_AND_FOLLOWING
+}
+
+// OccurrenceRequest identifies a single occurrence of a recurring event
+type OccurrenceRequest struct {
+	OccurrenceStart time.Time `json:"occurrence_start" binding:"required"`
+}
+
+// RespondOccurrenceRequest represents a participant's response to a single occurrence
+type RespondOccurrenceRequest struct {
+	OccurrenceStart time.Time `json:"occurrence_start" binding:"required"`
+	Status          string    `json:"status" binding:"required"` // ACCEPTED, DECLINED
 }
 
 // EventResponse represents event data in API responses
 type EventResponse struct {
-	ID           int64              `json:"id"`
-	Title        string             `json:"title"`
-	Description  *string            `json:"description,omitempty"`
-	StartTime    time.Time          `json:"start_time"`
-	EndTime      time.Time          `json:"end_time"`
-	Location     *string            `json:"location,omitempty"`
-	Creator      *UserResponse      `json:"creator"`
-	Participants []*UserResponse    `json:"participants,omitempty"`
-	Status       EventStatus        `json:"status"`
-	CreatedAt    time.Time          `json:"created_at"`
-	UpdatedAt    time.Time          `json:"updated_at"`
+	ID                  int64                 `json:"id"`
+	Title               string                `json:"title"`
+	Description         *string               `json:"description,omitempty"`
+	StartTime           time.Time             `json:"start_time"`
+	EndTime             time.Time             `json:"end_time"`
+	Location            *string               `json:"location,omitempty"`
+	Timezone            string                `json:"timezone"`
+	AllDay              bool                  `json:"all_day"`
+	StartDate           string                `json:"start_date,omitempty"` // all-day events: "2006-01-02"
+	EndDate             string                `json:"end_date,omitempty"`   // all-day events: exclusive end date
+	Creator             *UserResponse         `json:"creator"`
+	Participants        []*UserResponse       `json:"participants,omitempty"`
+	Status              EventStatus           `json:"status"`
+	RecurrenceRule      *string               `json:"recurrence_rule,omitempty"`
+	RecurrenceExDates   []time.Time           `json:"recurrence_exdates,omitempty"`
+	OccurrenceStart     *time.Time            `json:"occurrence_start,omitempty"` // set on expanded occurrences
+	OccurrenceResponses []*OccurrenceResponse `json:"occurrence_responses,omitempty"`
+	CreatedAt           time.Time             `json:"created_at"`
+	UpdatedAt           time.Time             `json:"updated_at"`
 }
 
 // EventWithParticipants represents an event with its participants
@@ -82,17 +226,23 @@ type EventWithParticipants struct {
 // ToEventResponse converts EventWithParticipants to EventResponse
 func (e *EventWithParticipants) ToEventResponse() *EventResponse {
 	response := &EventResponse{
-		ID:          e.Event.ID,
-		Title:       e.Event.Title,
-		Description: e.Event.Description,
-		StartTime:   e.Event.StartTime,
-		EndTime:     e.Event.EndTime,
-		Location:    e.Event.Location,
-		Status:      e.Event.Status,
-		CreatedAt:   e.Event.CreatedAt,
-		UpdatedAt:   e.Event.UpdatedAt,
+		ID:                e.Event.ID,
+		Title:             e.Event.Title,
+		Description:       e.Event.Description,
+		StartTime:         e.Event.StartTime,
+		EndTime:           e.Event.EndTime,
+		Location:          e.Event.Location,
+		Timezone:          e.Event.Timezone,
+		AllDay:            e.Event.AllDay,
+		Status:            e.Event.Status,
+		RecurrenceRule:    e.Event.RecurrenceRule,
+		RecurrenceExDates: e.Event.RecurrenceExDates,
+		CreatedAt:         e.Event.CreatedAt,
+		UpdatedAt:         e.Event.UpdatedAt,
 	}
 
+	response.SetDates()
+
 	if e.Creator != nil {
 		response.Creator = e.Creator.ToUserResponse()
 	}
@@ -106,3 +256,42 @@ func (e *EventWithParticipants) ToEventResponse() *EventResponse {
 
 	return response
 }
+
+// EventInvitedMessage is published to the EVENTS stream (subject event.invited)
+// when users are added to an event by its creator
+type EventInvitedMessage struct {
+	EventID     int64   `json:"event_id"`
+	Title       string  `json:"title"`
+	InviterID   int64   `json:"inviter_id"`
+	InviterName string  `json:"inviter_name"`
+	UserIDs     []int64 `json:"user_ids"`
+}
+
+// SetDates fills StartDate/EndDate of all-day events from StartTime/EndTime
+func (r *EventResponse) SetDates() {
+	if !r.AllDay {
+		return
+	}
+	r.StartDate = r.StartTime.UTC().Format("2006-01-02")
+	r.EndDate = r.EndTime.UTC().Format("2006-01-02")
+}
+
+// In converts the times of a timed event to loc; all-day events keep their dates
+func (r *EventResponse) In(loc *time.Location) {
+	if r.AllDay {
+		return
+	}
+	r.StartTime = r.StartTime.In(loc)
+	r.EndTime = r.EndTime.In(loc)
+	if r.OccurrenceStart != nil {
+		occurrenceStart := r.OccurrenceStart.In(loc)
+		r.OccurrenceStart = &occurrenceStart
+	}
+	if len(r.RecurrenceExDates) > 0 {
+		exdates := make([]time.Time, len(r.RecurrenceExDates))
+		for i, exdate := range r.RecurrenceExDates {
+			exdates[i] = exdate.In(loc)
+		}
+		r.RecurrenceExDates = exdates
+	}
+}
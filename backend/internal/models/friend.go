@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// FriendshipStatus represents the state of a friendship row
+type FriendshipStatus string
+
+const (
+	FriendshipStatusPending  FriendshipStatus = "PENDING"
+	FriendshipStatusAccepted FriendshipStatus = "ACCEPTED"
+	FriendshipStatusBlocked  FriendshipStatus = "BLOCKED"
+)
+
+// Friendship is a directed relation from UserID to FriendID
+// A request is a single PENDING row from the requester; accepting it makes two
+// ACCEPTED rows, one per direction. A block is a BLOCKED row from the blocker.
+type Friendship struct {
+	ID        int64            `json:"id" db:"id"`
+	UserID    int64            `json:"user_id" db:"user_id"`
+	FriendID  int64            `json:"friend_id" db:"friend_id"`
+	Status    FriendshipStatus `json:"status" db:"status"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// Friend is another user in a friend, request or block list
+type Friend struct {
+	UserID          int64            `json:"user_id"`
+	Name            *string          `json:"name,omitempty"`
+	ProfileImageURL *string          `json:"profile_image_url,omitempty"`
+	Status          FriendshipStatus `json:"status"`
+	Since           time.Time        `json:"since"` // When the request was sent, accepted or the block was made
+}
+
+// FriendRequestsResponse lists pending friend requests of the current user
+type FriendRequestsResponse struct {
+	Incoming []*Friend `json:"incoming"`
+	Outgoing []*Friend `json:"outgoing"`
+}
+
+// SendFriendRequest represents a friend request to another user
+type SendFriendRequest struct {
+	UserID int64 `json:"user_id" binding:"required"`
+}
+
+// InviteFriendsRequest represents inviting friends to an event
+type InviteFriendsRequest struct {
+	FriendIDs []int64 `json:"friend_ids" binding:"required,min=1,max=100"`
+}
+
+// InviteFriendsResponse lists the friends added to the event
+type InviteFriendsResponse struct {
+	Invited []int64 `json:"invited"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MediaPurpose represents what an uploaded media object is used for
+type MediaPurpose string
+
+const (
+	MediaPurposeAvatar MediaPurpose = "AVATAR" // Profile image, visible to every user
+	MediaPurposeChat   MediaPurpose = "CHAT"   // Chat image, visible to the event's members
+)
+
+// MediaStatus represents the upload state of a media object
+type MediaStatus string
+
+const (
+	MediaStatusPending MediaStatus = "PENDING" // Presigned upload URL issued, not completed
+	MediaStatusReady   MediaStatus = "READY"   // Processed (EXIF stripped, thumbnail created)
+)
+
+// MaxChatAttachments is the number of images one chat message can carry
+const MaxChatAttachments = 10
+
+// MediaObject represents an uploaded image
+type MediaObject struct {
+	ID           uuid.UUID    `json:"id" db:"id"`
+	OwnerID      int64        `json:"owner_id" db:"owner_id"`
+	Purpose      MediaPurpose `json:"purpose" db:"purpose"`
+	EventID      *int64       `json:"event_id,omitempty" db:"event_id"`
+	Status       MediaStatus  `json:"status" db:"status"`
+	ContentType  string       `json:"content_type" db:"content_type"`
+	SizeBytes    int64        `json:"size_bytes" db:"size_bytes"`
+	Width        int          `json:"width" db:"width"`
+	Height       int          `json:"height" db:"height"`
+	HasThumbnail bool         `json:"has_thumbnail" db:"has_thumbnail"`
+	CreatedAt    time.Time    `json:"created_at" db:"created_at"`
+}
+
+// CreateUploadRequest represents a request for a presigned upload URL
+type CreateUploadRequest struct {
+	Purpose     MediaPurpose `json:"purpose" binding:"required"`
+	EventID     *int64       `json:"event_id,omitempty"` // Required for CHAT
+	ContentType string       `json:"content_type" binding:"required"`
+	SizeBytes   int64        `json:"size_bytes" binding:"required,min=1"`
+}
+
+// MediaResponse represents an uploaded image in API responses
+// URL and ThumbnailURL require the Authorization header
+type MediaResponse struct {
+	ID           uuid.UUID    `json:"id"`
+	Purpose      MediaPurpose `json:"purpose"`
+	EventID      *int64       `json:"event_id,omitempty"`
+	Status       MediaStatus  `json:"status"`
+	ContentType  string       `json:"content_type"`
+	SizeBytes    int64        `json:"size_bytes"`
+	Width        int          `json:"width,omitempty"`
+	Height       int          `json:"height,omitempty"`
+	URL          string       `json:"url,omitempty"`
+	ThumbnailURL string       `json:"thumbnail_url,omitempty"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+// UploadURLResponse represents a presigned upload
+// The client PUTs the file to UploadURL with Headers, then completes the upload
+type UploadURLResponse struct {
+	Media     *MediaResponse    `json:"media"`
+	UploadURL string            `json:"upload_url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// NotificationType represents the kind of an inbox notification
+type NotificationType string
+
+const (
+	NotificationEventInvited      NotificationType = "EVENT_INVITED"
+	NotificationEventTimeChanged  NotificationType = "EVENT_TIME_CHANGED"
+	NotificationEventCanceled     NotificationType = "EVENT_CANCELED"
+	NotificationParticipantJoined NotificationType = "PARTICIPANT_JOINED"
+	NotificationFriendRequested   NotificationType = "FRIEND_REQUESTED"
+	NotificationFriendAccepted    NotificationType = "FRIEND_ACCEPTED"
+)
+
+// Notification represents an entry in a user's in-app inbox
+// Data holds a snapshot for rendering (event title, actor name, new times)
+type Notification struct {
+	ID        int64             `json:"id" db:"id"`
+	UserID    int64             `json:"user_id" db:"user_id"`
+	Type      NotificationType  `json:"type" db:"type"`
+	EventID   *int64            `json:"event_id,omitempty" db:"event_id"`
+	ActorID   *int64            `json:"actor_id,omitempty" db:"actor_id"`
+	Data      map[string]string `json:"data" db:"data"`
+	ReadAt    *time.Time        `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+}
+
+// NotificationPage represents a page of notifications, newest first
+// NextCursor is empty on the last page
+type NotificationPage struct {
+	Notifications []*Notification `json:"notifications"`
+	NextCursor    string          `json:"next_cursor,omitempty"`
+	UnreadCount   int             `json:"unread_count"`
+}
+
+// NotificationMessage is sent on the user-level WebSocket channel when a notification is created
+type NotificationMessage struct {
+	Type         string        `json:"type"` // "notification"
+	Notification *Notification `json:"notification"`
+}
@@ -26,6 +26,7 @@ type OAuthAccount struct {
 	RefreshToken *string    `json:"-" db:"refresh_token"` // 토큰 갱신용 (JSON 노출 안함)
 	TokenExpiry  *time.Time `json:"-" db:"token_expiry"`  // Access Token 만료 시간
 	Scopes       []string   `json:"-" db:"scopes"`        // 부여된 OAuth Scope 목록
+	ReconsentRequiredAt *time.Time `json:"-" db:"reconsent_required_at"` // refresh token 폐기 감지 시각
 	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
 }
@@ -41,6 +42,11 @@ func (o *OAuthAccount) HasCalendarScope() bool {
 	return false
 }
 
+// NeedsReconsent reports whether the user must sign in with Google again before the tokens can be used
+func (o *OAuthAccount) NeedsReconsent() bool {
+	return o.ReconsentRequiredAt != nil
+}
+
 // IsTokenExpired checks if the access token is expired
 func (o *OAuthAccount) IsTokenExpired() bool {
 	if o.TokenExpiry == nil {
@@ -82,6 +88,32 @@ type GoogleTokenPayload struct {
 	Expiration    int64  `json:"exp"`           // Expiration timestamp
 }
 
+// ApplePrivateRelayDomain is the domain of Apple's "Hide My Email" relay addresses
+const ApplePrivateRelayDomain = "privaterelay.appleid.com"
+
+// AppleLoginRequest represents Sign in with Apple login request from Flutter
+// Apple only returns the user's name to the app on the first authorization,
+// so the client forwards it alongside the identity token when it has it
+type AppleLoginRequest struct {
+	IDToken    string `json:"id_token" binding:"required"`
+	Nonce      string `json:"nonce,omitempty"`       // Raw nonce; the token carries its SHA-256
+	GivenName  string `json:"given_name,omitempty"`  // First login only
+	FamilyName string `json:"family_name,omitempty"` // First login only
+	Platform   string `json:"platform"`              // web, ios, android
+}
+
+// AppleTokenPayload represents the decoded Apple identity token payload
+type AppleTokenPayload struct {
+	Issuer         string `json:"iss"`              // https://appleid.apple.com
+	Audience       string `json:"aud"`              // Bundle ID or Services ID
+	Subject        string `json:"sub"`              // Unique, stable Apple user ID
+	Email          string `json:"email"`            // Real or private relay email; may be empty
+	EmailVerified  bool   `json:"email_verified"`
+	IsPrivateEmail bool   `json:"is_private_email"` // Email is a relay address
+	IssuedAt       int64  `json:"iat"`
+	Expiration     int64  `json:"exp"`
+}
+
 // OAuthAccountResponse represents OAuth account info in API responses
 type OAuthAccountResponse struct {
 	Provider   OAuthProvider `json:"provider"`
@@ -0,0 +1,24 @@
+package models
+
+// OTPRequest represents a request to send an SMS verification code
+type OTPRequest struct {
+	Phone string `json:"phone" binding:"required"`
+}
+
+// OTPRequestResponse represents a sent verification code
+type OTPRequestResponse struct {
+	ExpiresIn   int64 `json:"expires_in"`   // seconds until the code expires
+	ResendAfter int64 `json:"resend_after"` // seconds until another code can be requested
+}
+
+// OTPVerifyRequest represents a verification code check
+type OTPVerifyRequest struct {
+	Phone string `json:"phone" binding:"required"`
+	Code  string `json:"code" binding:"required"`
+}
+
+// OTPVerifyResponse carries the proof of phone ownership used by register and login
+type OTPVerifyResponse struct {
+	VerificationToken string `json:"verification_token"`
+	ExpiresIn         int64  `json:"expires_in"` // seconds
+}
@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+// VoteChoice represents a participant's answer for a candidate time slot
+type VoteChoice string
+
+const (
+	VoteChoiceYes   VoteChoice = "YES"
+	VoteChoiceMaybe VoteChoice = "MAYBE"
+	VoteChoiceNo    VoteChoice = "NO"
+)
+
+// IsValid checks if the vote choice is one of the supported values
+func (v VoteChoice) IsValid() bool {
+	return v == VoteChoiceYes || v == VoteChoiceMaybe || v == VoteChoiceNo
+}
+
+// TimeSlot represents a candidate time proposed for an event
+type TimeSlot struct {
+	ID        int64     `json:"id" db:"id"`
+	EventID   int64     `json:"event_id" db:"event_id"`
+	StartTime time.Time `json:"start_time" db:"start_time"`
+	EndTime   time.Time `json:"end_time" db:"end_time"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// TimeSlotVote represents a participant's vote for a candidate time slot
+type TimeSlotVote struct {
+	SlotID  int64      `json:"slot_id" db:"slot_id"`
+	UserID  int64      `json:"user_id" db:"user_id"`
+	Choice  VoteChoice `json:"choice" db:"choice"`
+	VotedAt time.Time  `json:"voted_at" db:"voted_at"`
+}
+
+// TimeSlotInput represents a candidate time in a poll creation request
+type TimeSlotInput struct {
+	StartTime time.Time `json:"start_time" binding:"required"`
+	EndTime   time.Time `json:"end_time" binding:"required"`
+}
+
+// CreatePollRequest represents a request to propose candidate times for an event
+type CreatePollRequest struct {
+	Slots []TimeSlotInput `json:"slots" binding:"required"`
+}
+
+// SlotVoteInput represents a single vote in a vote request
+type SlotVoteInput struct {
+	SlotID int64      `json:"slot_id" binding:"required"`
+	Choice VoteChoice `json:"choice" binding:"required"` // YES, MAYBE, NO
+}
+
+// VotePollRequest represents a participant's votes on one or more slots
+type VotePollRequest struct {
+	Votes []SlotVoteInput `json:"votes" binding:"required"`
+}
+
+// ConfirmEventRequest represents an event confirmation request
+// SlotID picks a poll slot; when omitted the winning slot is used if a poll exists
+type ConfirmEventRequest struct {
+	SlotID *int64 `json:"slot_id,omitempty"`
+}
+
+// TimeSlotResult represents a candidate slot with its vote tally
+type TimeSlotResult struct {
+	Slot  *TimeSlot       `json:"slot"`
+	Yes   int             `json:"yes"`
+	Maybe int             `json:"maybe"`
+	No    int             `json:"no"`
+	Score int             `json:"score"` // YES = 2, MAYBE = 1
+	Votes []*TimeSlotVote `json:"votes"`
+}
+
+// PollResponse represents the candidate slots of an event, best slot first
+type PollResponse struct {
+	EventID int64             `json:"event_id"`
+	Slots   []*TimeSlotResult `json:"slots"`
+}
+
+// PollUpdateMessage is broadcast to the event's WebSocket room when votes change
+type PollUpdateMessage struct {
+	Type    string        `json:"type"` // "poll_updated"
+	EventID int64         `json:"event_id"`
+	UserID  int64         `json:"user_id"`
+	Poll    *PollResponse `json:"poll"`
+}
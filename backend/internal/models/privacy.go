@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// DeletedUserName replaces the sender name of a deleted user's chat messages
+const DeletedUserName = "탈퇴한 사용자"
+
+// ParticipationRecord is a user's participation in an event, as exported with their personal data
+type ParticipationRecord struct {
+	EventID      int64      `json:"event_id"`
+	EventTitle   string     `json:"event_title"`
+	StartTime    time.Time  `json:"start_time"`
+	Status       string     `json:"status"` // PENDING, ACCEPTED, DECLINED
+	InviteMethod *string    `json:"invite_method,omitempty"`
+	InvitedAt    *time.Time `json:"invited_at,omitempty"`
+	RespondedAt  *time.Time `json:"responded_at,omitempty"`
+	Confirmed    bool       `json:"confirmed"`
+	ConfirmedAt  *time.Time `json:"confirmed_at,omitempty"`
+}
+
+// ExportedProfile is profile.json in a personal data export
+type ExportedProfile struct {
+	User           *UserResponse           `json:"user"`
+	LinkedAccounts []*OAuthAccountResponse `json:"linked_accounts"`
+	ExportedAt     time.Time               `json:"exported_at"`
+}
+
+// AccountDeletionResponse reports when a requested account deletion takes effect
+type AccountDeletionResponse struct {
+	ScheduledAt time.Time `json:"scheduled_at"`
+	Message     string    `json:"message"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// ReminderStatus represents the delivery state of a scheduled reminder
+type ReminderStatus string
+
+const (
+	ReminderStatusPending ReminderStatus = "PENDING"
+	ReminderStatusSent    ReminderStatus = "SENT"
+	ReminderStatusSkipped ReminderStatus = "SKIPPED" // Event started before the reminder was sent
+)
+
+// EventReminder represents a reminder scheduled for one user and one event (occurrence)
+type EventReminder struct {
+	ID            int64          `json:"id" db:"id"`
+	EventID       int64          `json:"event_id" db:"event_id"`
+	UserID        int64          `json:"user_id" db:"user_id"`
+	EventStart    time.Time      `json:"event_start" db:"event_start"`
+	MinutesBefore int            `json:"minutes_before" db:"minutes_before"`
+	RemindAt      time.Time      `json:"remind_at" db:"remind_at"`
+	Status        ReminderStatus `json:"status" db:"status"`
+	SentAt        *time.Time     `json:"sent_at,omitempty" db:"sent_at"`
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+}
+
+// ReminderJob is published to the EVENTS stream (subject event.reminder) when a reminder is due
+type ReminderJob struct {
+	ReminderID    int64     `json:"reminder_id"`
+	EventID       int64     `json:"event_id"`
+	UserID        int64     `json:"user_id"`
+	Title         string    `json:"title"`
+	Location      *string   `json:"location,omitempty"`
+	EventStart    time.Time `json:"event_start"`
+	AllDay        bool      `json:"all_day,omitempty"`
+	Timezone      string    `json:"timezone,omitempty"` // Recipient's zone the start is shown in
+	MinutesBefore int       `json:"minutes_before"`
+}
+
+// UpdateReminderPreferencesRequest represents a user's reminder preferences
+type UpdateReminderPreferencesRequest struct {
+	Minutes []int64 `json:"minutes"` // Minutes before start; empty disables reminders
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// SessionPlatforms are the accepted X-Client-Platform header values
+var SessionPlatforms = map[string]bool{
+	"ANDROID": true,
+	"IOS":     true,
+	"WEB":     true,
+}
+
+// SessionMetadata describes the client a login or token refresh came from
+type SessionMetadata struct {
+	Platform  *string // ANDROID, IOS, WEB
+	UserAgent *string
+	IPAddress *string
+}
+
+// Session represents a logged-in device (one refresh token family)
+type Session struct {
+	ID         string    `json:"id"` // Refresh token family ID
+	Platform   *string   `json:"platform,omitempty"`
+	UserAgent  *string   `json:"user_agent,omitempty"`
+	IPAddress  *string   `json:"ip_address,omitempty"`
+	Current    bool      `json:"current"` // Session of the access token making the request
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
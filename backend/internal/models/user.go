@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 )
 
@@ -8,33 +9,97 @@ import (
 type UserRole string
 
 const (
-	UserRoleUser     UserRole = "USER"
-	UserRoleBusiness UserRole = "BUSINESS"
+	UserRoleUser       UserRole = "USER"
+	UserRoleBusiness   UserRole = "BUSINESS"
+	UserRoleAdmin      UserRole = "ADMIN"
+	UserRoleSuperAdmin UserRole = "SUPER_ADMIN"
 )
 
+// UserStatus represents account status types
+type UserStatus string
+
+const (
+	UserStatusActive    UserStatus = "ACTIVE"
+	UserStatusSuspended UserStatus = "SUSPENDED"
+	UserStatusDeleted   UserStatus = "DELETED"
+)
+
+// SupportedLanguages are the language codes the app is localized in
+var SupportedLanguages = map[string]bool{
+	"ko": true,
+	"en": true,
+	"ja": true,
+}
+
+// SupportedInterests are the interests a user can pick for their profile
+var SupportedInterests = map[string]bool{
+	"운동":  true,
+	"독서":  true,
+	"음악":  true,
+	"영화":  true,
+	"여행":  true,
+	"맛집":  true,
+	"카페":  true,
+	"게임":  true,
+	"스터디": true,
+	"등산":  true,
+	"요리":  true,
+	"사진":  true,
+	"공연":  true,
+	"전시":  true,
+	"봉사":  true,
+}
+
+// MaxInterests is the number of interests a profile can list
+const MaxInterests = 10
+
+// OAuthPhonePrefix marks the placeholder phone of users who signed up with an OAuth provider
+const OAuthPhonePrefix = "oauth_"
+
+// DeletedPhonePrefix marks the placeholder phone of users whose account was deleted and anonymized
+const DeletedPhonePrefix = "deleted_"
+
 // User represents a user in the system
 type User struct {
-	ID              int64     `json:"id" db:"id"`
-	Phone           string    `json:"phone" db:"phone"`
-	Name            *string   `json:"name,omitempty" db:"name"`
-	Email           *string   `json:"email,omitempty" db:"email"`
-	ProfileImageURL *string   `json:"profile_image_url,omitempty" db:"profile_image_url"`
-	Region          *string   `json:"region,omitempty" db:"region"`
-	Interests       []string  `json:"interests,omitempty" db:"interests"`
-	Timezone        string    `json:"timezone" db:"timezone"`
-	Language        string    `json:"language" db:"language"`
-	Role            UserRole  `json:"role" db:"role"`
-	CreatedAt       time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+	ID              int64      `json:"id" db:"id"`
+	Phone           string     `json:"phone" db:"phone"`
+	Name            *string    `json:"name,omitempty" db:"name"`
+	Email           *string    `json:"email,omitempty" db:"email"`
+	ProfileImageURL *string    `json:"profile_image_url,omitempty" db:"profile_image_url"`
+	Region          *string    `json:"region,omitempty" db:"region"`
+	Interests       []string   `json:"interests,omitempty" db:"interests"`
+	Timezone        string     `json:"timezone" db:"timezone"`
+	Language        string     `json:"language" db:"language"`
+	Role            UserRole   `json:"role" db:"role"`
+	Status          UserStatus `json:"status" db:"status"`
+	// ReliabilityScore is 0-100 from attendance history, nil without history
+	ReliabilityScore *float64 `json:"reliability_score,omitempty" db:"reliability_score"`
+	ReminderMinutes  []int64  `json:"reminder_minutes,omitempty" db:"reminder_minutes"` // Minutes before start
+	// DeletionScheduledAt is when a requested account deletion takes effect, nil without a request
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty" db:"deletion_scheduled_at"`
+	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// HasPhoneLogin reports whether the user can log in with a phone number (SMS OTP)
+func (u *User) HasPhoneLogin() bool {
+	return !strings.HasPrefix(u.Phone, OAuthPhonePrefix) && !strings.HasPrefix(u.Phone, DeletedPhonePrefix)
+}
+
+// IsActive reports whether the user may use the API (not suspended or deleted)
+func (u *User) IsActive() bool {
+	return u.Status != UserStatusSuspended && u.Status != UserStatusDeleted
 }
 
 // RegisterRequest represents user registration request
 type RegisterRequest struct {
-	Phone string `json:"phone" binding:"required"`
-	Name  string `json:"name" binding:"required"`
+	Phone             string `json:"phone" binding:"required"`
+	Name              string `json:"name" binding:"required"`
+	VerificationToken string `json:"verification_token" binding:"required"` // From POST /auth/otp/verify
 }
 
 // UpdateUserRequest represents user profile update request
+// Omitted fields are unchanged; an empty email, region or profile image clears it
 type UpdateUserRequest struct {
 	Name            *string  `json:"name,omitempty"`
 	Email           *string  `json:"email,omitempty"`
@@ -47,32 +112,70 @@ type UpdateUserRequest struct {
 
 // UserResponse represents user data in API responses (excludes sensitive fields)
 type UserResponse struct {
-	ID              int64     `json:"id"`
-	Phone           string    `json:"phone"`
-	Name            *string   `json:"name,omitempty"`
-	Email           *string   `json:"email,omitempty"`
-	ProfileImageURL *string   `json:"profile_image_url,omitempty"`
-	Region          *string   `json:"region,omitempty"`
-	Interests       []string  `json:"interests,omitempty"`
-	Timezone        string    `json:"timezone"`
-	Language        string    `json:"language"`
-	Role            UserRole  `json:"role"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID               int64    `json:"id"`
+	Phone            string   `json:"phone"`
+	Name             *string  `json:"name,omitempty"`
+	Email            *string  `json:"email,omitempty"`
+	ProfileImageURL  *string  `json:"profile_image_url,omitempty"`
+	Region           *string  `json:"region,omitempty"`
+	Interests        []string `json:"interests,omitempty"`
+	Timezone         string   `json:"timezone"`
+	Language         string   `json:"language"`
+	Role             UserRole `json:"role"`
+	ReliabilityScore *float64 `json:"reliability_score,omitempty"`
+	// DeletionScheduledAt is set while an account deletion is pending and can still be canceled
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// PublicUserResponse represents another user's profile
+// Phone is only shown to friends (and the user themselves)
+type PublicUserResponse struct {
+	ID               int64     `json:"id"`
+	Phone            *string   `json:"phone,omitempty"`
+	Name             *string   `json:"name,omitempty"`
+	ProfileImageURL  *string   `json:"profile_image_url,omitempty"`
+	Region           *string   `json:"region,omitempty"`
+	Interests        []string  `json:"interests,omitempty"`
+	ReliabilityScore *float64  `json:"reliability_score,omitempty"`
+	IsFriend         bool      `json:"is_friend"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// ToPublicResponse converts User to PublicUserResponse, including the phone when showPhone is set
+func (u *User) ToPublicResponse(isFriend, showPhone bool) *PublicUserResponse {
+	response := &PublicUserResponse{
+		ID:               u.ID,
+		Name:             u.Name,
+		ProfileImageURL:  u.ProfileImageURL,
+		Region:           u.Region,
+		Interests:        u.Interests,
+		ReliabilityScore: u.ReliabilityScore,
+		IsFriend:         isFriend,
+		CreatedAt:        u.CreatedAt,
+	}
+	if showPhone && u.HasPhoneLogin() {
+		phone := u.Phone
+		response.Phone = &phone
+	}
+	return response
 }
 
 // ToUserResponse converts User to UserResponse
 func (u *User) ToUserResponse() *UserResponse {
 	return &UserResponse{
-		ID:              u.ID,
-		Phone:           u.Phone,
-		Name:            u.Name,
-		Email:           u.Email,
-		ProfileImageURL: u.ProfileImageURL,
-		Region:          u.Region,
-		Interests:       u.Interests,
-		Timezone:        u.Timezone,
-		Language:        u.Language,
-		Role:            u.Role,
-		CreatedAt:       u.CreatedAt,
+		ID:                  u.ID,
+		Phone:               u.Phone,
+		Name:                u.Name,
+		Email:               u.Email,
+		ProfileImageURL:     u.ProfileImageURL,
+		Region:              u.Region,
+		Interests:           u.Interests,
+		Timezone:            u.Timezone,
+		Language:            u.Language,
+		Role:                u.Role,
+		ReliabilityScore:    u.ReliabilityScore,
+		DeletionScheduledAt: u.DeletionScheduledAt,
+		CreatedAt:           u.CreatedAt,
 	}
 }
@@ -0,0 +1,202 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+const (
+	// DefaultAPNsBaseURL is the production APNs endpoint
+	// Use https://api.sandbox.push.apple.com for development builds
+	DefaultAPNsBaseURL = "https://api.push.apple.com"
+
+	// apnsTokenLifetime stays below Apple's one hour limit; Apple rejects
+	// tokens that are refreshed more often than every 20 minutes
+	apnsTokenLifetime = 50 * time.Minute
+)
+
+// APNsProvider sends push notifications through Apple Push Notification service
+// using token-based (.p8 key) authentication
+type APNsProvider struct {
+	key     *ecdsa.PrivateKey
+	keyID   string
+	teamID  string
+	topic   string // App bundle ID
+	baseURL string
+	client  *http.Client
+
+	mu        sync.Mutex
+	token     string
+	tokenTime time.Time
+}
+
+// NewAPNsProvider creates an APNs provider from a PEM encoded .p8 signing key
+func NewAPNsProvider(keyPEM []byte, keyID, teamID, topic, baseURL string, client *http.Client) (*APNsProvider, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("invalid APNs key: no PEM data")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid APNs key: %w", err)
+	}
+
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid APNs key: not an ECDSA key")
+	}
+
+	if baseURL == "" {
+		baseURL = DefaultAPNsBaseURL
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &APNsProvider{
+		key:     key,
+		keyID:   keyID,
+		teamID:  teamID,
+		topic:   topic,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  client,
+	}, nil
+}
+
+// NewAPNsProviderFromFile creates an APNs provider from a .p8 key file
+func NewAPNsProviderFromFile(keyFile, keyID, teamID, topic, baseURL string) (*APNsProvider, error) {
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read APNs key: %w", err)
+	}
+	return NewAPNsProvider(keyPEM, keyID, teamID, topic, baseURL, nil)
+}
+
+// Name returns the provider name
+func (p *APNsProvider) Name() models.PushProvider {
+	return models.PushProviderAPNs
+}
+
+// authToken returns the cached provider token, signing a new one when it is about to expire
+func (p *APNsProvider) authToken(now time.Time) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && now.Sub(p.tokenTime) < apnsTokenLifetime {
+		return p.token, nil
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": p.teamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = p.keyID
+
+	signed, err := token.SignedString(p.key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign APNs token: %w", err)
+	}
+
+	p.token = signed
+	p.tokenTime = now
+	return signed, nil
+}
+
+// resetAuthToken forces a new provider token on the next request
+func (p *APNsProvider) resetAuthToken() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.token = ""
+}
+
+type apnsAlert struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type apnsAps struct {
+	Alert apnsAlert `json:"alert"`
+	Sound string    `json:"sound"`
+}
+
+type apnsErrorResponse struct {
+	Reason string `json:"reason"`
+}
+
+// Send delivers a message to one device
+func (p *APNsProvider) Send(ctx context.Context, msg *Message) error {
+	// Custom data goes next to "aps" at the top level of the payload
+	payload := map[string]interface{}{
+		"aps": apnsAps{
+			Alert: apnsAlert{Title: msg.Title, Body: msg.Body},
+			Sound: "default",
+		},
+	}
+	for key, value := range msg.Data {
+		if key != "aps" {
+			payload[key] = value
+		}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal APNs payload: %w", err)
+	}
+
+	token, err := p.authToken(time.Now())
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/3/device/%s", p.baseURL, msg.Token)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create APNs request: %w", err)
+	}
+	req.Header.Set("Authorization", "bearer "+token)
+	req.Header.Set("apns-topic", p.topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send APNs message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	respBody, _ := io.ReadAll(resp.Body)
+	var apnsErr apnsErrorResponse
+	_ = json.Unmarshal(respBody, &apnsErr)
+
+	switch {
+	case resp.StatusCode == http.StatusGone,
+		apnsErr.Reason == "BadDeviceToken",
+		apnsErr.Reason == "Unregistered",
+		apnsErr.Reason == "DeviceTokenNotForTopic":
+		return ErrInvalidToken
+	case apnsErr.Reason == "ExpiredProviderToken":
+		p.resetAuthToken()
+	}
+
+	return fmt.Errorf("APNs returned %d: %s", resp.StatusCode, apnsErr.Reason)
+}
@@ -0,0 +1,228 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/repositories"
+	ws "github.com/khchoi-tnh/timingle/internal/websocket"
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	// Subjects of the domain events turned into pushes
+	// Kept in sync with services.ReminderSubject and services.InviteSubject
+	chatSubject     = "chat.message.*"
+	reminderSubject = "event.reminder"
+	inviteSubject   = "event.invited"
+
+	maxPushBodyLength = 120
+	pushTimeout       = 30 * time.Second
+)
+
+// Consumer turns chat messages, reminders and invites from JetStream into pushes
+type Consumer struct {
+	js        nats.JetStreamContext
+	notifier  *Notifier
+	eventRepo *repositories.EventRepository
+	presence  *ws.Presence
+	subs      []*nats.Subscription
+}
+
+// NewConsumer creates a new push consumer
+// presence may be nil, in which case chat pushes go to every member
+func NewConsumer(
+	js nats.JetStreamContext,
+	notifier *Notifier,
+	eventRepo *repositories.EventRepository,
+	presence *ws.Presence,
+) *Consumer {
+	return &Consumer{
+		js:        js,
+		notifier:  notifier,
+		eventRepo: eventRepo,
+		presence:  presence,
+	}
+}
+
+// Start subscribes durable consumers for each domain event
+func (c *Consumer) Start() error {
+	handlers := []struct {
+		subject string
+		durable string
+		handle  func(ctx context.Context, data []byte) error
+	}{
+		{chatSubject, "push-chat", c.handleChatMessage},
+		{reminderSubject, "push-reminder", c.handleReminder},
+		{inviteSubject, "push-invite", c.handleInvite},
+	}
+
+	for _, h := range handlers {
+		handle := h.handle
+		sub, err := c.js.Subscribe(h.subject, func(msg *nats.Msg) {
+			ctx, cancel := context.WithTimeout(context.Background(), pushTimeout)
+			defer cancel()
+
+			if err := handle(ctx, msg.Data); err != nil {
+				log.Printf("Failed to push %s: %v", msg.Subject, err)
+				msg.Nak()
+				return
+			}
+			msg.Ack()
+		}, nats.Durable(h.durable), nats.ManualAck())
+		if err != nil {
+			c.Stop()
+			return fmt.Errorf("failed to subscribe to %s: %w", h.subject, err)
+		}
+		c.subs = append(c.subs, sub)
+	}
+
+	return nil
+}
+
+// Stop removes the subscriptions; durable consumers keep their position
+func (c *Consumer) Stop() {
+	for _, sub := range c.subs {
+		sub.Unsubscribe()
+	}
+	c.subs = nil
+}
+
+// handleChatMessage pushes a chat message to members who are not looking at the chat
+func (c *Consumer) handleChatMessage(ctx context.Context, data []byte) error {
+	var msg models.ChatMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		// Malformed payloads will never succeed; drop them
+		log.Printf("Failed to unmarshal chat message: %v", err)
+		return nil
+	}
+
+	event, err := c.eventRepo.FindByID(msg.EventID)
+	if err != nil {
+		return err
+	}
+
+	participantIDs, err := c.eventRepo.FindParticipants(msg.EventID)
+	if err != nil {
+		return err
+	}
+
+	recipients := []int64{}
+	for _, userID := range append([]int64{event.CreatorID}, participantIDs...) {
+		if userID == msg.SenderID || c.isInRoom(ctx, msg.EventID, userID) {
+			continue
+		}
+		recipients = append(recipients, userID)
+	}
+
+	return c.notifier.Notify(ctx, recipients, &Notification{
+		Title: event.Title,
+		Body:  truncate(msg.SenderName+": "+msg.Message, maxPushBodyLength),
+		Data: map[string]string{
+			"type":     "chat_message",
+			"event_id": strconv.FormatInt(msg.EventID, 10),
+		},
+	})
+}
+
+// isInRoom reports whether a user has the event chat open; errors count as absent
+// so a Redis outage results in extra pushes rather than missed ones
+func (c *Consumer) isInRoom(ctx context.Context, eventID, userID int64) bool {
+	if c.presence == nil {
+		return false
+	}
+
+	inRoom, err := c.presence.IsInRoom(ctx, eventID, userID)
+	if err != nil {
+		fmt.Printf("Warning: failed to check presence of user %d: %v\n", userID, err)
+		return false
+	}
+	return inRoom
+}
+
+// handleReminder pushes a due event reminder
+func (c *Consumer) handleReminder(ctx context.Context, data []byte) error {
+	var job models.ReminderJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		log.Printf("Failed to unmarshal reminder: %v", err)
+		return nil
+	}
+
+	body := "Starts " + formatLeadTime(job.MinutesBefore) + " (" + formatReminderStart(&job) + ")"
+	if job.Location != nil && *job.Location != "" {
+		body += " at " + *job.Location
+	}
+
+	return c.notifier.Notify(ctx, []int64{job.UserID}, &Notification{
+		Title: job.Title,
+		Body:  body,
+		Data: map[string]string{
+			"type":     "event_reminder",
+			"event_id": strconv.FormatInt(job.EventID, 10),
+		},
+	})
+}
+
+// handleInvite pushes an invitation to newly added participants
+func (c *Consumer) handleInvite(ctx context.Context, data []byte) error {
+	var invite models.EventInvitedMessage
+	if err := json.Unmarshal(data, &invite); err != nil {
+		log.Printf("Failed to unmarshal invite: %v", err)
+		return nil
+	}
+
+	inviter := invite.InviterName
+	if inviter == "" {
+		inviter = "Someone"
+	}
+
+	return c.notifier.Notify(ctx, invite.UserIDs, &Notification{
+		Title: invite.Title,
+		Body:  inviter + " invited you",
+		Data: map[string]string{
+			"type":     "event_invited",
+			"event_id": strconv.FormatInt(invite.EventID, 10),
+		},
+	})
+}
+
+// formatLeadTime describes a reminder offset, e.g. "in 1 hour" or "tomorrow"
+func formatLeadTime(minutes int) string {
+	switch {
+	case minutes == 24*60:
+		return "tomorrow"
+	case minutes%(24*60) == 0:
+		return fmt.Sprintf("in %d days", minutes/(24*60))
+	case minutes == 60:
+		return "in 1 hour"
+	case minutes%60 == 0:
+		return fmt.Sprintf("in %d hours", minutes/60)
+	case minutes == 1:
+		return "in 1 minute"
+	default:
+		return fmt.Sprintf("in %d minutes", minutes)
+	}
+}
+
+// formatReminderStart describes when an event starts in the recipient's zone, e.g. "Mar 1, 09:00"
+func formatReminderStart(job *models.ReminderJob) string {
+	start := job.EventStart.In(models.LoadZone(job.Timezone))
+	if job.AllDay {
+		return start.Format("Jan 2") + ", all day"
+	}
+	return start.Format("Jan 2, 15:04")
+}
+
+// truncate shortens s to at most max runes, ending with an ellipsis when cut
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,63 @@
+package notifications
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+// FakeProvider records messages instead of sending them
+// Used in tests and in local environments without push credentials
+type FakeProvider struct {
+	name models.PushProvider
+
+	mu            sync.Mutex
+	sent          []*Message
+	invalidTokens map[string]bool
+}
+
+// NewFakeProvider creates a fake provider standing in for the given push service
+func NewFakeProvider(name models.PushProvider) *FakeProvider {
+	return &FakeProvider{
+		name:          name,
+		invalidTokens: map[string]bool{},
+	}
+}
+
+// Name returns the provider name
+func (p *FakeProvider) Name() models.PushProvider {
+	return p.name
+}
+
+// Send records a message, or returns ErrInvalidToken for tokens marked invalid
+func (p *FakeProvider) Send(ctx context.Context, msg *Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.invalidTokens[msg.Token] {
+		return ErrInvalidToken
+	}
+
+	p.sent = append(p.sent, msg)
+	log.Printf("📱 [%s fake] %s: %s", p.name, msg.Title, msg.Body)
+	return nil
+}
+
+// InvalidateToken makes later sends to a token fail with ErrInvalidToken
+func (p *FakeProvider) InvalidateToken(token string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.invalidTokens[token] = true
+}
+
+// Sent returns the messages recorded so far
+func (p *FakeProvider) Sent() []*Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sent := make([]*Message, len(p.sent))
+	copy(sent, p.sent)
+	return sent
+}
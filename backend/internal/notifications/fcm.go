@@ -0,0 +1,145 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	// DefaultFCMBaseURL is the Firebase Cloud Messaging API endpoint
+	DefaultFCMBaseURL = "https://fcm.googleapis.com"
+
+	fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+)
+
+// FCMProvider sends push notifications through the FCM HTTP v1 API
+type FCMProvider struct {
+	projectID string
+	baseURL   string
+	client    *http.Client
+}
+
+// NewFCMProvider creates an FCM provider
+// client must attach OAuth2 credentials to requests (see NewFCMProviderFromCredentials)
+func NewFCMProvider(projectID, baseURL string, client *http.Client) *FCMProvider {
+	if baseURL == "" {
+		baseURL = DefaultFCMBaseURL
+	}
+	return &FCMProvider{
+		projectID: projectID,
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		client:    client,
+	}
+}
+
+// NewFCMProviderFromCredentials creates an FCM provider authenticated with a service account file
+func NewFCMProviderFromCredentials(ctx context.Context, projectID, credentialsFile, baseURL string) (*FCMProvider, error) {
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read FCM credentials: %w", err)
+	}
+
+	creds, err := google.CredentialsFromJSON(ctx, data, fcmScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse FCM credentials: %w", err)
+	}
+
+	client := oauth2.NewClient(ctx, creds.TokenSource)
+	client.Timeout = 10 * time.Second
+
+	return NewFCMProvider(projectID, baseURL, client), nil
+}
+
+// Name returns the provider name
+func (p *FCMProvider) Name() models.PushProvider {
+	return models.PushProviderFCM
+}
+
+type fcmRequest struct {
+	Message fcmMessage `json:"message"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+	Android      fcmAndroidConfig  `json:"android"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type fcmAndroidConfig struct {
+	Priority string `json:"priority"`
+}
+
+type fcmErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// Send delivers a message to one device
+func (p *FCMProvider) Send(ctx context.Context, msg *Message) error {
+	body, err := json.Marshal(&fcmRequest{
+		Message: fcmMessage{
+			Token:        msg.Token,
+			Notification: fcmNotification{Title: msg.Title, Body: msg.Body},
+			Data:         msg.Data,
+			Android:      fcmAndroidConfig{Priority: "high"},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal FCM message: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/v1/projects/%s/messages:send", p.baseURL, p.projectID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create FCM request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send FCM message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	respBody, _ := io.ReadAll(resp.Body)
+	var fcmErr fcmErrorResponse
+	_ = json.Unmarshal(respBody, &fcmErr)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrInvalidToken
+	}
+	for _, detail := range fcmErr.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" {
+			return ErrInvalidToken
+		}
+	}
+
+	return fmt.Errorf("FCM returned %d: %s", resp.StatusCode, fcmErr.Error.Message)
+}
@@ -0,0 +1,85 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/repositories"
+)
+
+// Notification is the content of a push sent to every device of its recipients
+type Notification struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// Notifier sends notifications to users' registered devices
+type Notifier struct {
+	deviceRepo *repositories.DeviceRepository
+	providers  map[models.PushProvider]Provider
+}
+
+// NewNotifier creates a new notifier
+// Devices of a provider missing from providers are skipped
+func NewNotifier(deviceRepo *repositories.DeviceRepository, providers ...Provider) *Notifier {
+	byName := map[models.PushProvider]Provider{}
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+	return &Notifier{
+		deviceRepo: deviceRepo,
+		providers:  byName,
+	}
+}
+
+// Notify sends a notification to all devices of the given users
+// Delivery failures are logged per device; only failing to load devices is returned
+func (n *Notifier) Notify(ctx context.Context, userIDs []int64, notification *Notification) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	devices, err := n.deviceRepo.FindByUserIDs(userIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, token := range n.deliver(ctx, devices, notification) {
+		if err := n.deviceRepo.DeleteByToken(token); err != nil {
+			fmt.Printf("Warning: failed to remove invalid device token: %v\n", err)
+		}
+	}
+
+	return nil
+}
+
+// deliver sends a notification to each device and returns the tokens the providers rejected
+func (n *Notifier) deliver(ctx context.Context, devices []*models.DeviceToken, notification *Notification) []string {
+	invalid := []string{}
+	for _, device := range devices {
+		provider, ok := n.providers[device.Provider]
+		if !ok {
+			continue
+		}
+
+		err := provider.Send(ctx, &Message{
+			Token: device.Token,
+			Title: notification.Title,
+			Body:  notification.Body,
+			Data:  notification.Data,
+		})
+		if errors.Is(err, ErrInvalidToken) {
+			invalid = append(invalid, device.Token)
+			continue
+		}
+		if err != nil {
+			// Log error but continue with other devices
+			fmt.Printf("Warning: failed to send %s push to user %d: %v\n", device.Provider, device.UserID, err)
+		}
+	}
+
+	return invalid
+}
@@ -0,0 +1,26 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+// ErrInvalidToken is returned by a provider when a device token is no longer valid
+// (app uninstalled, token rotated); the token should be removed
+var ErrInvalidToken = errors.New("invalid device token")
+
+// Message is a push notification addressed to one device
+type Message struct {
+	Token string
+	Title string
+	Body  string
+	Data  map[string]string // Delivered to the app for deep linking
+}
+
+// Provider delivers push notifications through one push service
+type Provider interface {
+	Name() models.PushProvider
+	Send(ctx context.Context, msg *Message) error
+}
@@ -0,0 +1,174 @@
+package notifications
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+func TestFCMProviderSend(t *testing.T) {
+	var received fcmRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/projects/timingle-test/messages:send" {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if received.Message.Token == "stale" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":404,"status":"NOT_FOUND","details":[{"errorCode":"UNREGISTERED"}]}}`))
+			return
+		}
+		w.Write([]byte(`{"name":"projects/timingle-test/messages/1"}`))
+	}))
+	defer server.Close()
+
+	provider := NewFCMProvider("timingle-test", server.URL, server.Client())
+
+	msg := &Message{Token: "device-1", Title: "Dinner", Body: "Alice: hi", Data: map[string]string{"event_id": "7"}}
+	if err := provider.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if received.Message.Notification.Title != "Dinner" || received.Message.Data["event_id"] != "7" {
+		t.Errorf("Unexpected payload: %+v", received.Message)
+	}
+
+	err := provider.Send(context.Background(), &Message{Token: "stale"})
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestAPNsProviderSend(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("apns-topic") != "app.timingle" {
+			t.Errorf("Unexpected topic: %s", r.Header.Get("apns-topic"))
+		}
+
+		bearer := strings.TrimPrefix(r.Header.Get("Authorization"), "bearer ")
+		token, err := jwt.Parse(bearer, func(token *jwt.Token) (interface{}, error) {
+			if token.Header["kid"] != "KEY123" {
+				t.Errorf("Unexpected kid: %v", token.Header["kid"])
+			}
+			return &key.PublicKey, nil
+		}, jwt.WithValidMethods([]string{"ES256"}))
+		if err != nil || !token.Valid {
+			t.Errorf("Invalid provider token: %v", err)
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/stale") {
+			w.WriteHeader(http.StatusGone)
+			w.Write([]byte(`{"reason":"Unregistered"}`))
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+	}))
+	defer server.Close()
+
+	provider, err := NewAPNsProvider(keyPEM, "KEY123", "TEAM456", "app.timingle", server.URL, server.Client())
+	if err != nil {
+		t.Fatalf("NewAPNsProvider failed: %v", err)
+	}
+
+	msg := &Message{Token: "device-1", Title: "Dinner", Body: "Starts in 1 hour", Data: map[string]string{"event_id": "7"}}
+	if err := provider.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if payload["event_id"] != "7" || payload["aps"] == nil {
+		t.Errorf("Unexpected payload: %v", payload)
+	}
+
+	err = provider.Send(context.Background(), &Message{Token: "stale"})
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestNotifierDeliver(t *testing.T) {
+	fcm := NewFakeProvider(models.PushProviderFCM)
+	fcm.InvalidateToken("stale")
+	notifier := NewNotifier(nil, fcm)
+
+	devices := []*models.DeviceToken{
+		{UserID: 1, Provider: models.PushProviderFCM, Token: "android-1"},
+		{UserID: 2, Provider: models.PushProviderFCM, Token: "stale"},
+		{UserID: 3, Provider: models.PushProviderAPNs, Token: "ios-1"}, // No APNs provider
+	}
+
+	invalid := notifier.deliver(context.Background(), devices, &Notification{Title: "Dinner", Body: "hi"})
+	if len(invalid) != 1 || invalid[0] != "stale" {
+		t.Errorf("Expected stale token to be reported invalid, got %v", invalid)
+	}
+
+	sent := fcm.Sent()
+	if len(sent) != 1 || sent[0].Token != "android-1" || sent[0].Title != "Dinner" {
+		t.Errorf("Unexpected sent messages: %+v", sent)
+	}
+}
+
+func TestFormatLeadTime(t *testing.T) {
+	tests := map[int]string{
+		1:    "in 1 minute",
+		15:   "in 15 minutes",
+		60:   "in 1 hour",
+		180:  "in 3 hours",
+		90:   "in 90 minutes",
+		1440: "tomorrow",
+		2880: "in 2 days",
+	}
+	for minutes, expected := range tests {
+		if got := formatLeadTime(minutes); got != expected {
+			t.Errorf("formatLeadTime(%d) = %q, want %q", minutes, got, expected)
+		}
+	}
+}
+
+func TestFormatReminderStart(t *testing.T) {
+	job := &models.ReminderJob{
+		EventStart: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		Timezone:   "Asia/Seoul",
+	}
+	if got := formatReminderStart(job); got != "Mar 1, 19:00" {
+		t.Errorf("Expected the start in the recipient's zone, got %q", got)
+	}
+
+	job.EventStart = time.Date(2026, 2, 28, 15, 0, 0, 0, time.UTC) // midnight in Seoul
+	job.AllDay = true
+	if got := formatReminderStart(job); got != "Mar 1, all day" {
+		t.Errorf("Expected the local date of an all-day event, got %q", got)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("짧은 메시지", 10); got != "짧은 메시지" {
+		t.Errorf("Short string changed: %q", got)
+	}
+	if got := truncate("가나다라마바사", 4); got != "가나다…" {
+		t.Errorf("Expected rune-safe truncation, got %q", got)
+	}
+}
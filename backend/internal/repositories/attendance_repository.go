@@ -0,0 +1,239 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+// AttendanceRepository handles event attendance data operations
+type AttendanceRepository struct {
+	db *sql.DB
+}
+
+// NewAttendanceRepository creates a new attendance repository
+func NewAttendanceRepository(db *sql.DB) *AttendanceRepository {
+	return &AttendanceRepository{db: db}
+}
+
+// Upsert creates or updates a participant's attendance record
+func (r *AttendanceRepository) Upsert(attendance *models.EventAttendance) error {
+	query := `
+		INSERT INTO event_attendance (event_id, user_id, occurrence_start, status, method, checked_in_at, recorded_by, note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (event_id, user_id, occurrence_start) DO UPDATE
+		SET status = EXCLUDED.status,
+		    method = EXCLUDED.method,
+		    checked_in_at = EXCLUDED.checked_in_at,
+		    recorded_by = EXCLUDED.recorded_by,
+		    note = EXCLUDED.note,
+		    updated_at = NOW()
+		RETURNING created_at, updated_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		attendance.EventID,
+		attendance.UserID,
+		attendance.OccurrenceStart,
+		attendance.Status,
+		attendance.Method,
+		attendance.CheckedInAt,
+		attendance.RecordedBy,
+		attendance.Note,
+	).Scan(&attendance.CreatedAt, &attendance.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save attendance: %w", err)
+	}
+
+	return nil
+}
+
+// Find finds a participant's attendance record (returns nil, nil if none)
+// occurrenceStart selects an occurrence of a recurring event; nil for single events
+func (r *AttendanceRepository) Find(eventID, userID int64, occurrenceStart *time.Time) (*models.EventAttendance, error) {
+	query := `
+		SELECT event_id, user_id, occurrence_start, status, method, checked_in_at, recorded_by, note, created_at, updated_at
+		FROM event_attendance
+		WHERE event_id = $1 AND user_id = $2 AND occurrence_start IS NOT DISTINCT FROM $3
+	`
+
+	attendance := &models.EventAttendance{}
+	err := r.db.QueryRow(query, eventID, userID, occurrenceStart).Scan(
+		&attendance.EventID,
+		&attendance.UserID,
+		&attendance.OccurrenceStart,
+		&attendance.Status,
+		&attendance.Method,
+		&attendance.CheckedInAt,
+		&attendance.RecordedBy,
+		&attendance.Note,
+		&attendance.CreatedAt,
+		&attendance.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find attendance: %w", err)
+	}
+
+	return attendance, nil
+}
+
+// FindByEventID finds all attendance records of an event
+func (r *AttendanceRepository) FindByEventID(eventID int64) ([]*models.EventAttendance, error) {
+	query := `
+		SELECT event_id, user_id, occurrence_start, status, method, checked_in_at, recorded_by, note, created_at, updated_at
+		FROM event_attendance
+		WHERE event_id = $1
+		ORDER BY occurrence_start ASC NULLS FIRST, user_id ASC
+	`
+
+	rows, err := r.db.Query(query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find attendance: %w", err)
+	}
+	defer rows.Close()
+
+	records := []*models.EventAttendance{}
+	for rows.Next() {
+		attendance := &models.EventAttendance{}
+		err := rows.Scan(
+			&attendance.EventID,
+			&attendance.UserID,
+			&attendance.OccurrenceStart,
+			&attendance.Status,
+			&attendance.Method,
+			&attendance.CheckedInAt,
+			&attendance.RecordedBy,
+			&attendance.Note,
+			&attendance.CreatedAt,
+			&attendance.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attendance: %w", err)
+		}
+		records = append(records, attendance)
+	}
+
+	return records, nil
+}
+
+// MarkNoShows records NO_SHOW for accepted or confirmed participants without an attendance record
+// Returns the IDs of the users that were marked
+func (r *AttendanceRepository) MarkNoShows(eventID int64) ([]int64, error) {
+	query := `
+		INSERT INTO event_attendance (event_id, user_id, status, method)
+		SELECT event_id, user_id, 'NO_SHOW', 'SYSTEM'
+		FROM event_participants
+		WHERE event_id = $1 AND (status = 'ACCEPTED' OR confirmed)
+		ON CONFLICT (event_id, user_id, occurrence_start) DO NOTHING
+		RETURNING user_id
+	`
+
+	rows, err := r.db.Query(query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark no-shows: %w", err)
+	}
+	defer rows.Close()
+
+	userIDs := []int64{}
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan user ID: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, nil
+}
+
+// CountByUserID counts a user's attendance records per status
+func (r *AttendanceRepository) CountByUserID(userID int64) (map[models.AttendanceStatus]int, error) {
+	query := `
+		SELECT status, COUNT(*)
+		FROM event_attendance
+		WHERE user_id = $1
+		GROUP BY status
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count attendance: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[models.AttendanceStatus]int{}
+	for rows.Next() {
+		var status models.AttendanceStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan attendance count: %w", err)
+		}
+		counts[status] = count
+	}
+
+	return counts, nil
+}
+
+// UpdateReliabilityScore stores a user's reliability score (nil clears it)
+func (r *AttendanceRepository) UpdateReliabilityScore(userID int64, score *float64) error {
+	query := `UPDATE users SET reliability_score = $1 WHERE id = $2`
+
+	if _, err := r.db.Exec(query, score, userID); err != nil {
+		return fmt.Errorf("failed to update reliability score: %w", err)
+	}
+
+	return nil
+}
+
+// UpsertGeofence creates or updates the check-in area of an event
+func (r *AttendanceRepository) UpsertGeofence(geofence *models.EventGeofence) error {
+	query := `
+		INSERT INTO event_geofences (event_id, latitude, longitude, radius_meters)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (event_id) DO UPDATE
+		SET latitude = EXCLUDED.latitude,
+		    longitude = EXCLUDED.longitude,
+		    radius_meters = EXCLUDED.radius_meters,
+		    updated_at = NOW()
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(query, geofence.EventID, geofence.Latitude, geofence.Longitude, geofence.RadiusMeters).Scan(&geofence.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save geofence: %w", err)
+	}
+
+	return nil
+}
+
+// FindGeofence finds the check-in area of an event (returns nil, nil if none)
+func (r *AttendanceRepository) FindGeofence(eventID int64) (*models.EventGeofence, error) {
+	query := `
+		SELECT event_id, latitude, longitude, radius_meters, updated_at
+		FROM event_geofences
+		WHERE event_id = $1
+	`
+
+	geofence := &models.EventGeofence{}
+	err := r.db.QueryRow(query, eventID).Scan(
+		&geofence.EventID,
+		&geofence.Latitude,
+		&geofence.Longitude,
+		&geofence.RadiusMeters,
+		&geofence.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find geofence: %w", err)
+	}
+
+	return geofence, nil
+}
@@ -18,20 +18,50 @@ func NewAuthRepository(db *sql.DB) *AuthRepository {
 	return &AuthRepository{db: db}
 }
 
+const refreshTokenColumns = `id, user_id, token_hash, family_id, platform, user_agent, ip_address,
+	expires_at, rotated_at, revoked_at, last_used_at, created_at`
+
+// scanRefreshToken scans a row selected with refreshTokenColumns
+func scanRefreshToken(row rowScanner) (*models.RefreshToken, error) {
+	refreshToken := &models.RefreshToken{}
+	err := row.Scan(
+		&refreshToken.ID,
+		&refreshToken.UserID,
+		&refreshToken.TokenHash,
+		&refreshToken.FamilyID,
+		&refreshToken.Platform,
+		&refreshToken.UserAgent,
+		&refreshToken.IPAddress,
+		&refreshToken.ExpiresAt,
+		&refreshToken.RotatedAt,
+		&refreshToken.RevokedAt,
+		&refreshToken.LastUsedAt,
+		&refreshToken.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return refreshToken, nil
+}
+
 // SaveRefreshToken saves a refresh token
 func (r *AuthRepository) SaveRefreshToken(refreshToken *models.RefreshToken) error {
 	query := `
-		INSERT INTO refresh_tokens (user_id, token, expires_at)
-		VALUES ($1, $2, $3)
-		RETURNING id, created_at
+		INSERT INTO refresh_tokens (user_id, token_hash, family_id, platform, user_agent, ip_address, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, last_used_at, created_at
 	`
 
 	err := r.db.QueryRow(
 		query,
 		refreshToken.UserID,
-		refreshToken.Token,
+		refreshToken.TokenHash,
+		refreshToken.FamilyID,
+		refreshToken.Platform,
+		refreshToken.UserAgent,
+		refreshToken.IPAddress,
 		refreshToken.ExpiresAt,
-	).Scan(&refreshToken.ID, &refreshToken.CreatedAt)
+	).Scan(&refreshToken.ID, &refreshToken.LastUsedAt, &refreshToken.CreatedAt)
 
 	if err != nil {
 		return fmt.Errorf("failed to save refresh token: %w", err)
@@ -40,23 +70,15 @@ func (r *AuthRepository) SaveRefreshToken(refreshToken *models.RefreshToken) err
 	return nil
 }
 
-// FindRefreshToken finds a refresh token by token string
-func (r *AuthRepository) FindRefreshToken(token string) (*models.RefreshToken, error) {
+// FindRefreshTokenByHash finds a refresh token by the hash of its token string
+func (r *AuthRepository) FindRefreshTokenByHash(tokenHash string) (*models.RefreshToken, error) {
 	query := `
-		SELECT id, user_id, token, expires_at, created_at
+		SELECT ` + refreshTokenColumns + `
 		FROM refresh_tokens
-		WHERE token = $1
+		WHERE token_hash = $1
 	`
 
-	refreshToken := &models.RefreshToken{}
-	err := r.db.QueryRow(query, token).Scan(
-		&refreshToken.ID,
-		&refreshToken.UserID,
-		&refreshToken.Token,
-		&refreshToken.ExpiresAt,
-		&refreshToken.CreatedAt,
-	)
-
+	refreshToken, err := scanRefreshToken(r.db.QueryRow(query, tokenHash))
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("refresh token not found")
 	}
@@ -67,11 +89,164 @@ func (r *AuthRepository) FindRefreshToken(token string) (*models.RefreshToken, e
 	return refreshToken, nil
 }
 
-// DeleteRefreshToken deletes a refresh token
-func (r *AuthRepository) DeleteRefreshToken(token string) error {
-	query := `DELETE FROM refresh_tokens WHERE token = $1`
+// RotateRefreshToken marks a token as rotated and saves its replacement in the same family
+// The replacement carries next's device metadata
+// Returns false without saving when the token was already rotated or revoked,
+// e.g. by a concurrent request presenting the same token
+func (r *AuthRepository) RotateRefreshToken(current, next *models.RefreshToken) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE refresh_tokens
+		SET rotated_at = NOW()
+		WHERE id = $1 AND rotated_at IS NULL AND revoked_at IS NULL
+	`, current.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO refresh_tokens (user_id, token_hash, family_id, platform, user_agent, ip_address, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, last_used_at, created_at
+	`,
+		next.UserID,
+		next.TokenHash,
+		current.FamilyID,
+		next.Platform,
+		next.UserAgent,
+		next.IPAddress,
+		next.ExpiresAt,
+	).Scan(&next.ID, &next.LastUsedAt, &next.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to save refresh token: %w", err)
+	}
+	next.FamilyID = current.FamilyID
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// RevokeRefreshTokenFamily revokes every token of a family
+func (r *AuthRepository) RevokeRefreshTokenFamily(familyID string) (int64, error) {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = NOW()
+		WHERE family_id = $1 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, familyID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// FindActiveSessions returns a user's sessions that can still be refreshed, most recently used first
+// Each family's unrotated token describes the session; CreatedAt is when the family started
+func (r *AuthRepository) FindActiveSessions(userID int64) ([]*models.Session, error) {
+	query := `
+		SELECT t.family_id, t.platform, t.user_agent, t.ip_address,
+			(SELECT MIN(f.created_at) FROM refresh_tokens f WHERE f.family_id = t.family_id),
+			t.last_used_at, t.expires_at
+		FROM refresh_tokens t
+		WHERE t.user_id = $1 AND t.rotated_at IS NULL AND t.revoked_at IS NULL AND t.expires_at > NOW()
+		ORDER BY t.last_used_at DESC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []*models.Session{}
+	for rows.Next() {
+		session := &models.Session{}
+		if err := rows.Scan(
+			&session.ID,
+			&session.Platform,
+			&session.UserAgent,
+			&session.IPAddress,
+			&session.CreatedAt,
+			&session.LastUsedAt,
+			&session.ExpiresAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// RevokeUserSession revokes one session (token family) of a user
+func (r *AuthRepository) RevokeUserSession(userID int64, familyID string) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = NOW()
+		WHERE user_id = $1 AND family_id = $2 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, userID, familyID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("session not found")
+	}
+
+	return nil
+}
+
+// RevokeOtherUserSessions revokes every session of a user except keepFamilyID
+// Returns the number of sessions revoked
+func (r *AuthRepository) RevokeOtherUserSessions(userID int64, keepFamilyID string) (int64, error) {
+	query := `
+		WITH revoked AS (
+			UPDATE refresh_tokens
+			SET revoked_at = NOW()
+			WHERE user_id = $1 AND family_id <> $2 AND revoked_at IS NULL
+			RETURNING rotated_at
+		)
+		SELECT COUNT(*) FROM revoked WHERE rotated_at IS NULL
+	`
+
+	var revoked int64
+	if err := r.db.QueryRow(query, userID, keepFamilyID).Scan(&revoked); err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return revoked, nil
+}
+
+// DeleteRefreshToken deletes a refresh token by the hash of its token string
+func (r *AuthRepository) DeleteRefreshToken(tokenHash string) error {
+	query := `DELETE FROM refresh_tokens WHERE token_hash = $1`
 
-	result, err := r.db.Exec(query, token)
+	result, err := r.db.Exec(query, tokenHash)
 	if err != nil {
 		return fmt.Errorf("failed to delete refresh token: %w", err)
 	}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+// CalendarFeedRepository handles iCalendar subscription feed operations
+type CalendarFeedRepository struct {
+	db *sql.DB
+}
+
+// NewCalendarFeedRepository creates a new calendar feed repository
+func NewCalendarFeedRepository(db *sql.DB) *CalendarFeedRepository {
+	return &CalendarFeedRepository{db: db}
+}
+
+// Upsert creates a user's feed or replaces its token, invalidating the previous URL
+func (r *CalendarFeedRepository) Upsert(feed *models.CalendarFeed) error {
+	query := `
+		INSERT INTO calendar_feeds (user_id, token_hash)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET token_hash = EXCLUDED.token_hash,
+		    created_at = NOW(),
+		    last_accessed_at = NULL
+		RETURNING created_at, last_accessed_at
+	`
+
+	err := r.db.QueryRow(query, feed.UserID, feed.TokenHash).Scan(&feed.CreatedAt, &feed.LastAccessedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save calendar feed: %w", err)
+	}
+
+	return nil
+}
+
+// FindByUserID finds a user's feed
+func (r *CalendarFeedRepository) FindByUserID(userID int64) (*models.CalendarFeed, error) {
+	query := `
+		SELECT user_id, token_hash, created_at, last_accessed_at
+		FROM calendar_feeds
+		WHERE user_id = $1
+	`
+
+	return r.findOne(query, userID)
+}
+
+// FindByTokenHash finds the feed a token belongs to
+func (r *CalendarFeedRepository) FindByTokenHash(tokenHash string) (*models.CalendarFeed, error) {
+	query := `
+		SELECT user_id, token_hash, created_at, last_accessed_at
+		FROM calendar_feeds
+		WHERE token_hash = $1
+	`
+
+	return r.findOne(query, tokenHash)
+}
+
+func (r *CalendarFeedRepository) findOne(query string, arg interface{}) (*models.CalendarFeed, error) {
+	feed := &models.CalendarFeed{}
+	err := r.db.QueryRow(query, arg).Scan(&feed.UserID, &feed.TokenHash, &feed.CreatedAt, &feed.LastAccessedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find calendar feed: %w", err)
+	}
+
+	return feed, nil
+}
+
+// Touch records that a calendar app fetched the feed
+func (r *CalendarFeedRepository) Touch(userID int64) error {
+	query := `UPDATE calendar_feeds SET last_accessed_at = NOW() WHERE user_id = $1`
+
+	if _, err := r.db.Exec(query, userID); err != nil {
+		return fmt.Errorf("failed to update calendar feed: %w", err)
+	}
+
+	return nil
+}
+
+// Delete revokes a user's feed
+// Returns false if the user had no feed
+func (r *CalendarFeedRepository) Delete(userID int64) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM calendar_feeds WHERE user_id = $1`, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete calendar feed: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
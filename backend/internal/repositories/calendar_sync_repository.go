@@ -0,0 +1,344 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+// calendarChannelColumns lists the calendar_sync_channels columns scanned by scanCalendarChannel
+const calendarChannelColumns = `id, user_id, provider, calendar_id, channel_id, resource_id, token, expires_at, sync_token, last_synced_at, created_at, updated_at`
+
+// calendarMappingColumns lists the calendar_event_mappings columns scanned by scanCalendarMapping
+const calendarMappingColumns = `id, event_id, user_id, provider, calendar_id, external_event_id, sync_state, etag,
+		last_synced_at, last_error, error_count, created_at, updated_at`
+
+// CalendarSyncRepository handles calendar push channels, participants' event copies and sync state
+type CalendarSyncRepository struct {
+	db *sql.DB
+}
+
+// NewCalendarSyncRepository creates a new calendar sync repository
+func NewCalendarSyncRepository(db *sql.DB) *CalendarSyncRepository {
+	return &CalendarSyncRepository{db: db}
+}
+
+// scanCalendarChannel scans a row selected with calendarChannelColumns
+func scanCalendarChannel(row rowScanner) (*models.CalendarSyncChannel, error) {
+	channel := &models.CalendarSyncChannel{}
+	err := row.Scan(
+		&channel.ID,
+		&channel.UserID,
+		&channel.Provider,
+		&channel.CalendarID,
+		&channel.ChannelID,
+		&channel.ResourceID,
+		&channel.Token,
+		&channel.ExpiresAt,
+		&channel.SyncToken,
+		&channel.LastSyncedAt,
+		&channel.CreatedAt,
+		&channel.UpdatedAt,
+	)
+	return channel, err
+}
+
+// UpsertChannel stores a user's newly registered channel, replacing the previous one
+// The sync token is kept, so renewing a channel does not force a full sync
+func (r *CalendarSyncRepository) UpsertChannel(channel *models.CalendarSyncChannel) error {
+	query := `
+		INSERT INTO calendar_sync_channels (user_id, provider, calendar_id, channel_id, resource_id, token, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, provider) DO UPDATE
+		SET calendar_id = EXCLUDED.calendar_id, channel_id = EXCLUDED.channel_id,
+		    resource_id = EXCLUDED.resource_id, token = EXCLUDED.token,
+		    expires_at = EXCLUDED.expires_at, updated_at = NOW()
+		RETURNING ` + calendarChannelColumns
+
+	stored, err := scanCalendarChannel(r.db.QueryRow(
+		query,
+		channel.UserID,
+		channel.Provider,
+		channel.CalendarID,
+		channel.ChannelID,
+		channel.ResourceID,
+		channel.Token,
+		channel.ExpiresAt,
+	))
+	if err != nil {
+		return fmt.Errorf("failed to save calendar channel: %w", err)
+	}
+
+	*channel = *stored
+	return nil
+}
+
+// FindByChannelID finds a channel by the ID Google sends in notifications
+func (r *CalendarSyncRepository) FindByChannelID(channelID string) (*models.CalendarSyncChannel, error) {
+	query := `SELECT ` + calendarChannelColumns + ` FROM calendar_sync_channels WHERE channel_id = $1`
+
+	channel, err := scanCalendarChannel(r.db.QueryRow(query, channelID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("calendar channel not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find calendar channel: %w", err)
+	}
+
+	return channel, nil
+}
+
+// FindByUserID finds a user's channel for a provider, nil if there is none
+func (r *CalendarSyncRepository) FindByUserID(userID int64, provider models.OAuthProvider) (*models.CalendarSyncChannel, error) {
+	query := `SELECT ` + calendarChannelColumns + ` FROM calendar_sync_channels WHERE user_id = $1 AND provider = $2`
+
+	channel, err := scanCalendarChannel(r.db.QueryRow(query, userID, provider))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find calendar channel: %w", err)
+	}
+
+	return channel, nil
+}
+
+// FindExpiringBefore finds the channels that expire before a time
+func (r *CalendarSyncRepository) FindExpiringBefore(before time.Time) ([]*models.CalendarSyncChannel, error) {
+	query := `SELECT ` + calendarChannelColumns + ` FROM calendar_sync_channels WHERE expires_at < $1 ORDER BY expires_at`
+
+	rows, err := r.db.Query(query, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expiring calendar channels: %w", err)
+	}
+	defer rows.Close()
+
+	channels := []*models.CalendarSyncChannel{}
+	for rows.Next() {
+		channel, err := scanCalendarChannel(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan calendar channel: %w", err)
+		}
+		channels = append(channels, channel)
+	}
+
+	return channels, rows.Err()
+}
+
+// UpdateSyncToken stores the sync token to continue from; nil forces a full sync next time
+func (r *CalendarSyncRepository) UpdateSyncToken(id int64, syncToken *string, syncedAt time.Time) error {
+	query := `
+		UPDATE calendar_sync_channels
+		SET sync_token = $2, last_synced_at = $3, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	if _, err := r.db.Exec(query, id, syncToken, syncedAt); err != nil {
+		return fmt.Errorf("failed to update sync token: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a user's channel for a provider
+func (r *CalendarSyncRepository) Delete(userID int64, provider models.OAuthProvider) error {
+	query := `DELETE FROM calendar_sync_channels WHERE user_id = $1 AND provider = $2`
+
+	result, err := r.db.Exec(query, userID, provider)
+	if err != nil {
+		return fmt.Errorf("failed to delete calendar channel: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("calendar sync is not enabled")
+	}
+
+	return nil
+}
+
+// scanCalendarMapping scans a row selected with calendarMappingColumns
+func scanCalendarMapping(row rowScanner) (*models.CalendarEventMapping, error) {
+	mapping := &models.CalendarEventMapping{}
+	err := row.Scan(
+		&mapping.ID,
+		&mapping.EventID,
+		&mapping.UserID,
+		&mapping.Provider,
+		&mapping.CalendarID,
+		&mapping.ExternalEventID,
+		&mapping.SyncState,
+		&mapping.ETag,
+		&mapping.LastSyncedAt,
+		&mapping.LastError,
+		&mapping.ErrorCount,
+		&mapping.CreatedAt,
+		&mapping.UpdatedAt,
+	)
+	return mapping, err
+}
+
+// scanCalendarMappings scans all rows selected with calendarMappingColumns
+func scanCalendarMappings(rows *sql.Rows) ([]*models.CalendarEventMapping, error) {
+	mappings := []*models.CalendarEventMapping{}
+	for rows.Next() {
+		mapping, err := scanCalendarMapping(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan calendar mapping: %w", err)
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	return mappings, rows.Err()
+}
+
+// SaveSyncedMapping records a successful write of a user's copy of an event and clears any previous error
+func (r *CalendarSyncRepository) SaveSyncedMapping(mapping *models.CalendarEventMapping) error {
+	query := `
+		INSERT INTO calendar_event_mappings (event_id, user_id, provider, calendar_id, external_event_id, sync_state, etag, last_synced_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		ON CONFLICT (event_id, user_id, provider) DO UPDATE
+		SET calendar_id = EXCLUDED.calendar_id, external_event_id = EXCLUDED.external_event_id,
+		    sync_state = EXCLUDED.sync_state, etag = EXCLUDED.etag, last_synced_at = EXCLUDED.last_synced_at,
+		    last_error = NULL, error_count = 0, updated_at = NOW()
+		RETURNING ` + calendarMappingColumns
+
+	stored, err := scanCalendarMapping(r.db.QueryRow(
+		query,
+		mapping.EventID,
+		mapping.UserID,
+		mapping.Provider,
+		mapping.CalendarID,
+		mapping.ExternalEventID,
+		models.CalendarSyncStateSynced,
+		mapping.ETag,
+	))
+	if err != nil {
+		return fmt.Errorf("failed to save calendar mapping: %w", err)
+	}
+
+	*mapping = *stored
+	return nil
+}
+
+// MarkMappingFailed records that a change could not be written to a copy
+func (r *CalendarSyncRepository) MarkMappingFailed(id int64, syncErr string) error {
+	query := `
+		UPDATE calendar_event_mappings
+		SET sync_state = $2, last_error = $3, error_count = error_count + 1, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	if _, err := r.db.Exec(query, id, models.CalendarSyncStateFailed, syncErr); err != nil {
+		return fmt.Errorf("failed to update calendar mapping: %w", err)
+	}
+
+	return nil
+}
+
+// FindMapping finds a user's copy of an event, nil if the user has not synced it
+func (r *CalendarSyncRepository) FindMapping(eventID, userID int64, provider models.OAuthProvider) (*models.CalendarEventMapping, error) {
+	query := `SELECT ` + calendarMappingColumns + ` FROM calendar_event_mappings WHERE event_id = $1 AND user_id = $2 AND provider = $3`
+
+	mapping, err := scanCalendarMapping(r.db.QueryRow(query, eventID, userID, provider))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find calendar mapping: %w", err)
+	}
+
+	return mapping, nil
+}
+
+// FindMappingsByEventID finds every participant's copy of an event
+func (r *CalendarSyncRepository) FindMappingsByEventID(eventID int64) ([]*models.CalendarEventMapping, error) {
+	query := `SELECT ` + calendarMappingColumns + ` FROM calendar_event_mappings WHERE event_id = $1 ORDER BY id`
+
+	rows, err := r.db.Query(query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find calendar mappings: %w", err)
+	}
+	defer rows.Close()
+
+	return scanCalendarMappings(rows)
+}
+
+// FindMappingsByExternalIDs finds a user's copies with any of the given external event IDs
+func (r *CalendarSyncRepository) FindMappingsByExternalIDs(userID int64, provider models.OAuthProvider, externalIDs []string) ([]*models.CalendarEventMapping, error) {
+	if len(externalIDs) == 0 {
+		return []*models.CalendarEventMapping{}, nil
+	}
+
+	query := `
+		SELECT ` + calendarMappingColumns + `
+		FROM calendar_event_mappings
+		WHERE user_id = $1 AND provider = $2 AND external_event_id = ANY($3)
+	`
+
+	rows, err := r.db.Query(query, userID, provider, pq.Array(externalIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find calendar mappings: %w", err)
+	}
+	defer rows.Close()
+
+	return scanCalendarMappings(rows)
+}
+
+// DeleteMapping forgets a copy; the event is no longer kept in sync with it
+func (r *CalendarSyncRepository) DeleteMapping(id int64) error {
+	query := `DELETE FROM calendar_event_mappings WHERE id = $1`
+
+	if _, err := r.db.Exec(query, id); err != nil {
+		return fmt.Errorf("failed to delete calendar mapping: %w", err)
+	}
+
+	return nil
+}
+
+// FindMappingsByUserID finds all of a user's copies for a provider
+func (r *CalendarSyncRepository) FindMappingsByUserID(userID int64, provider models.OAuthProvider) ([]*models.CalendarEventMapping, error) {
+	query := `SELECT ` + calendarMappingColumns + ` FROM calendar_event_mappings WHERE user_id = $1 AND provider = $2 ORDER BY id`
+
+	rows, err := r.db.Query(query, userID, provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find calendar mappings: %w", err)
+	}
+	defer rows.Close()
+
+	return scanCalendarMappings(rows)
+}
+
+// SummarizeMappings fills the copy counts, last sync time and last error of a user's sync status
+func (r *CalendarSyncRepository) SummarizeMappings(userID int64, provider models.OAuthProvider, status *models.CalendarSyncStatus) error {
+	query := `
+		SELECT COUNT(*) FILTER (WHERE sync_state = $3),
+		       COUNT(*) FILTER (WHERE sync_state = $4),
+		       MAX(last_synced_at),
+		       (SELECT last_error FROM calendar_event_mappings
+		        WHERE user_id = $1 AND provider = $2 AND sync_state = $4
+		        ORDER BY updated_at DESC LIMIT 1)
+		FROM calendar_event_mappings
+		WHERE user_id = $1 AND provider = $2
+	`
+
+	err := r.db.QueryRow(query, userID, provider, models.CalendarSyncStateSynced, models.CalendarSyncStateFailed).Scan(
+		&status.Copies.Synced,
+		&status.Copies.Failed,
+		&status.LastSyncedAt,
+		&status.LastError,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to summarize calendar mappings: %w", err)
+	}
+
+	return nil
+}
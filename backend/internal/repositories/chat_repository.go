@@ -145,8 +145,9 @@ func (r *ChatRepository) SaveEventHistory(entry *models.EventHistoryEntry) error
 	).Exec()
 }
 
-// GetEventHistory retrieves event history
-func (r *ChatRepository) GetEventHistory(eventID int64, limit int) ([]*models.EventHistoryEntry, error) {
+// GetEventHistory retrieves a page of event history, newest first
+// pageState is the value returned by the previous call (nil for the first page)
+func (r *ChatRepository) GetEventHistory(eventID int64, limit int, pageState []byte) ([]*models.EventHistoryEntry, []byte, error) {
 	if limit == 0 {
 		limit = 100
 	}
@@ -157,10 +158,10 @@ func (r *ChatRepository) GetEventHistory(eventID int64, limit int) ([]*models.Ev
 		FROM event_history
 		WHERE event_id = ?
 		ORDER BY changed_at DESC
-		LIMIT ?
 	`
 
-	iter := r.session.Query(query, eventID, limit).Iter()
+	iter := r.session.Query(query, eventID).PageSize(limit).PageState(pageState).Iter()
+	nextPageState := iter.PageState()
 
 	entries := []*models.EventHistoryEntry{}
 
@@ -184,8 +185,113 @@ func (r *ChatRepository) GetEventHistory(eventID int64, limit int) ([]*models.Ev
 	}
 
 	if err := iter.Close(); err != nil {
-		return nil, fmt.Errorf("failed to get event history: %w", err)
+		return nil, nil, fmt.Errorf("failed to get event history: %w", err)
 	}
 
-	return entries, nil
+	return entries, nextPageState, nil
+}
+
+// FindMessagesBySender retrieves all messages a user sent in an event, oldest first
+// Filtering stays within the event's partition, so ALLOW FILTERING does not scan the cluster
+func (r *ChatRepository) FindMessagesBySender(eventID, senderID int64) ([]*models.ChatMessage, error) {
+	query := `
+		SELECT event_id, created_at, message_id, sender_id, sender_name, sender_profile_url,
+		       message, message_type, attachments, reply_to, edited_at, is_deleted, metadata
+		FROM chat_messages_by_event
+		WHERE event_id = ? AND sender_id = ?
+		ALLOW FILTERING
+	`
+
+	iter := r.session.Query(query, eventID, senderID).Iter()
+
+	messages := []*models.ChatMessage{}
+
+	for {
+		msg := &models.ChatMessage{}
+		if !iter.Scan(
+			&msg.EventID,
+			&msg.CreatedAt,
+			&msg.MessageID,
+			&msg.SenderID,
+			&msg.SenderName,
+			&msg.SenderProfileURL,
+			&msg.Message,
+			&msg.MessageType,
+			&msg.Attachments,
+			&msg.ReplyTo,
+			&msg.EditedAt,
+			&msg.IsDeleted,
+			&msg.Metadata,
+		) {
+			break
+		}
+		messages = append(messages, msg)
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get messages by sender: %w", err)
+	}
+
+	return messages, nil
+}
+
+// AnonymizeSender replaces the authorship of a user's messages in an event with senderName
+// The messages stay so the conversation remains readable for the other members
+func (r *ChatRepository) AnonymizeSender(eventID, senderID int64, senderName string) (int, error) {
+	messages, err := r.FindMessagesBySender(eventID, senderID)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `
+		UPDATE chat_messages_by_event
+		SET sender_id = 0, sender_name = ?, sender_profile_url = ''
+		WHERE event_id = ? AND created_at = ? AND message_id = ?
+	`
+
+	for _, msg := range messages {
+		if err := r.session.Query(query, senderName, eventID, msg.CreatedAt, msg.MessageID).Exec(); err != nil {
+			return 0, fmt.Errorf("failed to anonymize message: %w", err)
+		}
+	}
+
+	return len(messages), nil
+}
+
+// AnonymizeHistoryActor replaces the actor of a user's event history entries with actorName
+func (r *ChatRepository) AnonymizeHistoryActor(eventID, actorID int64, actorName string) error {
+	iter := r.session.Query(`
+		SELECT changed_at, change_id
+		FROM event_history
+		WHERE event_id = ? AND actor_id = ?
+		ALLOW FILTERING
+	`, eventID, actorID).Iter()
+
+	type entryKey struct {
+		changedAt time.Time
+		changeID  gocql.UUID
+	}
+	keys := []entryKey{}
+
+	var key entryKey
+	for iter.Scan(&key.changedAt, &key.changeID) {
+		keys = append(keys, key)
+	}
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("failed to get event history by actor: %w", err)
+	}
+
+	query := `
+		UPDATE event_history
+		SET actor_id = 0, actor_name = ?
+		WHERE event_id = ? AND changed_at = ? AND change_id = ?
+	`
+
+	for _, key := range keys {
+		if err := r.session.Query(query, actorName, eventID, key.changedAt, key.changeID).Exec(); err != nil {
+			return fmt.Errorf("failed to anonymize event history: %w", err)
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/lib/pq"
+)
+
+// DeviceRepository handles push notification device token operations
+type DeviceRepository struct {
+	db *sql.DB
+}
+
+// NewDeviceRepository creates a new device repository
+func NewDeviceRepository(db *sql.DB) *DeviceRepository {
+	return &DeviceRepository{db: db}
+}
+
+// Upsert registers a device token for a user
+// A token registered by another user is moved to this user (shared device, re-login)
+func (r *DeviceRepository) Upsert(device *models.DeviceToken) error {
+	query := `
+		INSERT INTO device_tokens (user_id, provider, platform, token)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (token) DO UPDATE
+		SET user_id = EXCLUDED.user_id,
+		    provider = EXCLUDED.provider,
+		    platform = EXCLUDED.platform,
+		    last_seen_at = NOW()
+		RETURNING id, created_at, last_seen_at
+	`
+
+	err := r.db.QueryRow(query, device.UserID, device.Provider, device.Platform, device.Token).
+		Scan(&device.ID, &device.CreatedAt, &device.LastSeenAt)
+	if err != nil {
+		return fmt.Errorf("failed to register device: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a user's device token
+func (r *DeviceRepository) Delete(userID int64, token string) error {
+	query := `DELETE FROM device_tokens WHERE user_id = $1 AND token = $2`
+
+	result, err := r.db.Exec(query, userID, token)
+	if err != nil {
+		return fmt.Errorf("failed to delete device: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("device not found")
+	}
+
+	return nil
+}
+
+// DeleteByToken removes a token the provider reported as invalid
+func (r *DeviceRepository) DeleteByToken(token string) error {
+	query := `DELETE FROM device_tokens WHERE token = $1`
+
+	if _, err := r.db.Exec(query, token); err != nil {
+		return fmt.Errorf("failed to delete device: %w", err)
+	}
+
+	return nil
+}
+
+// FindByUserIDs finds the device tokens of the given users
+func (r *DeviceRepository) FindByUserIDs(userIDs []int64) ([]*models.DeviceToken, error) {
+	if len(userIDs) == 0 {
+		return []*models.DeviceToken{}, nil
+	}
+
+	query := `
+		SELECT id, user_id, provider, platform, token, created_at, last_seen_at
+		FROM device_tokens
+		WHERE user_id = ANY($1)
+	`
+
+	rows, err := r.db.Query(query, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find devices: %w", err)
+	}
+	defer rows.Close()
+
+	devices := []*models.DeviceToken{}
+	for rows.Next() {
+		device := &models.DeviceToken{}
+		err := rows.Scan(
+			&device.ID,
+			&device.UserID,
+			&device.Provider,
+			&device.Platform,
+			&device.Token,
+			&device.CreatedAt,
+			&device.LastSeenAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan device: %w", err)
+		}
+		devices = append(devices, device)
+	}
+
+	return devices, nil
+}
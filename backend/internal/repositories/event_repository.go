@@ -3,10 +3,106 @@ package repositories
 import (
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
 
 	"github.com/khchoi-tnh/timingle/internal/models"
 )
 
+// eventColumns is the column list shared by all event SELECT queries (see scanEvent)
+const eventColumns = `id, title, description, start_time, end_time, location, creator_id, status,
+		timezone, all_day, recurrence_rule, recurrence_exdates, parent_event_id, created_at, updated_at`
+
+// prefixedEventColumns returns eventColumns qualified with a table alias
+func prefixedEventColumns(alias string) string {
+	columns := strings.Split(eventColumns, ",")
+	for i, column := range columns {
+		columns[i] = alias + "." + strings.TrimSpace(column)
+	}
+	return strings.Join(columns, ", ")
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanEvent scans a row selected with eventColumns
+func scanEvent(row rowScanner) (*models.Event, error) {
+	event := &models.Event{}
+	var exdates pq.StringArray
+	err := row.Scan(
+		&event.ID,
+		&event.Title,
+		&event.Description,
+		&event.StartTime,
+		&event.EndTime,
+		&event.Location,
+		&event.CreatorID,
+		&event.Status,
+		&event.Timezone,
+		&event.AllDay,
+		&event.RecurrenceRule,
+		&exdates,
+		&event.ParentEventID,
+		&event.CreatedAt,
+		&event.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	event.RecurrenceExDates, err = parseExDates(exdates)
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// formatExDates converts EXDATE values to the RFC3339 strings stored in recurrence_exdates
+func formatExDates(exdates []time.Time) pq.StringArray {
+	if len(exdates) == 0 {
+		return nil
+	}
+	values := make(pq.StringArray, len(exdates))
+	for i, exdate := range exdates {
+		values[i] = exdate.UTC().Format(time.RFC3339)
+	}
+	return values
+}
+
+// parseExDates parses the RFC3339 strings stored in recurrence_exdates
+func parseExDates(values pq.StringArray) ([]time.Time, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	exdates := make([]time.Time, len(values))
+	for i, value := range values {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recurrence exdate %q: %w", value, err)
+		}
+		exdates[i] = t
+	}
+	return exdates, nil
+}
+
+// scanEvents scans all rows selected with eventColumns
+func scanEvents(rows *sql.Rows) ([]*models.Event, error) {
+	events := []*models.Event{}
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
 // EventRepository handles event data operations
 type EventRepository struct {
 	db *sql.DB
@@ -20,8 +116,9 @@ func NewEventRepository(db *sql.DB) *EventRepository {
 // Create creates a new event
 func (r *EventRepository) Create(event *models.Event) error {
 	query := `
-		INSERT INTO events (title, description, start_time, end_time, location, creator_id, status)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO events (title, description, start_time, end_time, location, creator_id, status,
+		                    timezone, all_day, recurrence_rule, recurrence_exdates, parent_event_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		RETURNING id, created_at, updated_at
 	`
 
@@ -34,6 +131,11 @@ func (r *EventRepository) Create(event *models.Event) error {
 		event.Location,
 		event.CreatorID,
 		event.Status,
+		event.Timezone,
+		event.AllDay,
+		event.RecurrenceRule,
+		formatExDates(event.RecurrenceExDates),
+		event.ParentEventID,
 	).Scan(&event.ID, &event.CreatedAt, &event.UpdatedAt)
 
 	if err != nil {
@@ -46,25 +148,12 @@ func (r *EventRepository) Create(event *models.Event) error {
 // FindByID finds an event by ID
 func (r *EventRepository) FindByID(id int64) (*models.Event, error) {
 	query := `
-		SELECT id, title, description, start_time, end_time, location, creator_id, status, google_calendar_id, created_at, updated_at
+		SELECT ` + eventColumns + `
 		FROM events
 		WHERE id = $1
 	`
 
-	event := &models.Event{}
-	err := r.db.QueryRow(query, id).Scan(
-		&event.ID,
-		&event.Title,
-		&event.Description,
-		&event.StartTime,
-		&event.EndTime,
-		&event.Location,
-		&event.CreatorID,
-		&event.Status,
-		&event.GoogleCalendarID,
-		&event.CreatedAt,
-		&event.UpdatedAt,
-	)
+	event, err := scanEvent(r.db.QueryRow(query, id))
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("event not found")
@@ -84,7 +173,7 @@ func (r *EventRepository) FindByCreatorID(creatorID int64, status string) ([]*mo
 
 	if status != "" {
 		query = `
-			SELECT id, title, description, start_time, end_time, location, creator_id, status, created_at, updated_at
+			SELECT ` + eventColumns + `
 			FROM events
 			WHERE creator_id = $1 AND status = $2
 			ORDER BY start_time DESC
@@ -92,7 +181,7 @@ func (r *EventRepository) FindByCreatorID(creatorID int64, status string) ([]*mo
 		rows, err = r.db.Query(query, creatorID, status)
 	} else {
 		query = `
-			SELECT id, title, description, start_time, end_time, location, creator_id, status, created_at, updated_at
+			SELECT ` + eventColumns + `
 			FROM events
 			WHERE creator_id = $1
 			ORDER BY start_time DESC
@@ -105,28 +194,7 @@ func (r *EventRepository) FindByCreatorID(creatorID int64, status string) ([]*mo
 	}
 	defer rows.Close()
 
-	events := []*models.Event{}
-	for rows.Next() {
-		event := &models.Event{}
-		err := rows.Scan(
-			&event.ID,
-			&event.Title,
-			&event.Description,
-			&event.StartTime,
-			&event.EndTime,
-			&event.Location,
-			&event.CreatorID,
-			&event.Status,
-			&event.CreatedAt,
-			&event.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan event: %w", err)
-		}
-		events = append(events, event)
-	}
-
-	return events, nil
+	return scanEvents(rows)
 }
 
 // FindByParticipantID finds events where user is a participant
@@ -137,7 +205,7 @@ func (r *EventRepository) FindByParticipantID(userID int64, status string) ([]*m
 
 	if status != "" {
 		query = `
-			SELECT e.id, e.title, e.description, e.start_time, e.end_time, e.location, e.creator_id, e.status, e.created_at, e.updated_at
+			SELECT ` + prefixedEventColumns("e") + `
 			FROM events e
 			INNER JOIN event_participants ep ON e.id = ep.event_id
 			WHERE ep.user_id = $1 AND e.status = $2
@@ -146,7 +214,7 @@ func (r *EventRepository) FindByParticipantID(userID int64, status string) ([]*m
 		rows, err = r.db.Query(query, userID, status)
 	} else {
 		query = `
-			SELECT e.id, e.title, e.description, e.start_time, e.end_time, e.location, e.creator_id, e.status, e.created_at, e.updated_at
+			SELECT ` + prefixedEventColumns("e") + `
 			FROM events e
 			INNER JOIN event_participants ep ON e.id = ep.event_id
 			WHERE ep.user_id = $1
@@ -160,36 +228,16 @@ func (r *EventRepository) FindByParticipantID(userID int64, status string) ([]*m
 	}
 	defer rows.Close()
 
-	events := []*models.Event{}
-	for rows.Next() {
-		event := &models.Event{}
-		err := rows.Scan(
-			&event.ID,
-			&event.Title,
-			&event.Description,
-			&event.StartTime,
-			&event.EndTime,
-			&event.Location,
-			&event.CreatorID,
-			&event.Status,
-			&event.CreatedAt,
-			&event.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan event: %w", err)
-		}
-		events = append(events, event)
-	}
-
-	return events, nil
+	return scanEvents(rows)
 }
 
 // Update updates an event
 func (r *EventRepository) Update(event *models.Event) error {
 	query := `
 		UPDATE events
-		SET title = $1, description = $2, start_time = $3, end_time = $4, location = $5, status = $6
-		WHERE id = $7
+		SET title = $1, description = $2, start_time = $3, end_time = $4, location = $5, status = $6,
+		    timezone = $7, all_day = $8, recurrence_rule = $9, recurrence_exdates = $10
+		WHERE id = $11
 		RETURNING updated_at
 	`
 
@@ -201,6 +249,10 @@ func (r *EventRepository) Update(event *models.Event) error {
 		event.EndTime,
 		event.Location,
 		event.Status,
+		event.Timezone,
+		event.AllDay,
+		event.RecurrenceRule,
+		formatExDates(event.RecurrenceExDates),
 		event.ID,
 	).Scan(&event.UpdatedAt)
 
@@ -293,6 +345,48 @@ func (r *EventRepository) ConfirmParticipant(eventID, userID int64) error {
 	return nil
 }
 
+// FindUpcomingConfirmed finds confirmed events that may start in [from, to)
+// Recurring series that started before to are included so occurrences can be expanded.
+// All-day events start at local midnight, up to 14 hours before or after their stored UTC midnight.
+func (r *EventRepository) FindUpcomingConfirmed(from, to time.Time) ([]*models.Event, error) {
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE status = 'CONFIRMED'
+		  AND ((recurrence_rule IS NULL AND NOT all_day AND start_time >= $1 AND start_time < $2)
+		    OR (recurrence_rule IS NULL AND all_day
+		        AND start_time >= $1 - INTERVAL '14 hours' AND start_time < $2 + INTERVAL '14 hours')
+		    OR (recurrence_rule IS NOT NULL AND start_time < $2 + INTERVAL '14 hours'))
+		ORDER BY start_time ASC
+	`
+
+	rows, err := r.db.Query(query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find upcoming events: %w", err)
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+// HasParticipantInCreatedEvents checks if userID participates in an event created by creatorID
+func (r *EventRepository) HasParticipantInCreatedEvents(creatorID, userID int64) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM events e
+			JOIN event_participants ep ON ep.event_id = e.id
+			WHERE e.creator_id = $1 AND ep.user_id = $2
+		)
+	`
+
+	var exists bool
+	if err := r.db.QueryRow(query, creatorID, userID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check participant: %w", err)
+	}
+
+	return exists, nil
+}
+
 // FindParticipants finds all participants for an event
 func (r *EventRepository) FindParticipants(eventID int64) ([]int64, error) {
 	query := `SELECT user_id FROM event_participants WHERE event_id = $1`
@@ -315,6 +409,93 @@ func (r *EventRepository) FindParticipants(eventID int64) ([]int64, error) {
 	return userIDs, nil
 }
 
+// FindParticipations finds all of a user's event participations with the event title and start
+func (r *EventRepository) FindParticipations(userID int64) ([]*models.ParticipationRecord, error) {
+	query := `
+		SELECT ep.event_id, e.title, e.start_time, ep.status, ep.invite_method,
+		       ep.invited_at, ep.responded_at, COALESCE(ep.confirmed, false), ep.confirmed_at
+		FROM event_participants ep
+		INNER JOIN events e ON e.id = ep.event_id
+		WHERE ep.user_id = $1
+		ORDER BY e.start_time DESC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find participations: %w", err)
+	}
+	defer rows.Close()
+
+	records := []*models.ParticipationRecord{}
+	for rows.Next() {
+		record := &models.ParticipationRecord{}
+		if err := rows.Scan(
+			&record.EventID,
+			&record.EventTitle,
+			&record.StartTime,
+			&record.Status,
+			&record.InviteMethod,
+			&record.InvitedAt,
+			&record.RespondedAt,
+			&record.Confirmed,
+			&record.ConfirmedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan participation: %w", err)
+		}
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+// FindMemberEvents finds all events a user created or participates in, including canceled ones
+func (r *EventRepository) FindMemberEvents(userID int64) ([]*models.Event, error) {
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE creator_id = $1
+		   OR id IN (SELECT event_id FROM event_participants WHERE user_id = $1)
+		ORDER BY start_time, id
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find member events: %w", err)
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+// FindJoinedEventIDs finds the IDs of events a user created, participates in
+// or participated in before leaving or being removed
+func (r *EventRepository) FindJoinedEventIDs(userID int64) ([]int64, error) {
+	query := `
+		SELECT id FROM events WHERE creator_id = $1
+		UNION
+		SELECT event_id FROM event_participants WHERE user_id = $1
+		UNION
+		SELECT event_id FROM event_member_history WHERE user_id = $1
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find member events: %w", err)
+	}
+	defer rows.Close()
+
+	eventIDs := []int64{}
+	for rows.Next() {
+		var eventID int64
+		if err := rows.Scan(&eventID); err != nil {
+			return nil, fmt.Errorf("failed to scan event ID: %w", err)
+		}
+		eventIDs = append(eventIDs, eventID)
+	}
+
+	return eventIDs, rows.Err()
+}
+
 // IsUserParticipant checks if a user is a participant of an event
 func (r *EventRepository) IsUserParticipant(eventID, userID int64) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM event_participants WHERE event_id = $1 AND user_id = $2)`
@@ -328,60 +509,226 @@ func (r *EventRepository) IsUserParticipant(eventID, userID int64) (bool, error)
 	return exists, nil
 }
 
-// UpdateGoogleCalendarID updates the Google Calendar ID for an event
-func (r *EventRepository) UpdateGoogleCalendarID(eventID int64, calendarID string) error {
+// FindOccurrenceOverrides finds all per-occurrence overrides of a recurring event
+func (r *EventRepository) FindOccurrenceOverrides(eventID int64) ([]*models.EventOccurrenceOverride, error) {
 	query := `
-		UPDATE events
-		SET google_calendar_id = $1, updated_at = NOW()
-		WHERE id = $2
+		SELECT event_id, occurrence_start, title, description, start_time, end_time, location,
+		       is_canceled, updated_by, created_at, updated_at
+		FROM event_occurrence_overrides
+		WHERE event_id = $1
+		ORDER BY occurrence_start ASC
 	`
 
-	result, err := r.db.Exec(query, calendarID, eventID)
+	rows, err := r.db.Query(query, eventID)
 	if err != nil {
-		return fmt.Errorf("failed to update Google Calendar ID: %w", err)
+		return nil, fmt.Errorf("failed to find occurrence overrides: %w", err)
 	}
+	defer rows.Close()
 
-	rowsAffected, err := result.RowsAffected()
+	overrides := []*models.EventOccurrenceOverride{}
+	for rows.Next() {
+		override := &models.EventOccurrenceOverride{}
+		err := rows.Scan(
+			&override.EventID,
+			&override.OccurrenceStart,
+			&override.Title,
+			&override.Description,
+			&override.StartTime,
+			&override.EndTime,
+			&override.Location,
+			&override.IsCanceled,
+			&override.UpdatedBy,
+			&override.CreatedAt,
+			&override.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan occurrence override: %w", err)
+		}
+		overrides = append(overrides, override)
+	}
+
+	return overrides, nil
+}
+
+// FindOccurrenceOverride finds the override of a single occurrence
+// Returns nil, nil if the occurrence has not been modified
+func (r *EventRepository) FindOccurrenceOverride(eventID int64, occurrenceStart time.Time) (*models.EventOccurrenceOverride, error) {
+	query := `
+		SELECT event_id, occurrence_start, title, description, start_time, end_time, location,
+		       is_canceled, updated_by, created_at, updated_at
+		FROM event_occurrence_overrides
+		WHERE event_id = $1 AND occurrence_start = $2
+	`
+
+	override := &models.EventOccurrenceOverride{}
+	err := r.db.QueryRow(query, eventID, occurrenceStart).Scan(
+		&override.EventID,
+		&override.OccurrenceStart,
+		&override.Title,
+		&override.Description,
+		&override.StartTime,
+		&override.EndTime,
+		&override.Location,
+		&override.IsCanceled,
+		&override.UpdatedBy,
+		&override.CreatedAt,
+		&override.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return nil, fmt.Errorf("failed to find occurrence override: %w", err)
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("event not found")
+	return override, nil
+}
+
+// UpsertOccurrenceOverride creates or replaces the override of a single occurrence
+func (r *EventRepository) UpsertOccurrenceOverride(override *models.EventOccurrenceOverride) error {
+	query := `
+		INSERT INTO event_occurrence_overrides (event_id, occurrence_start, title, description, start_time, end_time,
+		                                        location, is_canceled, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (event_id, occurrence_start) DO UPDATE
+		SET title = EXCLUDED.title, description = EXCLUDED.description, start_time = EXCLUDED.start_time,
+		    end_time = EXCLUDED.end_time, location = EXCLUDED.location, is_canceled = EXCLUDED.is_canceled,
+		    updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		RETURNING created_at, updated_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		override.EventID,
+		override.OccurrenceStart,
+		override.Title,
+		override.Description,
+		override.StartTime,
+		override.EndTime,
+		override.Location,
+		override.IsCanceled,
+		override.UpdatedBy,
+	).Scan(&override.CreatedAt, &override.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save occurrence override: %w", err)
 	}
 
 	return nil
 }
 
-// FindByGoogleCalendarID finds an event by Google Calendar ID
-func (r *EventRepository) FindByGoogleCalendarID(calendarID string) (*models.Event, error) {
+// SaveOccurrenceResponse records a participant's response to a single occurrence
+func (r *EventRepository) SaveOccurrenceResponse(response *models.OccurrenceResponse) error {
 	query := `
-		SELECT id, title, description, start_time, end_time, location, creator_id, status, google_calendar_id, created_at, updated_at
-		FROM events
-		WHERE google_calendar_id = $1
+		INSERT INTO event_occurrence_responses (event_id, occurrence_start, user_id, status, responded_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (event_id, occurrence_start, user_id) DO UPDATE
+		SET status = EXCLUDED.status, responded_at = NOW()
+		RETURNING responded_at
 	`
 
-	event := &models.Event{}
-	err := r.db.QueryRow(query, calendarID).Scan(
-		&event.ID,
-		&event.Title,
-		&event.Description,
-		&event.StartTime,
-		&event.EndTime,
-		&event.Location,
-		&event.CreatorID,
-		&event.Status,
-		&event.GoogleCalendarID,
-		&event.CreatedAt,
-		&event.UpdatedAt,
-	)
+	err := r.db.QueryRow(
+		query,
+		response.EventID,
+		response.OccurrenceStart,
+		response.UserID,
+		response.Status,
+	).Scan(&response.RespondedAt)
 
-	if err == sql.ErrNoRows {
-		return nil, nil // Not found
+	if err != nil {
+		return fmt.Errorf("failed to save occurrence response: %w", err)
 	}
+
+	return nil
+}
+
+// FindOccurrenceResponses finds all per-occurrence responses of a recurring event
+func (r *EventRepository) FindOccurrenceResponses(eventID int64) ([]*models.OccurrenceResponse, error) {
+	query := `
+		SELECT event_id, occurrence_start, user_id, status, responded_at
+		FROM event_occurrence_responses
+		WHERE event_id = $1
+		ORDER BY occurrence_start ASC, user_id ASC
+	`
+
+	rows, err := r.db.Query(query, eventID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find event by Google Calendar ID: %w", err)
+		return nil, fmt.Errorf("failed to find occurrence responses: %w", err)
 	}
+	defer rows.Close()
 
-	return event, nil
+	responses := []*models.OccurrenceResponse{}
+	for rows.Next() {
+		response := &models.OccurrenceResponse{}
+		err := rows.Scan(
+			&response.EventID,
+			&response.OccurrenceStart,
+			&response.UserID,
+			&response.Status,
+			&response.RespondedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan occurrence response: %w", err)
+		}
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
+
+// ReassignOccurrences moves overrides, responses and attendance at or after since to another event
+// Used when a recurring series is split by a "this and following" edit: occurrence keys
+// are moved by shift (the new series' start change) and those in dropped are deleted
+func (r *EventRepository) ReassignOccurrences(fromEventID, toEventID int64, since time.Time, shift time.Duration, dropped []time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	droppedStarts := formatExDates(dropped)
+	if _, err := tx.Exec(`
+		DELETE FROM event_occurrence_overrides
+		WHERE event_id = $1 AND occurrence_start = ANY($2::timestamptz[])
+	`, fromEventID, droppedStarts); err != nil {
+		return fmt.Errorf("failed to delete occurrence overrides: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+		DELETE FROM event_occurrence_responses
+		WHERE event_id = $1 AND occurrence_start = ANY($2::timestamptz[])
+	`, fromEventID, droppedStarts); err != nil {
+		return fmt.Errorf("failed to delete occurrence responses: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE event_occurrence_overrides
+		SET event_id = $1, occurrence_start = occurrence_start + make_interval(secs => $4)
+		WHERE event_id = $2 AND occurrence_start >= $3
+	`, toEventID, fromEventID, since, shift.Seconds()); err != nil {
+		return fmt.Errorf("failed to reassign occurrence overrides: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE event_occurrence_responses
+		SET event_id = $1, occurrence_start = occurrence_start + make_interval(secs => $4)
+		WHERE event_id = $2 AND occurrence_start >= $3
+	`, toEventID, fromEventID, since, shift.Seconds()); err != nil {
+		return fmt.Errorf("failed to reassign occurrence responses: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE event_attendance
+		SET event_id = $1, occurrence_start = occurrence_start + make_interval(secs => $4)
+		WHERE event_id = $2 AND occurrence_start >= $3
+	`, toEventID, fromEventID, since, shift.Seconds()); err != nil {
+		return fmt.Errorf("failed to reassign occurrence attendance: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
 }
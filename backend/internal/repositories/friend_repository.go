@@ -0,0 +1,321 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/lib/pq"
+)
+
+// FriendRepository handles friendship data operations
+type FriendRepository struct {
+	db *sql.DB
+}
+
+// NewFriendRepository creates a new friend repository
+func NewFriendRepository(db *sql.DB) *FriendRepository {
+	return &FriendRepository{db: db}
+}
+
+// Find finds the friendship row from userID to friendID, nil if there is none
+func (r *FriendRepository) Find(userID, friendID int64) (*models.Friendship, error) {
+	query := `
+		SELECT id, user_id, friend_id, status, created_at, updated_at
+		FROM friendships
+		WHERE user_id = $1 AND friend_id = $2
+	`
+
+	friendship := &models.Friendship{}
+	err := r.db.QueryRow(query, userID, friendID).Scan(
+		&friendship.ID,
+		&friendship.UserID,
+		&friendship.FriendID,
+		&friendship.Status,
+		&friendship.CreatedAt,
+		&friendship.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find friendship: %w", err)
+	}
+
+	return friendship, nil
+}
+
+// CreateRequest creates a pending friend request from userID to friendID
+func (r *FriendRepository) CreateRequest(userID, friendID int64) error {
+	query := `
+		INSERT INTO friendships (user_id, friend_id, status)
+		VALUES ($1, $2, $3)
+	`
+
+	if _, err := r.db.Exec(query, userID, friendID, models.FriendshipStatusPending); err != nil {
+		return fmt.Errorf("failed to create friend request: %w", err)
+	}
+
+	return nil
+}
+
+// Accept accepts the pending request from requesterID to userID
+// The request becomes ACCEPTED and the reverse row is created (or replaces the
+// user's own pending request to the requester)
+func (r *FriendRepository) Accept(requesterID, userID int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE friendships SET status = $3, updated_at = NOW()
+		WHERE user_id = $1 AND friend_id = $2 AND status = $4
+	`, requesterID, userID, models.FriendshipStatusAccepted, models.FriendshipStatusPending)
+	if err != nil {
+		return fmt.Errorf("failed to accept friend request: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("friend request not found")
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO friendships (user_id, friend_id, status)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, friend_id) DO UPDATE
+		SET status = EXCLUDED.status, updated_at = NOW()
+	`, userID, requesterID, models.FriendshipStatusAccepted)
+	if err != nil {
+		return fmt.Errorf("failed to accept friend request: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteRequest deletes the pending request from userID to friendID
+func (r *FriendRepository) DeleteRequest(userID, friendID int64) error {
+	query := `DELETE FROM friendships WHERE user_id = $1 AND friend_id = $2 AND status = $3`
+
+	result, err := r.db.Exec(query, userID, friendID, models.FriendshipStatusPending)
+	if err != nil {
+		return fmt.Errorf("failed to delete friend request: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("friend request not found")
+	}
+
+	return nil
+}
+
+// DeleteFriendship removes an accepted friendship in both directions
+func (r *FriendRepository) DeleteFriendship(userID, friendID int64) error {
+	query := `
+		DELETE FROM friendships
+		WHERE status = $3
+		  AND ((user_id = $1 AND friend_id = $2) OR (user_id = $2 AND friend_id = $1))
+	`
+
+	result, err := r.db.Exec(query, userID, friendID, models.FriendshipStatusAccepted)
+	if err != nil {
+		return fmt.Errorf("failed to delete friendship: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("friend not found")
+	}
+
+	return nil
+}
+
+// Block blocks blockedID for userID
+// Any friendship or request between the two is removed; a block the other user
+// made is kept so unblocking on one side does not lift the other
+func (r *FriendRepository) Block(userID, blockedID int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO friendships (user_id, friend_id, status)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, friend_id) DO UPDATE
+		SET status = EXCLUDED.status, updated_at = NOW()
+	`, userID, blockedID, models.FriendshipStatusBlocked)
+	if err != nil {
+		return fmt.Errorf("failed to block user: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM friendships
+		WHERE user_id = $1 AND friend_id = $2 AND status <> $3
+	`, blockedID, userID, models.FriendshipStatusBlocked)
+	if err != nil {
+		return fmt.Errorf("failed to block user: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Unblock removes the block userID made on blockedID
+func (r *FriendRepository) Unblock(userID, blockedID int64) error {
+	query := `DELETE FROM friendships WHERE user_id = $1 AND friend_id = $2 AND status = $3`
+
+	result, err := r.db.Exec(query, userID, blockedID, models.FriendshipStatusBlocked)
+	if err != nil {
+		return fmt.Errorf("failed to unblock user: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user is not blocked")
+	}
+
+	return nil
+}
+
+// AreFriends checks if two users are friends
+func (r *FriendRepository) AreFriends(userID, otherID int64) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM friendships WHERE user_id = $1 AND friend_id = $2 AND status = $3)`
+
+	var exists bool
+	if err := r.db.QueryRow(query, userID, otherID, models.FriendshipStatusAccepted).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check friendship: %w", err)
+	}
+
+	return exists, nil
+}
+
+// IsBlocked checks if either user has blocked the other
+func (r *FriendRepository) IsBlocked(userID, otherID int64) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM friendships
+			WHERE status = $3
+			  AND ((user_id = $1 AND friend_id = $2) OR (user_id = $2 AND friend_id = $1))
+		)
+	`
+
+	var blocked bool
+	if err := r.db.QueryRow(query, userID, otherID, models.FriendshipStatusBlocked).Scan(&blocked); err != nil {
+		return false, fmt.Errorf("failed to check block: %w", err)
+	}
+
+	return blocked, nil
+}
+
+// FindRelations finds the friendship rows in either direction between userID and otherIDs
+func (r *FriendRepository) FindRelations(userID int64, otherIDs []int64) ([]*models.Friendship, error) {
+	if len(otherIDs) == 0 {
+		return []*models.Friendship{}, nil
+	}
+
+	query := `
+		SELECT id, user_id, friend_id, status, created_at, updated_at
+		FROM friendships
+		WHERE (user_id = $1 AND friend_id = ANY($2)) OR (friend_id = $1 AND user_id = ANY($2))
+	`
+
+	rows, err := r.db.Query(query, userID, pq.Array(otherIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find friendships: %w", err)
+	}
+	defer rows.Close()
+
+	friendships := []*models.Friendship{}
+	for rows.Next() {
+		friendship := &models.Friendship{}
+		err := rows.Scan(
+			&friendship.ID,
+			&friendship.UserID,
+			&friendship.FriendID,
+			&friendship.Status,
+			&friendship.CreatedAt,
+			&friendship.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan friendship: %w", err)
+		}
+		friendships = append(friendships, friendship)
+	}
+
+	return friendships, rows.Err()
+}
+
+// FindOutgoing finds the users userID has a relation with in the given status
+// (friends, sent requests or blocked users), newest first
+func (r *FriendRepository) FindOutgoing(userID int64, status models.FriendshipStatus) ([]*models.Friend, error) {
+	query := `
+		SELECT u.id, u.name, u.profile_image_url, f.status, f.updated_at
+		FROM friendships f
+		JOIN users u ON u.id = f.friend_id
+		WHERE f.user_id = $1 AND f.status = $2
+		ORDER BY f.updated_at DESC
+	`
+
+	return r.findFriends(query, userID, status)
+}
+
+// FindIncoming finds the users with a relation to userID in the given status
+// (received requests), newest first
+func (r *FriendRepository) FindIncoming(userID int64, status models.FriendshipStatus) ([]*models.Friend, error) {
+	query := `
+		SELECT u.id, u.name, u.profile_image_url, f.status, f.updated_at
+		FROM friendships f
+		JOIN users u ON u.id = f.user_id
+		WHERE f.friend_id = $1 AND f.status = $2
+		ORDER BY f.updated_at DESC
+	`
+
+	return r.findFriends(query, userID, status)
+}
+
+// findFriends runs a FindOutgoing/FindIncoming query
+func (r *FriendRepository) findFriends(query string, userID int64, status models.FriendshipStatus) ([]*models.Friend, error) {
+	rows, err := r.db.Query(query, userID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find friends: %w", err)
+	}
+	defer rows.Close()
+
+	friends := []*models.Friend{}
+	for rows.Next() {
+		friend := &models.Friend{}
+		if err := rows.Scan(&friend.UserID, &friend.Name, &friend.ProfileImageURL, &friend.Status, &friend.Since); err != nil {
+			return nil, fmt.Errorf("failed to scan friend: %w", err)
+		}
+		friends = append(friends, friend)
+	}
+
+	return friends, rows.Err()
+}
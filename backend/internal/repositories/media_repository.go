@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/lib/pq"
+)
+
+// mediaColumns lists the media_objects columns scanned by scanMedia
+const mediaColumns = `id, owner_id, purpose, event_id, status, content_type, size_bytes, width, height, has_thumbnail, created_at`
+
+// MediaRepository handles uploaded media metadata
+type MediaRepository struct {
+	db *sql.DB
+}
+
+// NewMediaRepository creates a new media repository
+func NewMediaRepository(db *sql.DB) *MediaRepository {
+	return &MediaRepository{db: db}
+}
+
+// scanMedia scans a row selected with mediaColumns
+func scanMedia(row rowScanner) (*models.MediaObject, error) {
+	media := &models.MediaObject{}
+	err := row.Scan(
+		&media.ID,
+		&media.OwnerID,
+		&media.Purpose,
+		&media.EventID,
+		&media.Status,
+		&media.ContentType,
+		&media.SizeBytes,
+		&media.Width,
+		&media.Height,
+		&media.HasThumbnail,
+		&media.CreatedAt,
+	)
+	return media, err
+}
+
+// Create creates a media object
+func (r *MediaRepository) Create(media *models.MediaObject) error {
+	query := `
+		INSERT INTO media_objects (id, owner_id, purpose, event_id, status, content_type, size_bytes, width, height, has_thumbnail)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING created_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		media.ID,
+		media.OwnerID,
+		media.Purpose,
+		media.EventID,
+		media.Status,
+		media.ContentType,
+		media.SizeBytes,
+		media.Width,
+		media.Height,
+		media.HasThumbnail,
+	).Scan(&media.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create media: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID finds a media object by ID
+func (r *MediaRepository) FindByID(id uuid.UUID) (*models.MediaObject, error) {
+	query := `SELECT ` + mediaColumns + ` FROM media_objects WHERE id = $1`
+
+	media, err := scanMedia(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("media not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find media: %w", err)
+	}
+
+	return media, nil
+}
+
+// FindByIDs finds the media objects with the given IDs; missing IDs are skipped
+func (r *MediaRepository) FindByIDs(ids []uuid.UUID) ([]*models.MediaObject, error) {
+	if len(ids) == 0 {
+		return []*models.MediaObject{}, nil
+	}
+
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+
+	query := `SELECT ` + mediaColumns + ` FROM media_objects WHERE id = ANY($1::uuid[])`
+
+	rows, err := r.db.Query(query, pq.Array(values))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find media: %w", err)
+	}
+	defer rows.Close()
+
+	objects := []*models.MediaObject{}
+	for rows.Next() {
+		media, err := scanMedia(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan media: %w", err)
+		}
+		objects = append(objects, media)
+	}
+
+	return objects, rows.Err()
+}
+
+// MarkReady stores the processed metadata of a pending upload and marks it ready
+func (r *MediaRepository) MarkReady(media *models.MediaObject) error {
+	query := `
+		UPDATE media_objects
+		SET status = $2, content_type = $3, size_bytes = $4, width = $5, height = $6, has_thumbnail = $7
+		WHERE id = $1 AND status = $8
+	`
+
+	result, err := r.db.Exec(
+		query,
+		media.ID,
+		models.MediaStatusReady,
+		media.ContentType,
+		media.SizeBytes,
+		media.Width,
+		media.Height,
+		media.HasThumbnail,
+		models.MediaStatusPending,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update media: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("upload already completed")
+	}
+
+	media.Status = models.MediaStatusReady
+	return nil
+}
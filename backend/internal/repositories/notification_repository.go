@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/lib/pq"
+)
+
+// NotificationRepository handles in-app notification database operations
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// CreateForUsers stores the same notification for each user and returns the created entries
+func (r *NotificationRepository) CreateForUsers(userIDs []int64, notification *models.Notification) ([]*models.Notification, error) {
+	if len(userIDs) == 0 {
+		return []*models.Notification{}, nil
+	}
+
+	data, err := json.Marshal(notification.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal notification data: %w", err)
+	}
+
+	query := `
+		INSERT INTO notifications (user_id, type, event_id, actor_id, data)
+		SELECT user_id, $2, $3, $4, $5
+		FROM unnest($1::bigint[]) AS user_id
+		RETURNING id, user_id, created_at
+	`
+
+	rows, err := r.db.Query(query, pq.Array(userIDs), notification.Type, notification.EventID, notification.ActorID, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create notifications: %w", err)
+	}
+	defer rows.Close()
+
+	created := []*models.Notification{}
+	for rows.Next() {
+		entry := *notification
+		if err := rows.Scan(&entry.ID, &entry.UserID, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		created = append(created, &entry)
+	}
+
+	return created, nil
+}
+
+// FindByUserID finds a user's notifications with IDs below beforeID (0 for the newest), newest first
+func (r *NotificationRepository) FindByUserID(userID, beforeID int64, limit int, unreadOnly bool) ([]*models.Notification, error) {
+	query := `
+		SELECT id, user_id, type, event_id, actor_id, data, read_at, created_at
+		FROM notifications
+		WHERE user_id = $1
+		  AND ($2 = 0 OR id < $2)
+		  AND (NOT $3 OR read_at IS NULL)
+		ORDER BY id DESC
+		LIMIT $4
+	`
+
+	rows, err := r.db.Query(query, userID, beforeID, unreadOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notifications: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := []*models.Notification{}
+	for rows.Next() {
+		notification := &models.Notification{}
+		var data []byte
+		err := rows.Scan(
+			&notification.ID,
+			&notification.UserID,
+			&notification.Type,
+			&notification.EventID,
+			&notification.ActorID,
+			&data,
+			&notification.ReadAt,
+			&notification.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		if err := json.Unmarshal(data, &notification.Data); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal notification data: %w", err)
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, nil
+}
+
+// CountUnread counts a user's unread notifications
+func (r *NotificationRepository) CountUnread(userID int64) (int, error) {
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`
+
+	var count int
+	if err := r.db.QueryRow(query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	return count, nil
+}
+
+// MarkRead marks one of a user's notifications as read
+func (r *NotificationRepository) MarkRead(userID, notificationID int64) error {
+	query := `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2
+	`
+
+	result, err := r.db.Exec(query, notificationID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("notification not found")
+	}
+
+	return nil
+}
+
+// MarkAllRead marks all of a user's notifications as read
+func (r *NotificationRepository) MarkAllRead(userID int64) (int64, error) {
+	query := `UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`
+
+	result, err := r.db.Exec(query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -24,7 +24,7 @@ func NewOAuthRepository(db *sql.DB) *OAuthRepository {
 func (r *OAuthRepository) FindByProviderUserID(provider models.OAuthProvider, providerUserID string) (*models.OAuthAccount, error) {
 	query := `
 		SELECT id, user_id, provider, provider_user_id, email, name, picture_url,
-		       access_token, refresh_token, token_expiry, scopes,
+		       access_token, refresh_token, token_expiry, scopes, reconsent_required_at,
 		       created_at, updated_at
 		FROM oauth_accounts
 		WHERE provider = $1 AND provider_user_id = $2
@@ -44,6 +44,7 @@ func (r *OAuthRepository) FindByProviderUserID(provider models.OAuthProvider, pr
 		&account.RefreshToken,
 		&account.TokenExpiry,
 		&scopes,
+		&account.ReconsentRequiredAt,
 		&account.CreatedAt,
 		&account.UpdatedAt,
 	)
@@ -63,7 +64,7 @@ func (r *OAuthRepository) FindByProviderUserID(provider models.OAuthProvider, pr
 func (r *OAuthRepository) FindByUserID(userID int64) ([]*models.OAuthAccount, error) {
 	query := `
 		SELECT id, user_id, provider, provider_user_id, email, name, picture_url,
-		       access_token, refresh_token, token_expiry, scopes,
+		       access_token, refresh_token, token_expiry, scopes, reconsent_required_at,
 		       created_at, updated_at
 		FROM oauth_accounts
 		WHERE user_id = $1
@@ -92,6 +93,7 @@ func (r *OAuthRepository) FindByUserID(userID int64) ([]*models.OAuthAccount, er
 			&account.RefreshToken,
 			&account.TokenExpiry,
 			&scopes,
+			&account.ReconsentRequiredAt,
 			&account.CreatedAt,
 			&account.UpdatedAt,
 		)
@@ -202,10 +204,12 @@ func (r *OAuthRepository) DeleteByUserIDAndProvider(userID int64, provider model
 }
 
 // UpdateTokens updates OAuth tokens for an account
+// New tokens mean the user consented again, so the re-consent mark is cleared
 func (r *OAuthRepository) UpdateTokens(accountID int64, accessToken, refreshToken *string, tokenExpiry *time.Time, scopes []string) error {
 	query := `
 		UPDATE oauth_accounts
-		SET access_token = $1, refresh_token = $2, token_expiry = $3, scopes = $4, updated_at = NOW()
+		SET access_token = $1, refresh_token = $2, token_expiry = $3, scopes = $4,
+		    reconsent_required_at = NULL, updated_at = NOW()
 		WHERE id = $5
 	`
 
@@ -226,11 +230,27 @@ func (r *OAuthRepository) UpdateTokens(accountID int64, accessToken, refreshToke
 	return nil
 }
 
+// MarkReconsentRequired records that an account's refresh token was revoked
+// The first detection time is kept.
+func (r *OAuthRepository) MarkReconsentRequired(accountID int64) error {
+	query := `
+		UPDATE oauth_accounts
+		SET reconsent_required_at = COALESCE(reconsent_required_at, NOW()), updated_at = NOW()
+		WHERE id = $1
+	`
+
+	if _, err := r.db.Exec(query, accountID); err != nil {
+		return fmt.Errorf("failed to mark OAuth account for re-consent: %w", err)
+	}
+
+	return nil
+}
+
 // FindByUserIDAndProvider finds an OAuth account by user ID and provider
 func (r *OAuthRepository) FindByUserIDAndProvider(userID int64, provider models.OAuthProvider) (*models.OAuthAccount, error) {
 	query := `
 		SELECT id, user_id, provider, provider_user_id, email, name, picture_url,
-		       access_token, refresh_token, token_expiry, scopes,
+		       access_token, refresh_token, token_expiry, scopes, reconsent_required_at,
 		       created_at, updated_at
 		FROM oauth_accounts
 		WHERE user_id = $1 AND provider = $2
@@ -250,6 +270,7 @@ func (r *OAuthRepository) FindByUserIDAndProvider(userID int64, provider models.
 		&account.RefreshToken,
 		&account.TokenExpiry,
 		&scopes,
+		&account.ReconsentRequiredAt,
 		&account.CreatedAt,
 		&account.UpdatedAt,
 	)
@@ -269,7 +290,7 @@ func (r *OAuthRepository) FindByUserIDAndProvider(userID int64, provider models.
 func (r *OAuthRepository) FindAccountsWithCalendarScope() ([]*models.OAuthAccount, error) {
 	query := `
 		SELECT id, user_id, provider, provider_user_id, email, name, picture_url,
-		       access_token, refresh_token, token_expiry, scopes,
+		       access_token, refresh_token, token_expiry, scopes, reconsent_required_at,
 		       created_at, updated_at
 		FROM oauth_accounts
 		WHERE 'https://www.googleapis.com/auth/calendar' = ANY(scopes)
@@ -298,6 +319,7 @@ func (r *OAuthRepository) FindAccountsWithCalendarScope() ([]*models.OAuthAccoun
 			&account.RefreshToken,
 			&account.TokenExpiry,
 			&scopes,
+			&account.ReconsentRequiredAt,
 			&account.CreatedAt,
 			&account.UpdatedAt,
 		)
@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+// PollRepository handles event time slot poll data operations
+type PollRepository struct {
+	db *sql.DB
+}
+
+// NewPollRepository creates a new poll repository
+func NewPollRepository(db *sql.DB) *PollRepository {
+	return &PollRepository{db: db}
+}
+
+// ReplaceSlots replaces all candidate slots of an event (existing votes are removed)
+func (r *PollRepository) ReplaceSlots(eventID int64, slots []*models.TimeSlot) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM event_time_slots WHERE event_id = $1`, eventID); err != nil {
+		return fmt.Errorf("failed to delete time slots: %w", err)
+	}
+
+	query := `
+		INSERT INTO event_time_slots (event_id, start_time, end_time)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	for _, slot := range slots {
+		slot.EventID = eventID
+		if err := tx.QueryRow(query, eventID, slot.StartTime, slot.EndTime).Scan(&slot.ID, &slot.CreatedAt); err != nil {
+			return fmt.Errorf("failed to create time slot: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// FindSlotsByEventID finds all candidate slots of an event
+func (r *PollRepository) FindSlotsByEventID(eventID int64) ([]*models.TimeSlot, error) {
+	query := `
+		SELECT id, event_id, start_time, end_time, created_at
+		FROM event_time_slots
+		WHERE event_id = $1
+		ORDER BY start_time ASC
+	`
+
+	rows, err := r.db.Query(query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find time slots: %w", err)
+	}
+	defer rows.Close()
+
+	slots := []*models.TimeSlot{}
+	for rows.Next() {
+		slot := &models.TimeSlot{}
+		if err := rows.Scan(&slot.ID, &slot.EventID, &slot.StartTime, &slot.EndTime, &slot.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan time slot: %w", err)
+		}
+		slots = append(slots, slot)
+	}
+
+	return slots, nil
+}
+
+// UpsertVote creates or updates a participant's vote for a slot
+func (r *PollRepository) UpsertVote(vote *models.TimeSlotVote) error {
+	query := `
+		INSERT INTO event_time_slot_votes (slot_id, user_id, choice, voted_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (slot_id, user_id) DO UPDATE
+		SET choice = EXCLUDED.choice, voted_at = NOW()
+		RETURNING voted_at
+	`
+
+	err := r.db.QueryRow(query, vote.SlotID, vote.UserID, vote.Choice).Scan(&vote.VotedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save vote: %w", err)
+	}
+
+	return nil
+}
+
+// FindVotesByEventID finds all votes on the candidate slots of an event
+func (r *PollRepository) FindVotesByEventID(eventID int64) ([]*models.TimeSlotVote, error) {
+	query := `
+		SELECT v.slot_id, v.user_id, v.choice, v.voted_at
+		FROM event_time_slot_votes v
+		INNER JOIN event_time_slots s ON s.id = v.slot_id
+		WHERE s.event_id = $1
+		ORDER BY v.voted_at ASC
+	`
+
+	rows, err := r.db.Query(query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find votes: %w", err)
+	}
+	defer rows.Close()
+
+	votes := []*models.TimeSlotVote{}
+	for rows.Next() {
+		vote := &models.TimeSlotVote{}
+		if err := rows.Scan(&vote.SlotID, &vote.UserID, &vote.Choice, &vote.VotedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan vote: %w", err)
+		}
+		votes = append(votes, vote)
+	}
+
+	return votes, nil
+}
@@ -0,0 +1,175 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/lib/pq"
+)
+
+// ReminderRepository handles scheduled event reminder data operations
+type ReminderRepository struct {
+	db *sql.DB
+}
+
+// NewReminderRepository creates a new reminder repository
+func NewReminderRepository(db *sql.DB) *ReminderRepository {
+	return &ReminderRepository{db: db}
+}
+
+// CreateIfAbsent schedules a reminder unless the same one already exists
+// Returns true if a new reminder was created
+func (r *ReminderRepository) CreateIfAbsent(reminder *models.EventReminder) (bool, error) {
+	query := `
+		INSERT INTO event_reminders (event_id, user_id, event_start, minutes_before, remind_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (event_id, user_id, event_start, minutes_before) DO NOTHING
+		RETURNING id, status, created_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		reminder.EventID,
+		reminder.UserID,
+		reminder.EventStart,
+		reminder.MinutesBefore,
+		reminder.RemindAt,
+	).Scan(&reminder.ID, &reminder.Status, &reminder.CreatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to create reminder: %w", err)
+	}
+
+	return true, nil
+}
+
+// FindRecipients returns the creator and the participants who have not declined
+func (r *ReminderRepository) FindRecipients(eventID int64) ([]int64, error) {
+	query := `
+		SELECT creator_id FROM events WHERE id = $1
+		UNION
+		SELECT user_id FROM event_participants WHERE event_id = $1 AND status <> 'DECLINED'
+	`
+
+	rows, err := r.db.Query(query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find reminder recipients: %w", err)
+	}
+	defer rows.Close()
+
+	userIDs := []int64{}
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan recipient: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, nil
+}
+
+// ClaimDue locks due reminders of confirmed events and passes them to send
+// Reminders are marked SENT only if send succeeds; rows locked by another worker are skipped
+func (r *ReminderRepository) ClaimDue(now time.Time, limit int, send func([]*models.EventReminder) error) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT r.id, r.event_id, r.user_id, r.event_start, r.minutes_before, r.remind_at, r.status, r.sent_at, r.created_at
+		FROM event_reminders r
+		INNER JOIN events e ON e.id = r.event_id
+		WHERE r.status = 'PENDING' AND r.remind_at <= $1 AND r.event_start > $1
+		  AND e.status = 'CONFIRMED'
+		ORDER BY r.remind_at ASC
+		LIMIT $2
+		FOR UPDATE OF r SKIP LOCKED
+	`
+
+	rows, err := tx.Query(query, now, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find due reminders: %w", err)
+	}
+
+	reminders := []*models.EventReminder{}
+	ids := []int64{}
+	for rows.Next() {
+		reminder := &models.EventReminder{}
+		err := rows.Scan(
+			&reminder.ID,
+			&reminder.EventID,
+			&reminder.UserID,
+			&reminder.EventStart,
+			&reminder.MinutesBefore,
+			&reminder.RemindAt,
+			&reminder.Status,
+			&reminder.SentAt,
+			&reminder.CreatedAt,
+		)
+		if err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan reminder: %w", err)
+		}
+		reminders = append(reminders, reminder)
+		ids = append(ids, reminder.ID)
+	}
+	rows.Close()
+
+	if len(reminders) == 0 {
+		return 0, nil
+	}
+
+	if err := send(reminders); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec(`UPDATE event_reminders SET status = 'SENT', sent_at = NOW() WHERE id = ANY($1)`, pq.Array(ids)); err != nil {
+		return 0, fmt.Errorf("failed to mark reminders sent: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(reminders), nil
+}
+
+// SkipStarted marks pending reminders of events that already started as SKIPPED
+func (r *ReminderRepository) SkipStarted(now time.Time) error {
+	query := `UPDATE event_reminders SET status = 'SKIPPED' WHERE status = 'PENDING' AND event_start <= $1`
+
+	if _, err := r.db.Exec(query, now); err != nil {
+		return fmt.Errorf("failed to skip started reminders: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePendingByEventID removes unsent reminders of an event so they are planned again
+func (r *ReminderRepository) DeletePendingByEventID(eventID int64) error {
+	query := `DELETE FROM event_reminders WHERE event_id = $1 AND status = 'PENDING'`
+
+	if _, err := r.db.Exec(query, eventID); err != nil {
+		return fmt.Errorf("failed to delete pending reminders: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePendingByUserID removes a user's unsent reminders so they are planned again
+func (r *ReminderRepository) DeletePendingByUserID(userID int64) error {
+	query := `DELETE FROM event_reminders WHERE user_id = $1 AND status = 'PENDING'`
+
+	if _, err := r.db.Exec(query, userID); err != nil {
+		return fmt.Errorf("failed to delete pending reminders: %w", err)
+	}
+
+	return nil
+}
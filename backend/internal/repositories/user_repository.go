@@ -14,6 +14,36 @@ type UserRepository struct {
 	db *sql.DB
 }
 
+// userColumns is the column list shared by all user selects (see scanUser)
+const userColumns = `id, phone, name, email, profile_image_url, region, interests, timezone, language, role, COALESCE(status, 'ACTIVE'), reliability_score, reminder_minutes, deletion_scheduled_at, created_at, updated_at`
+
+// scanUser scans a row selected with userColumns
+func scanUser(row rowScanner) (*models.User, error) {
+	user := &models.User{}
+	err := row.Scan(
+		&user.ID,
+		&user.Phone,
+		&user.Name,
+		&user.Email,
+		&user.ProfileImageURL,
+		&user.Region,
+		pq.Array(&user.Interests),
+		&user.Timezone,
+		&user.Language,
+		&user.Role,
+		&user.Status,
+		&user.ReliabilityScore,
+		pq.Array(&user.ReminderMinutes),
+		&user.DeletionScheduledAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
 // NewUserRepository creates a new user repository
 func NewUserRepository(db *sql.DB) *UserRepository {
 	return &UserRepository{db: db}
@@ -24,7 +54,7 @@ func (r *UserRepository) Create(user *models.User) error {
 	query := `
 		INSERT INTO users (phone, name, email, profile_image_url, region, interests, timezone, language, role)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		RETURNING id, created_at, updated_at
+		RETURNING id, status, created_at, updated_at
 	`
 
 	err := r.db.QueryRow(
@@ -38,7 +68,7 @@ func (r *UserRepository) Create(user *models.User) error {
 		user.Timezone,
 		user.Language,
 		user.Role,
-	).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	).Scan(&user.ID, &user.Status, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
 		return fmt.Errorf("failed to create user: %w", err)
@@ -50,27 +80,12 @@ func (r *UserRepository) Create(user *models.User) error {
 // FindByID finds a user by ID
 func (r *UserRepository) FindByID(id int64) (*models.User, error) {
 	query := `
-		SELECT id, phone, name, email, profile_image_url, region, interests, timezone, language, role, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE id = $1
 	`
 
-	user := &models.User{}
-	err := r.db.QueryRow(query, id).Scan(
-		&user.ID,
-		&user.Phone,
-		&user.Name,
-		&user.Email,
-		&user.ProfileImageURL,
-		&user.Region,
-		pq.Array(&user.Interests),
-		&user.Timezone,
-		&user.Language,
-		&user.Role,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
-
+	user, err := scanUser(r.db.QueryRow(query, id))
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("user not found")
 	}
@@ -84,27 +99,12 @@ func (r *UserRepository) FindByID(id int64) (*models.User, error) {
 // FindByPhone finds a user by phone number
 func (r *UserRepository) FindByPhone(phone string) (*models.User, error) {
 	query := `
-		SELECT id, phone, name, email, profile_image_url, region, interests, timezone, language, role, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE phone = $1
 	`
 
-	user := &models.User{}
-	err := r.db.QueryRow(query, phone).Scan(
-		&user.ID,
-		&user.Phone,
-		&user.Name,
-		&user.Email,
-		&user.ProfileImageURL,
-		&user.Region,
-		pq.Array(&user.Interests),
-		&user.Timezone,
-		&user.Language,
-		&user.Role,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
-
+	user, err := scanUser(r.db.QueryRow(query, phone))
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("user not found")
 	}
@@ -115,6 +115,37 @@ func (r *UserRepository) FindByPhone(phone string) (*models.User, error) {
 	return user, nil
 }
 
+// FindByPhoneHashes finds active users whose phone_hash (SHA-256 of the E.164 number) is in hashes
+// Only the fields a contact match shows are loaded
+func (r *UserRepository) FindByPhoneHashes(hashes []string) ([]*models.ContactMatch, error) {
+	if len(hashes) == 0 {
+		return []*models.ContactMatch{}, nil
+	}
+
+	query := `
+		SELECT phone_hash, id, name, profile_image_url
+		FROM users
+		WHERE phone_hash = ANY($1) AND COALESCE(status, 'ACTIVE') = 'ACTIVE'
+	`
+
+	rows, err := r.db.Query(query, pq.Array(hashes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users by phone hash: %w", err)
+	}
+	defer rows.Close()
+
+	matches := []*models.ContactMatch{}
+	for rows.Next() {
+		match := &models.ContactMatch{}
+		if err := rows.Scan(&match.PhoneHash, &match.UserID, &match.Name, &match.ProfileImageURL); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		matches = append(matches, match)
+	}
+
+	return matches, rows.Err()
+}
+
 // Update updates a user
 func (r *UserRepository) Update(user *models.User) error {
 	query := `
@@ -144,6 +175,17 @@ func (r *UserRepository) Update(user *models.User) error {
 	return nil
 }
 
+// UpdateReminderMinutes updates a user's reminder preferences
+func (r *UserRepository) UpdateReminderMinutes(userID int64, minutes []int64) error {
+	query := `UPDATE users SET reminder_minutes = $1, updated_at = NOW() WHERE id = $2`
+
+	if _, err := r.db.Exec(query, pq.Array(minutes), userID); err != nil {
+		return fmt.Errorf("failed to update reminder preferences: %w", err)
+	}
+
+	return nil
+}
+
 // Delete deletes a user
 func (r *UserRepository) Delete(id int64) error {
 	query := `DELETE FROM users WHERE id = $1`
@@ -165,6 +207,274 @@ func (r *UserRepository) Delete(id int64) error {
 	return nil
 }
 
+// ScheduleDeletion schedules a user's account deletion at the given time and returns the schedule
+// An already scheduled deletion keeps its original time, so repeated requests do not extend the grace period
+func (r *UserRepository) ScheduleDeletion(userID int64, at time.Time) (time.Time, error) {
+	query := `
+		UPDATE users
+		SET deletion_scheduled_at = COALESCE(deletion_scheduled_at, $2), updated_at = NOW()
+		WHERE id = $1 AND COALESCE(status, 'ACTIVE') <> 'DELETED'
+		RETURNING deletion_scheduled_at
+	`
+
+	var scheduledAt time.Time
+	err := r.db.QueryRow(query, userID, at).Scan(&scheduledAt)
+	if err == sql.ErrNoRows {
+		return time.Time{}, fmt.Errorf("user not found")
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to schedule account deletion: %w", err)
+	}
+
+	return scheduledAt, nil
+}
+
+// CancelDeletion cancels a user's scheduled account deletion
+func (r *UserRepository) CancelDeletion(userID int64) error {
+	query := `
+		UPDATE users
+		SET deletion_scheduled_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND deletion_scheduled_at IS NOT NULL
+	`
+
+	result, err := r.db.Exec(query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to cancel account deletion: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("account deletion is not scheduled")
+	}
+
+	return nil
+}
+
+// FindDueDeletions finds up to limit users whose grace period ended before now
+func (r *UserRepository) FindDueDeletions(now time.Time, limit int) ([]int64, error) {
+	query := `
+		SELECT id FROM users
+		WHERE deletion_scheduled_at <= $1
+		ORDER BY deletion_scheduled_at
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find due account deletions: %w", err)
+	}
+	defer rows.Close()
+
+	userIDs := []int64{}
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, rows.Err()
+}
+
+// anonymizeUserStatements remove what identifies or reaches user $1
+// Events, participations and attendance stay for the other members, tied to the anonymized row
+var anonymizeUserStatements = []string{
+	`DELETE FROM friendships WHERE user_id = $1 OR friend_id = $1`,
+	`DELETE FROM oauth_accounts WHERE user_id = $1`,
+	`DELETE FROM refresh_tokens WHERE user_id = $1`,
+	`DELETE FROM device_tokens WHERE user_id = $1`,
+	`DELETE FROM calendar_feeds WHERE user_id = $1`,
+	`DELETE FROM calendar_event_mappings WHERE user_id = $1`,
+	`DELETE FROM calendar_sync_channels WHERE user_id = $1`,
+	`DELETE FROM notifications WHERE user_id = $1`,
+	`DELETE FROM event_reminders WHERE user_id = $1 AND status = 'PENDING'`,
+	`DELETE FROM media_objects WHERE owner_id = $1 AND purpose = 'AVATAR'`,
+	`UPDATE event_invite_links SET is_active = false WHERE created_by = $1`,
+}
+
+// Anonymize erases the personal data of a user whose deletion is due and marks them DELETED
+// The row is kept because other users' events and chats still reference it.
+// Nothing is changed if the deletion was canceled or is not due at now.
+func (r *UserRepository) Anonymize(userID int64, now time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRow(`SELECT id FROM users WHERE id = $1 AND deletion_scheduled_at <= $2 FOR UPDATE`, userID, now).Scan(&id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("account deletion is not due")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find user to anonymize: %w", err)
+	}
+
+	for _, statement := range anonymizeUserStatements {
+		if _, err := tx.Exec(statement, userID); err != nil {
+			return fmt.Errorf("failed to delete user data: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE users
+		SET phone = $2, name = NULL, email = NULL, profile_image_url = NULL, region = NULL,
+		    interests = '{}', reliability_score = NULL, status = 'DELETED',
+		    deletion_scheduled_at = NULL, updated_at = NOW()
+		WHERE id = $1
+	`, userID, fmt.Sprintf("%s%d", models.DeletedPhonePrefix, userID))
+	if err != nil {
+		return fmt.Errorf("failed to anonymize user: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// mergeUserStatements move everything owned by user $1 (source) to user $2 (target)
+// Rows the target already has an equivalent of are dropped from the source first,
+// so primary keys and unique constraints hold
+var mergeUserStatements = []string{
+	// Events and participations
+	`UPDATE events SET creator_id = $2 WHERE creator_id = $1`,
+	`UPDATE event_participants t
+		SET status = s.status, confirmed = s.confirmed, confirmed_at = s.confirmed_at, responded_at = s.responded_at
+		FROM event_participants s
+		WHERE t.user_id = $2 AND s.user_id = $1 AND t.event_id = s.event_id
+		  AND t.status <> 'ACCEPTED' AND s.status = 'ACCEPTED'`,
+	`DELETE FROM event_participants s USING event_participants t
+		WHERE s.user_id = $1 AND t.user_id = $2 AND s.event_id = t.event_id`,
+	`UPDATE event_participants SET user_id = $2 WHERE user_id = $1`,
+	`UPDATE event_participants SET invited_by = $2 WHERE invited_by = $1`,
+	`DELETE FROM event_member_history s USING event_member_history t
+		WHERE s.user_id = $1 AND t.user_id = $2 AND s.event_id = t.event_id`,
+	`UPDATE event_member_history SET user_id = $2 WHERE user_id = $1`,
+	`UPDATE event_invite_links SET created_by = $2 WHERE created_by = $1`,
+
+	// Recurring event responses and overrides
+	`DELETE FROM event_occurrence_responses s USING event_occurrence_responses t
+		WHERE s.user_id = $1 AND t.user_id = $2 AND s.event_id = t.event_id AND s.occurrence_start = t.occurrence_start`,
+	`UPDATE event_occurrence_responses SET user_id = $2 WHERE user_id = $1`,
+	`UPDATE event_occurrence_overrides SET updated_by = $2 WHERE updated_by = $1`,
+
+	// Time poll votes
+	`DELETE FROM event_time_slot_votes s USING event_time_slot_votes t
+		WHERE s.user_id = $1 AND t.user_id = $2 AND s.slot_id = t.slot_id`,
+	`UPDATE event_time_slot_votes SET user_id = $2 WHERE user_id = $1`,
+
+	// Attendance
+	`DELETE FROM event_attendance s USING event_attendance t
+		WHERE s.user_id = $1 AND t.user_id = $2 AND s.event_id = t.event_id
+		AND s.occurrence_start IS NOT DISTINCT FROM t.occurrence_start`,
+	`UPDATE event_attendance SET user_id = $2 WHERE user_id = $1`,
+	`UPDATE event_attendance SET recorded_by = $2 WHERE recorded_by = $1`,
+
+	// Pending reminders are replanned with the target's preferences
+	`DELETE FROM event_reminders WHERE user_id = $1 AND status = 'PENDING'`,
+	`DELETE FROM event_reminders s USING event_reminders t
+		WHERE s.user_id = $1 AND t.user_id = $2 AND s.event_id = t.event_id
+		  AND s.event_start = t.event_start AND s.minutes_before = t.minutes_before`,
+	`UPDATE event_reminders SET user_id = $2 WHERE user_id = $1`,
+
+	// Friendships, dropping any between the two accounts
+	`DELETE FROM friendships
+		WHERE (user_id = $1 AND friend_id = $2) OR (user_id = $2 AND friend_id = $1)`,
+	`DELETE FROM friendships s USING friendships t
+		WHERE s.user_id = $1 AND t.user_id = $2 AND s.friend_id = t.friend_id`,
+	`DELETE FROM friendships s USING friendships t
+		WHERE s.friend_id = $1 AND t.friend_id = $2 AND s.user_id = t.user_id`,
+	`UPDATE friendships SET user_id = $2 WHERE user_id = $1`,
+	`UPDATE friendships SET friend_id = $2 WHERE friend_id = $1`,
+
+	// Login methods, devices and inbox
+	`UPDATE oauth_accounts SET user_id = $2, updated_at = NOW() WHERE user_id = $1`,
+	`UPDATE device_tokens SET user_id = $2 WHERE user_id = $1`,
+	`UPDATE notifications SET user_id = $2 WHERE user_id = $1`,
+	`UPDATE notifications SET actor_id = $2 WHERE actor_id = $1`,
+	`UPDATE audit_logs SET admin_id = $2 WHERE admin_id = $1`,
+
+	// Calendar copies and the push channel follow the Google account they were written with
+	// (AccountService stops the source's channel first when the target has its own)
+	`DELETE FROM calendar_event_mappings s USING calendar_event_mappings t
+	 WHERE s.user_id = $1 AND t.user_id = $2 AND s.event_id = t.event_id AND s.provider = t.provider`,
+	`UPDATE calendar_event_mappings SET user_id = $2, updated_at = NOW() WHERE user_id = $1`,
+	`DELETE FROM calendar_sync_channels s USING calendar_sync_channels t
+	 WHERE s.user_id = $1 AND t.user_id = $2 AND s.provider = t.provider`,
+	`UPDATE calendar_sync_channels SET user_id = $2, updated_at = NOW() WHERE user_id = $1`,
+
+	// Subscription feed URL, kept working when the target has none (otherwise the target's wins)
+	`DELETE FROM calendar_feeds s USING calendar_feeds t WHERE s.user_id = $1 AND t.user_id = $2`,
+	`UPDATE calendar_feeds SET user_id = $2 WHERE user_id = $1`,
+
+	// Uploaded media, so avatars and chat images other members received keep loading
+	`UPDATE media_objects SET owner_id = $2 WHERE owner_id = $1`,
+}
+
+// Merge moves all data of the source user to the target user and deletes the source
+// The target keeps its own profile fields and only takes the source's where its own are empty,
+// including the source's phone when the target only has an OAuth placeholder.
+// The source's refresh tokens are dropped with it, logging out all of its sessions.
+func (r *UserRepository) Merge(sourceID, targetID int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	source, err := scanUser(tx.QueryRow(`SELECT `+userColumns+` FROM users WHERE id = $1 FOR UPDATE`, sourceID))
+	if err != nil {
+		return fmt.Errorf("failed to find user to merge: %w", err)
+	}
+	target, err := scanUser(tx.QueryRow(`SELECT `+userColumns+` FROM users WHERE id = $1 FOR UPDATE`, targetID))
+	if err != nil {
+		return fmt.Errorf("failed to find user to merge into: %w", err)
+	}
+
+	for _, statement := range mergeUserStatements {
+		if _, err := tx.Exec(statement, sourceID, targetID); err != nil {
+			return fmt.Errorf("failed to merge user data: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM users WHERE id = $1`, sourceID); err != nil {
+		return fmt.Errorf("failed to delete merged user: %w", err)
+	}
+
+	phone := target.Phone
+	if !target.HasPhoneLogin() && source.HasPhoneLogin() {
+		phone = source.Phone
+	}
+
+	_, err = tx.Exec(`
+		UPDATE users
+		SET phone = $1,
+		    name = COALESCE(NULLIF(name, ''), $2),
+		    email = COALESCE(NULLIF(email, ''), $3),
+		    profile_image_url = COALESCE(NULLIF(profile_image_url, ''), $4),
+		    region = COALESCE(region, $5),
+		    updated_at = NOW()
+		WHERE id = $6
+	`, phone, source.Name, source.Email, source.ProfileImageURL, source.Region, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to update merged user: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
 // FindByIDs finds multiple users by their IDs
 func (r *UserRepository) FindByIDs(ids []int64) ([]*models.User, error) {
 	if len(ids) == 0 {
@@ -172,7 +482,7 @@ func (r *UserRepository) FindByIDs(ids []int64) ([]*models.User, error) {
 	}
 
 	query := `
-		SELECT id, phone, name, email, profile_image_url, region, interests, timezone, language, role, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE id = ANY($1)
 	`
@@ -185,21 +495,7 @@ func (r *UserRepository) FindByIDs(ids []int64) ([]*models.User, error) {
 
 	users := []*models.User{}
 	for rows.Next() {
-		user := &models.User{}
-		err := rows.Scan(
-			&user.ID,
-			&user.Phone,
-			&user.Name,
-			&user.Email,
-			&user.ProfileImageURL,
-			&user.Region,
-			pq.Array(&user.Interests),
-			&user.Timezone,
-			&user.Language,
-			&user.Role,
-			&user.CreatedAt,
-			&user.UpdatedAt,
-		)
+		user, err := scanUser(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
 		}
@@ -212,27 +508,12 @@ func (r *UserRepository) FindByIDs(ids []int64) ([]*models.User, error) {
 // FindByEmail finds a user by email address
 func (r *UserRepository) FindByEmail(email string) (*models.User, error) {
 	query := `
-		SELECT id, phone, name, email, profile_image_url, region, interests, timezone, language, role, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE email = $1
 	`
 
-	user := &models.User{}
-	err := r.db.QueryRow(query, email).Scan(
-		&user.ID,
-		&user.Phone,
-		&user.Name,
-		&user.Email,
-		&user.ProfileImageURL,
-		&user.Region,
-		pq.Array(&user.Interests),
-		&user.Timezone,
-		&user.Language,
-		&user.Role,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
-
+	user, err := scanUser(r.db.QueryRow(query, email))
 	if err == sql.ErrNoRows {
 		return nil, nil // Return nil, nil to indicate "not found" without error
 	}
@@ -243,20 +524,40 @@ func (r *UserRepository) FindByEmail(email string) (*models.User, error) {
 	return user, nil
 }
 
+// EmailExists checks if another user already uses an email address (case-insensitive)
+func (r *UserRepository) EmailExists(email string, excludeUserID int64) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE LOWER(email) = LOWER($1) AND id <> $2)`
+
+	var exists bool
+	if err := r.db.QueryRow(query, email, excludeUserID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check email: %w", err)
+	}
+
+	return exists, nil
+}
+
 // CreateOAuthUser creates a new user from OAuth provider data
 func (r *UserRepository) CreateOAuthUser(email, name, pictureURL string) (*models.User, error) {
 	// Generate a unique phone placeholder for OAuth users (they don't have phone numbers)
 	// Format: oauth_<timestamp> to ensure uniqueness
-	phonePlaceholder := fmt.Sprintf("oauth_%d", time.Now().UnixNano())
+	phonePlaceholder := fmt.Sprintf("%s%d", models.OAuthPhonePrefix, time.Now().UnixNano())
 
 	user := &models.User{
-		Phone:           phonePlaceholder,
-		Name:            &name,
-		Email:           &email,
-		ProfileImageURL: &pictureURL,
-		Timezone:        "UTC",
-		Language:        "ko",
-		Role:            models.UserRoleUser,
+		Phone:    phonePlaceholder,
+		Timezone: "UTC",
+		Language: "ko",
+		Role:     models.UserRoleUser,
+	}
+
+	// Providers may withhold fields (Apple sends no picture, and the name only once)
+	if name != "" {
+		user.Name = &name
+	}
+	if email != "" {
+		user.Email = &email
+	}
+	if pictureURL != "" {
+		user.ProfileImageURL = &pictureURL
 	}
 
 	if err := r.Create(user); err != nil {
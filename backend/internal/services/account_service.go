@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/repositories"
+	"github.com/khchoi-tnh/timingle/pkg/utils"
+)
+
+// AccountService manages the login methods linked to a user and account merging
+type AccountService struct {
+	userRepo       *repositories.UserRepository
+	oauthRepo      *repositories.OAuthRepository
+	attendanceRepo *repositories.AttendanceRepository
+	calendarSync   *CalendarSyncService
+	jwtManager     *utils.JWTManager
+	googleVerifier *utils.GoogleOAuthVerifier
+}
+
+// NewAccountService creates a new account service
+func NewAccountService(
+	userRepo *repositories.UserRepository,
+	oauthRepo *repositories.OAuthRepository,
+	attendanceRepo *repositories.AttendanceRepository,
+	calendarSync *CalendarSyncService,
+	jwtManager *utils.JWTManager,
+	googleVerifier *utils.GoogleOAuthVerifier,
+) *AccountService {
+	return &AccountService{
+		userRepo:       userRepo,
+		oauthRepo:      oauthRepo,
+		attendanceRepo: attendanceRepo,
+		calendarSync:   calendarSync,
+		jwtManager:     jwtManager,
+		googleVerifier: googleVerifier,
+	}
+}
+
+// GetLinkedAccounts returns the phone and OAuth accounts a user can log in with
+func (s *AccountService) GetLinkedAccounts(userID int64) (*models.LinkedAccountsResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.oauthRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.LinkedAccountsResponse{Accounts: []*models.OAuthAccountResponse{}}
+	if user.HasPhoneLogin() {
+		response.Phone = &user.Phone
+	}
+	for _, account := range accounts {
+		response.Accounts = append(response.Accounts, account.ToResponse())
+	}
+
+	return response, nil
+}
+
+// LinkGoogle links a Google account to the logged-in user
+// A Google account already linked to another timingle account must be merged instead
+func (s *AccountService) LinkGoogle(ctx context.Context, userID int64, req *models.GoogleLoginRequest) (*models.OAuthAccountResponse, error) {
+	googlePayload, err := s.googleVerifier.VerifyIDToken(ctx, req.IDToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Google ID token: %w", err)
+	}
+	identity := googleIdentity(googlePayload)
+
+	existing, err := s.oauthRepo.FindByProviderUserID(identity.Provider, identity.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check OAuth account: %w", err)
+	}
+	if existing != nil {
+		if existing.UserID != userID {
+			return nil, fmt.Errorf("this Google account is linked to another timingle account, merge the accounts instead")
+		}
+		return existing.ToResponse(), nil
+	}
+
+	current, err := s.oauthRepo.FindByUserIDAndProvider(userID, identity.Provider)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		return nil, fmt.Errorf("a different Google account is already linked, unlink it first")
+	}
+
+	account := &models.OAuthAccount{
+		UserID:         userID,
+		Provider:       identity.Provider,
+		ProviderUserID: identity.Subject,
+	}
+	applyIdentity(account, identity)
+	if err := s.oauthRepo.Create(account); err != nil {
+		return nil, fmt.Errorf("failed to link OAuth account: %w", err)
+	}
+
+	return account.ToResponse(), nil
+}
+
+// UnlinkProvider removes a linked OAuth account unless it is the user's last login method
+func (s *AccountService) UnlinkProvider(userID int64, provider models.OAuthProvider) error {
+	if provider != models.OAuthProviderGoogle && provider != models.OAuthProviderApple {
+		return fmt.Errorf("invalid provider: %s", provider)
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+
+	accounts, err := s.oauthRepo.FindByUserID(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkUnlink(user, accounts, provider); err != nil {
+		return err
+	}
+
+	return s.oauthRepo.DeleteByUserIDAndProvider(userID, provider)
+}
+
+// checkUnlink verifies a provider is linked and is not the user's only way to log in
+func checkUnlink(user *models.User, accounts []*models.OAuthAccount, provider models.OAuthProvider) error {
+	linked := false
+	methods := 0
+	if user.HasPhoneLogin() {
+		methods++
+	}
+	for _, account := range accounts {
+		methods++
+		if account.Provider == provider {
+			linked = true
+		}
+	}
+
+	if !linked {
+		return fmt.Errorf("%s account is not linked", provider)
+	}
+	if methods <= 1 {
+		return fmt.Errorf("cannot remove your last login method")
+	}
+	return nil
+}
+
+// MergeAccounts merges the account behind accessToken into the logged-in user
+// Events, participations and linked login methods move to the current user;
+// the other account is deleted.
+func (s *AccountService) MergeAccounts(ctx context.Context, userID int64, req *models.MergeAccountsRequest) (*models.MergeAccountsResponse, error) {
+	claims, err := s.jwtManager.ValidateAccessToken(req.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid access token for the account to merge")
+	}
+
+	sourceID := claims.UserID
+	if sourceID == userID {
+		return nil, fmt.Errorf("cannot merge an account into itself")
+	}
+
+	// A suspended account cannot escape its suspension by merging
+	source, err := s.userRepo.FindByID(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkActiveUser(source); err != nil {
+		return nil, err
+	}
+
+	sourceAccounts, err := s.oauthRepo.FindByUserID(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	targetAccounts, err := s.oauthRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if provider, ok := conflictingProvider(sourceAccounts, targetAccounts); ok {
+		return nil, fmt.Errorf("both accounts have a linked %s account, unlink one before merging", provider)
+	}
+
+	if err := s.calendarSync.StopMergedChannel(ctx, sourceID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.Merge(sourceID, userID); err != nil {
+		return nil, err
+	}
+
+	refreshReliabilityScore(s.attendanceRepo, userID)
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.MergeAccountsResponse{
+		MergedUserID: sourceID,
+		User:         user.ToUserResponse(),
+	}, nil
+}
+
+// conflictingProvider returns a provider linked to both accounts; each user may link one account per provider
+func conflictingProvider(source, target []*models.OAuthAccount) (models.OAuthProvider, bool) {
+	linked := map[models.OAuthProvider]bool{}
+	for _, account := range target {
+		linked[account.Provider] = true
+	}
+	for _, account := range source {
+		if linked[account.Provider] {
+			return account.Provider, true
+		}
+	}
+	return "", false
+}
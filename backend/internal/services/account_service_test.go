@@ -0,0 +1,72 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+func TestCheckUnlink(t *testing.T) {
+	phoneUser := &models.User{ID: 1, Phone: "+821012345678"}
+	oauthUser := &models.User{ID: 2, Phone: models.OAuthPhonePrefix + "1700000000"}
+	google := &models.OAuthAccount{Provider: models.OAuthProviderGoogle}
+	apple := &models.OAuthAccount{Provider: models.OAuthProviderApple}
+
+	tests := []struct {
+		name     string
+		user     *models.User
+		accounts []*models.OAuthAccount
+		provider models.OAuthProvider
+		wantErr  bool
+	}{
+		{
+			name:     "phone user can unlink Google",
+			user:     phoneUser,
+			accounts: []*models.OAuthAccount{google},
+			provider: models.OAuthProviderGoogle,
+		},
+		{
+			name:     "OAuth user with two providers can unlink one",
+			user:     oauthUser,
+			accounts: []*models.OAuthAccount{google, apple},
+			provider: models.OAuthProviderApple,
+		},
+		{
+			name:     "OAuth user cannot unlink the last provider",
+			user:     oauthUser,
+			accounts: []*models.OAuthAccount{google},
+			provider: models.OAuthProviderGoogle,
+			wantErr:  true,
+		},
+		{
+			name:     "provider not linked",
+			user:     phoneUser,
+			accounts: []*models.OAuthAccount{google},
+			provider: models.OAuthProviderApple,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkUnlink(tt.user, tt.accounts, tt.provider)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkUnlink() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConflictingProvider(t *testing.T) {
+	google := &models.OAuthAccount{Provider: models.OAuthProviderGoogle}
+	apple := &models.OAuthAccount{Provider: models.OAuthProviderApple}
+
+	if _, ok := conflictingProvider([]*models.OAuthAccount{google}, []*models.OAuthAccount{apple}); ok {
+		t.Errorf("Expected different providers to merge cleanly")
+	}
+
+	provider, ok := conflictingProvider([]*models.OAuthAccount{apple, google}, []*models.OAuthAccount{google})
+	if !ok || provider != models.OAuthProviderGoogle {
+		t.Errorf("Expected google conflict, got %q (%v)", provider, ok)
+	}
+}
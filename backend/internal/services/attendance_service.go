@@ -0,0 +1,370 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/repositories"
+)
+
+const (
+	// checkInOpensBefore is how long before StartTime participants may check in
+	checkInOpensBefore = time.Hour
+	// lateGracePeriod is how long after StartTime a check-in still counts as on time
+	lateGracePeriod = 10 * time.Minute
+	// defaultGeofenceRadius is used when the creator does not set a radius (meters)
+	defaultGeofenceRadius = 150
+	earthRadiusMeters     = 6371000.0
+	// qrTokenStep is how long a check-in QR token is shown; the previous and next
+	// tokens are also accepted to allow for scanning delay and clock skew
+	qrTokenStep = 30 * time.Second
+)
+
+// AttendanceService handles check-in and no-show tracking
+type AttendanceService struct {
+	attendanceRepo *repositories.AttendanceRepository
+	eventRepo      *repositories.EventRepository
+	eventService   *EventService
+	qrSecret       []byte
+}
+
+// NewAttendanceService creates a new attendance service
+func NewAttendanceService(
+	attendanceRepo *repositories.AttendanceRepository,
+	eventRepo *repositories.EventRepository,
+	eventService *EventService,
+	qrSecret string,
+) *AttendanceService {
+	return &AttendanceService{
+		attendanceRepo: attendanceRepo,
+		eventRepo:      eventRepo,
+		eventService:   eventService,
+		qrSecret:       []byte(qrSecret),
+	}
+}
+
+// CheckIn records a participant's own check-in by QR scan or geofence ping
+func (s *AttendanceService) CheckIn(eventID, userID int64, req *models.CheckInRequest) (*models.EventAttendance, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("event not found")
+	}
+
+	isMember, err := s.eventService.IsUserEventMember(eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, fmt.Errorf("user is not a member of this event")
+	}
+
+	if event.Status != models.EventStatusConfirmed {
+		return nil, fmt.Errorf("check-in is only available for confirmed events")
+	}
+
+	startTime, endTime, err := s.occurrenceTimes(event, req.OccurrenceStart)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if now.Before(startTime.Add(-checkInOpensBefore)) {
+		return nil, fmt.Errorf("check-in is not open yet")
+	}
+	if now.After(endTime) {
+		return nil, fmt.Errorf("check-in is closed")
+	}
+
+	switch req.Method {
+	case models.CheckInMethodQR:
+		if !s.validQRToken(eventID, req.QRToken, now) {
+			return nil, fmt.Errorf("invalid QR code")
+		}
+	case models.CheckInMethodGeofence:
+		if req.Latitude == nil || req.Longitude == nil {
+			return nil, fmt.Errorf("latitude and longitude are required")
+		}
+		geofence, err := s.attendanceRepo.FindGeofence(eventID)
+		if err != nil {
+			return nil, err
+		}
+		if geofence == nil {
+			return nil, fmt.Errorf("event has no check-in location")
+		}
+		distance := distanceMeters(geofence.Latitude, geofence.Longitude, *req.Latitude, *req.Longitude)
+		if distance > float64(geofence.RadiusMeters) {
+			return nil, fmt.Errorf("not within the check-in area (%.0fm away)", distance)
+		}
+	default:
+		return nil, fmt.Errorf("invalid check-in method: %s", req.Method)
+	}
+
+	// Repeated check-ins keep the first one
+	existing, err := s.attendanceRepo.Find(eventID, userID, req.OccurrenceStart)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && (existing.Status == models.AttendanceCheckedIn || existing.Status == models.AttendanceLate) {
+		return existing, nil
+	}
+
+	method := req.Method
+	attendance := &models.EventAttendance{
+		EventID:         eventID,
+		UserID:          userID,
+		OccurrenceStart: req.OccurrenceStart,
+		Status:          attendanceStatusAt(now, startTime),
+		Method:          &method,
+		CheckedInAt:     &now,
+		RecordedBy:      &userID,
+	}
+	if err := s.attendanceRepo.Upsert(attendance); err != nil {
+		return nil, err
+	}
+
+	refreshReliabilityScore(s.attendanceRepo, userID)
+
+	return attendance, nil
+}
+
+// RecordAttendance lets the creator confirm or correct a participant's attendance
+func (s *AttendanceService) RecordAttendance(eventID, creatorID, participantID int64, req *models.RecordAttendanceRequest) (*models.EventAttendance, error) {
+	if !req.Status.IsValid() {
+		return nil, fmt.Errorf("invalid status: %s", req.Status)
+	}
+
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("event not found")
+	}
+
+	if event.CreatorID != creatorID {
+		return nil, fmt.Errorf("only creator can record attendance")
+	}
+
+	isMember, err := s.eventService.IsUserEventMember(eventID, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, fmt.Errorf("user is not a member of this event")
+	}
+
+	if _, _, err := s.occurrenceTimes(event, req.OccurrenceStart); err != nil {
+		return nil, err
+	}
+
+	method := models.CheckInMethodCreator
+	attendance := &models.EventAttendance{
+		EventID:         eventID,
+		UserID:          participantID,
+		OccurrenceStart: req.OccurrenceStart,
+		Status:          req.Status,
+		Method:          &method,
+		RecordedBy:      &creatorID,
+		Note:            req.Note,
+	}
+	if req.Status == models.AttendanceCheckedIn || req.Status == models.AttendanceLate {
+		now := time.Now()
+		attendance.CheckedInAt = &now
+	}
+
+	if err := s.attendanceRepo.Upsert(attendance); err != nil {
+		return nil, err
+	}
+
+	refreshReliabilityScore(s.attendanceRepo, participantID)
+
+	return attendance, nil
+}
+
+// GetAttendance returns the attendance records of an event to one of its members
+func (s *AttendanceService) GetAttendance(eventID, userID int64) ([]*models.EventAttendance, error) {
+	isMember, err := s.eventService.IsUserEventMember(eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, fmt.Errorf("user is not a member of this event")
+	}
+
+	return s.attendanceRepo.FindByEventID(eventID)
+}
+
+// GetCheckInQR returns the token the creator shows as a QR code at the venue
+func (s *AttendanceService) GetCheckInQR(eventID, userID int64) (*models.CheckInQRResponse, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("event not found")
+	}
+
+	if event.CreatorID != userID {
+		return nil, fmt.Errorf("only creator can get the check-in QR code")
+	}
+
+	step := qrStep(time.Now())
+	return &models.CheckInQRResponse{
+		EventID:   eventID,
+		Token:     s.qrToken(eventID, step),
+		ExpiresAt: time.Unix((step+1)*int64(qrTokenStep/time.Second), 0).UTC(),
+	}, nil
+}
+
+// SetGeofence sets the area in which participants can check in by location
+func (s *AttendanceService) SetGeofence(eventID, userID int64, req *models.SetGeofenceRequest) (*models.EventGeofence, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("event not found")
+	}
+
+	if event.CreatorID != userID {
+		return nil, fmt.Errorf("only creator can set the check-in location")
+	}
+
+	if *req.Latitude < -90 || *req.Latitude > 90 || *req.Longitude < -180 || *req.Longitude > 180 {
+		return nil, fmt.Errorf("invalid coordinates")
+	}
+
+	radius := req.RadiusMeters
+	if radius <= 0 {
+		radius = defaultGeofenceRadius
+	}
+
+	geofence := &models.EventGeofence{
+		EventID:      eventID,
+		Latitude:     *req.Latitude,
+		Longitude:    *req.Longitude,
+		RadiusMeters: radius,
+	}
+	if err := s.attendanceRepo.UpsertGeofence(geofence); err != nil {
+		return nil, err
+	}
+
+	return geofence, nil
+}
+
+// occurrenceTimes returns the start and end of a single event or, for a recurring event,
+// of the occurrence originally starting at occurrenceStart (with its override applied)
+func (s *AttendanceService) occurrenceTimes(event *models.Event, occurrenceStart *time.Time) (time.Time, time.Time, error) {
+	if !event.IsRecurring() {
+		if occurrenceStart != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("event is not recurring")
+		}
+		return event.StartTime, event.EndTime, nil
+	}
+
+	if occurrenceStart == nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("occurrence_start is required for recurring events")
+	}
+
+	ok, err := isOccurrence(event, *occurrenceStart)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if !ok {
+		return time.Time{}, time.Time{}, fmt.Errorf("occurrence not found")
+	}
+
+	override, err := s.eventRepo.FindOccurrenceOverride(event.ID, *occurrenceStart)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if override != nil && override.IsCanceled {
+		return time.Time{}, time.Time{}, fmt.Errorf("occurrence is canceled")
+	}
+
+	startTime, endTime := occurrenceWindow(event, *occurrenceStart, override)
+	return startTime, endTime, nil
+}
+
+// occurrenceWindow returns the start and end of an occurrence after its override
+func occurrenceWindow(event *models.Event, occurrenceStart time.Time, override *models.EventOccurrenceOverride) (time.Time, time.Time) {
+	startTime := occurrenceStart
+	endTime := occurrenceStart.Add(event.EndTime.Sub(event.StartTime))
+	if override != nil {
+		if override.StartTime != nil {
+			startTime = *override.StartTime
+		}
+		if override.EndTime != nil {
+			endTime = *override.EndTime
+		}
+	}
+	return startTime, endTime
+}
+
+// qrStep returns the index of the QR token time step containing t
+func qrStep(t time.Time) int64 {
+	return t.Unix() / int64(qrTokenStep/time.Second)
+}
+
+// qrToken derives the check-in QR token of an event for a time step
+func (s *AttendanceService) qrToken(eventID, step int64) string {
+	mac := hmac.New(sha256.New, s.qrSecret)
+	mac.Write([]byte("checkin:" + strconv.FormatInt(eventID, 10) + ":" + strconv.FormatInt(step, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// validQRToken checks a scanned token against the current and adjacent time steps
+func (s *AttendanceService) validQRToken(eventID int64, token string, now time.Time) bool {
+	step := qrStep(now)
+	for _, candidate := range []int64{step - 1, step, step + 1} {
+		if hmac.Equal([]byte(token), []byte(s.qrToken(eventID, candidate))) {
+			return true
+		}
+	}
+	return false
+}
+
+// attendanceStatusAt returns CHECKED_IN within the grace period after start, LATE afterwards
+func attendanceStatusAt(checkedInAt, startTime time.Time) models.AttendanceStatus {
+	if checkedInAt.After(startTime.Add(lateGracePeriod)) {
+		return models.AttendanceLate
+	}
+	return models.AttendanceCheckedIn
+}
+
+// reliabilityScore returns 0-100 from attendance counts: on time counts fully,
+// late counts half and no-show counts zero. Excused absences are ignored.
+// Returns nil when there is nothing to score.
+func reliabilityScore(counts map[models.AttendanceStatus]int) *float64 {
+	total := counts[models.AttendanceCheckedIn] + counts[models.AttendanceLate] + counts[models.AttendanceNoShow]
+	if total == 0 {
+		return nil
+	}
+
+	points := float64(counts[models.AttendanceCheckedIn]) + 0.5*float64(counts[models.AttendanceLate])
+	score := math.Round(points/float64(total)*10000) / 100
+	return &score
+}
+
+// refreshReliabilityScore recomputes and stores a user's reliability score
+func refreshReliabilityScore(repo *repositories.AttendanceRepository, userID int64) {
+	counts, err := repo.CountByUserID(userID)
+	if err != nil {
+		fmt.Printf("Warning: failed to count attendance for user %d: %v\n", userID, err)
+		return
+	}
+
+	if err := repo.UpdateReliabilityScore(userID, reliabilityScore(counts)); err != nil {
+		fmt.Printf("Warning: failed to update reliability score for user %d: %v\n", userID, err)
+	}
+}
+
+// distanceMeters returns the great-circle distance between two coordinates
+func distanceMeters(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(a))
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+func TestAttendanceStatusAt(t *testing.T) {
+	start := time.Date(2024, 1, 2, 19, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected models.AttendanceStatus
+	}{
+		{"early", start.Add(-30 * time.Minute), models.AttendanceCheckedIn},
+		{"within grace period", start.Add(lateGracePeriod), models.AttendanceCheckedIn},
+		{"after grace period", start.Add(lateGracePeriod + time.Second), models.AttendanceLate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := attendanceStatusAt(tt.at, start); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestReliabilityScore(t *testing.T) {
+	if score := reliabilityScore(map[models.AttendanceStatus]int{models.AttendanceExcused: 3}); score != nil {
+		t.Errorf("Expected nil score without scored attendance, got %v", *score)
+	}
+
+	score := reliabilityScore(map[models.AttendanceStatus]int{
+		models.AttendanceCheckedIn: 6,
+		models.AttendanceLate:      2,
+		models.AttendanceNoShow:    2,
+		models.AttendanceExcused:   5,
+	})
+	if score == nil || *score != 70 {
+		t.Errorf("Expected score 70, got %v", score)
+	}
+}
+
+func TestDistanceMeters(t *testing.T) {
+	// Gangnam Station to Yeoksam Station is roughly 800m
+	distance := distanceMeters(37.4979, 127.0276, 37.5006, 127.0364)
+	if math.Abs(distance-830) > 50 {
+		t.Errorf("Expected about 830m, got %.0fm", distance)
+	}
+
+	if distanceMeters(37.5, 127.0, 37.5, 127.0) != 0 {
+		t.Error("Expected zero distance for identical points")
+	}
+}
+
+func TestValidQRToken(t *testing.T) {
+	s := &AttendanceService{qrSecret: []byte("test-checkin-secret")}
+	shownAt := time.Date(2024, 1, 2, 19, 0, 5, 0, time.UTC)
+	token := s.qrToken(7, qrStep(shownAt))
+
+	tests := []struct {
+		name     string
+		eventID  int64
+		at       time.Time
+		expected bool
+	}{
+		{"same step", 7, shownAt, true},
+		{"next step", 7, shownAt.Add(qrTokenStep), true},
+		{"two steps later", 7, shownAt.Add(2 * qrTokenStep), false},
+		{"previous step", 7, shownAt.Add(-qrTokenStep), true},
+		{"other event", 8, shownAt, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.validQRToken(tt.eventID, token, tt.at); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestOccurrenceWindow(t *testing.T) {
+	rule := "FREQ=WEEKLY"
+	event := &models.Event{
+		StartTime:      time.Date(2024, 1, 2, 19, 0, 0, 0, time.UTC),
+		EndTime:        time.Date(2024, 1, 2, 21, 0, 0, 0, time.UTC),
+		RecurrenceRule: &rule,
+	}
+	occurrenceStart := event.StartTime.AddDate(0, 0, 14)
+
+	start, end := occurrenceWindow(event, occurrenceStart, nil)
+	if !start.Equal(occurrenceStart) || !end.Equal(occurrenceStart.Add(2*time.Hour)) {
+		t.Errorf("Expected the occurrence's own window, got %v - %v", start, end)
+	}
+
+	moved := occurrenceStart.Add(time.Hour)
+	start, end = occurrenceWindow(event, occurrenceStart, &models.EventOccurrenceOverride{StartTime: &moved})
+	if !start.Equal(moved) || !end.Equal(occurrenceStart.Add(2*time.Hour)) {
+		t.Errorf("Expected the overridden start, got %v - %v", start, end)
+	}
+}
+
+func TestOccurrenceTimesRequiresOccurrenceOfRecurringEvent(t *testing.T) {
+	s := &AttendanceService{}
+	start := time.Date(2024, 1, 2, 19, 0, 0, 0, time.UTC)
+	single := &models.Event{StartTime: start, EndTime: start.Add(2 * time.Hour)}
+
+	if got, _, err := s.occurrenceTimes(single, nil); err != nil || !got.Equal(start) {
+		t.Errorf("Expected the event start for a single event, got %v (%v)", got, err)
+	}
+	if _, _, err := s.occurrenceTimes(single, &start); err == nil {
+		t.Error("Expected an error for an occurrence of a single event")
+	}
+
+	rule := "FREQ=WEEKLY"
+	recurring := &models.Event{StartTime: start, EndTime: start.Add(2 * time.Hour), RecurrenceRule: &rule}
+	if _, _, err := s.occurrenceTimes(recurring, nil); err == nil {
+		t.Error("Expected an error without an occurrence of a recurring event")
+	}
+	notOccurrence := start.AddDate(0, 0, 1)
+	if _, _, err := s.occurrenceTimes(recurring, &notOccurrence); err == nil {
+		t.Error("Expected an error for a time that is not an occurrence")
+	}
+}
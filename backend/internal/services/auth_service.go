@@ -2,9 +2,14 @@ package services
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/khchoi-tnh/timingle/internal/models"
 	"github.com/khchoi-tnh/timingle/internal/repositories"
 	"github.com/khchoi-tnh/timingle/pkg/utils"
@@ -13,6 +18,9 @@ import (
 // CalendarScope is the Google Calendar API scope
 const CalendarScope = "https://www.googleapis.com/auth/calendar"
 
+// ErrCalendarReconsentRequired is returned when the user's Google refresh token was revoked
+var ErrCalendarReconsentRequired = errors.New("google calendar access was revoked, please sign in with Google again")
+
 // AuthService handles authentication business logic
 type AuthService struct {
 	userRepo       *repositories.UserRepository
@@ -20,6 +28,8 @@ type AuthService struct {
 	oauthRepo      *repositories.OAuthRepository
 	jwtManager     *utils.JWTManager
 	googleVerifier *utils.GoogleOAuthVerifier
+	appleVerifier  *utils.AppleOAuthVerifier
+	otpService     *OTPService
 }
 
 // NewAuthService creates a new auth service
@@ -29,6 +39,8 @@ func NewAuthService(
 	oauthRepo *repositories.OAuthRepository,
 	jwtManager *utils.JWTManager,
 	googleVerifier *utils.GoogleOAuthVerifier,
+	appleVerifier *utils.AppleOAuthVerifier,
+	otpService *OTPService,
 ) *AuthService {
 	return &AuthService{
 		userRepo:       userRepo,
@@ -36,20 +48,33 @@ func NewAuthService(
 		oauthRepo:      oauthRepo,
 		jwtManager:     jwtManager,
 		googleVerifier: googleVerifier,
+		appleVerifier:  appleVerifier,
+		otpService:     otpService,
 	}
 }
 
-// Register registers a new user
-func (s *AuthService) Register(req *models.RegisterRequest) (*models.AuthResponse, error) {
+// Register registers a new user with a phone verified by OTP
+func (s *AuthService) Register(req *models.RegisterRequest, meta *models.SessionMetadata) (*models.AuthResponse, error) {
+	ctx := context.Background()
+
+	phone, err := s.otpService.CheckVerification(ctx, req.Phone, req.VerificationToken)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if user already exists
-	existingUser, _ := s.userRepo.FindByPhone(req.Phone)
+	existingUser, _ := s.userRepo.FindByPhone(phone)
 	if existingUser != nil {
 		return nil, fmt.Errorf("user with this phone already exists")
 	}
 
+	if _, err := s.otpService.ConsumeVerification(ctx, phone, req.VerificationToken); err != nil {
+		return nil, err
+	}
+
 	// Create new user
 	user := &models.User{
-		Phone:    req.Phone,
+		Phone:    phone,
 		Name:     &req.Name,
 		Timezone: "UTC",
 		Language: "ko",
@@ -61,36 +86,57 @@ func (s *AuthService) Register(req *models.RegisterRequest) (*models.AuthRespons
 	}
 
 	// Generate tokens
-	return s.generateAuthResponse(user)
+	return s.generateAuthResponse(user, meta)
 }
 
-// Login authenticates a user and returns tokens
-func (s *AuthService) Login(req *models.LoginRequest) (*models.AuthResponse, error) {
+// Login authenticates a user with a phone verified by OTP and returns tokens
+// An unregistered phone keeps its verification so it can be used to register
+func (s *AuthService) Login(req *models.LoginRequest, meta *models.SessionMetadata) (*models.AuthResponse, error) {
+	ctx := context.Background()
+
+	phone, err := s.otpService.CheckVerification(ctx, req.Phone, req.VerificationToken)
+	if err != nil {
+		return nil, err
+	}
+
 	// Find user by phone
-	user, err := s.userRepo.FindByPhone(req.Phone)
+	user, err := s.userRepo.FindByPhone(phone)
 	if err != nil {
 		return nil, fmt.Errorf("invalid phone number")
 	}
 
-	// In production, verify SMS code here
-	// For now, we skip verification for development
+	if _, err := s.otpService.ConsumeVerification(ctx, phone, req.VerificationToken); err != nil {
+		return nil, err
+	}
 
 	// Generate tokens
-	return s.generateAuthResponse(user)
+	return s.generateAuthResponse(user, meta)
 }
 
-// RefreshToken generates new access token from refresh token
-func (s *AuthService) RefreshToken(req *models.RefreshTokenRequest) (*models.AuthResponse, error) {
+// RefreshToken exchanges a refresh token for a new access token and a new refresh token
+// The presented token is rotated out. Presenting an already rotated token again means
+// it was copied, so the whole family is revoked and both holders must log in again.
+// The session keeps the device metadata it was started with unless meta has newer values.
+func (s *AuthService) RefreshToken(req *models.RefreshTokenRequest, meta *models.SessionMetadata) (*models.AuthResponse, error) {
 	// Find refresh token
-	refreshToken, err := s.authRepo.FindRefreshToken(req.RefreshToken)
+	refreshToken, err := s.authRepo.FindRefreshTokenByHash(hashRefreshToken(req.RefreshToken))
 	if err != nil {
 		return nil, fmt.Errorf("invalid refresh token")
 	}
 
+	if refreshToken.RevokedAt != nil {
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+
+	if refreshToken.RotatedAt != nil {
+		s.revokeReusedFamily(refreshToken)
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+
 	// Check if expired
 	if time.Now().After(refreshToken.ExpiresAt) {
 		// Delete expired token
-		_ = s.authRepo.DeleteRefreshToken(req.RefreshToken)
+		_ = s.authRepo.DeleteRefreshToken(refreshToken.TokenHash)
 		return nil, fmt.Errorf("refresh token expired")
 	}
 
@@ -99,24 +145,120 @@ func (s *AuthService) RefreshToken(req *models.RefreshTokenRequest) (*models.Aut
 	if err != nil {
 		return nil, fmt.Errorf("user not found")
 	}
+	if err := checkActiveUser(user); err != nil {
+		return nil, err
+	}
 
-	// Generate new access token (keep same refresh token)
-	accessToken, err := s.jwtManager.GenerateAccessToken(user)
+	// Generate new access token
+	accessToken, err := s.jwtManager.GenerateAccessToken(user, refreshToken.FamilyID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
+	// Rotate refresh token
+	refreshTokenString, err := s.jwtManager.GenerateRefreshToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	next := &models.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: hashRefreshToken(refreshTokenString),
+		Platform:  refreshToken.Platform,
+		UserAgent: refreshToken.UserAgent,
+		IPAddress: refreshToken.IPAddress,
+		ExpiresAt: time.Now().Add(s.jwtManager.GetRefreshExpiry()),
+	}
+	applySessionMetadata(next, meta)
+	rotated, err := s.authRepo.RotateRefreshToken(refreshToken, next)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Lost a race with another request presenting the same token
+		s.revokeReusedFamily(refreshToken)
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+
 	return &models.AuthResponse{
 		AccessToken:  accessToken,
-		RefreshToken: req.RefreshToken,
+		RefreshToken: refreshTokenString,
 		ExpiresIn:    int64(s.jwtManager.GetAccessExpiry().Seconds()),
 		User:         user.ToUserResponse(),
 	}, nil
 }
 
-// Logout logs out a user by deleting their refresh tokens
-func (s *AuthService) Logout(userID int64) error {
-	return s.authRepo.DeleteUserRefreshTokens(userID)
+// revokeReusedFamily revokes a token family after one of its rotated tokens was presented again
+func (s *AuthService) revokeReusedFamily(refreshToken *models.RefreshToken) {
+	revoked, err := s.authRepo.RevokeRefreshTokenFamily(refreshToken.FamilyID)
+	if err != nil {
+		fmt.Printf("Warning: failed to revoke refresh token family %s: %v\n", refreshToken.FamilyID, err)
+		return
+	}
+
+	fmt.Printf("Security: refresh token reuse detected for user %d (token %d, family %s); revoked %d tokens\n",
+		refreshToken.UserID, refreshToken.ID, refreshToken.FamilyID, revoked)
+}
+
+// hashRefreshToken returns the stored form of a refresh token
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// applySessionMetadata overwrites a token's device metadata with the values present in meta
+func applySessionMetadata(refreshToken *models.RefreshToken, meta *models.SessionMetadata) {
+	if meta == nil {
+		return
+	}
+	if meta.Platform != nil {
+		refreshToken.Platform = meta.Platform
+	}
+	if meta.UserAgent != nil {
+		refreshToken.UserAgent = meta.UserAgent
+	}
+	if meta.IPAddress != nil {
+		refreshToken.IPAddress = meta.IPAddress
+	}
+}
+
+// Logout ends the session the access token was issued for
+// Access tokens issued before sessions existed carry no session ID; those log out every session
+func (s *AuthService) Logout(userID int64, sessionID string) error {
+	if sessionID == "" {
+		return s.authRepo.DeleteUserRefreshTokens(userID)
+	}
+	return s.authRepo.RevokeUserSession(userID, sessionID)
+}
+
+// ListSessions returns the devices a user is logged in on, marking the current one
+func (s *AuthService) ListSessions(userID int64, currentSessionID string) ([]*models.Session, error) {
+	sessions, err := s.authRepo.FindActiveSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		session.Current = session.ID == currentSessionID
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession logs a user out of one of their sessions
+func (s *AuthService) RevokeSession(userID int64, sessionID string) error {
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return fmt.Errorf("session not found")
+	}
+	return s.authRepo.RevokeUserSession(userID, sessionID)
+}
+
+// RevokeOtherSessions logs a user out of every session except the current one
+func (s *AuthService) RevokeOtherSessions(userID int64, currentSessionID string) (int64, error) {
+	if currentSessionID == "" {
+		return 0, fmt.Errorf("current session is unknown, please log in again")
+	}
+	return s.authRepo.RevokeOtherUserSessions(userID, currentSessionID)
 }
 
 // ValidateAccessToken validates an access token and returns claims
@@ -130,89 +272,204 @@ func (s *AuthService) ValidateAccessToken(tokenString string) (*utils.Claims, er
 // 2. Find or create user based on Google email
 // 3. Link/update OAuth account
 // 4. Generate JWT tokens
-func (s *AuthService) GoogleLogin(ctx context.Context, req *models.GoogleLoginRequest) (*models.AuthResponse, error) {
+func (s *AuthService) GoogleLogin(ctx context.Context, req *models.GoogleLoginRequest, meta *models.SessionMetadata) (*models.AuthResponse, error) {
 	// 1. Verify Google ID token
 	googlePayload, err := s.googleVerifier.VerifyIDToken(ctx, req.IDToken)
 	if err != nil {
 		return nil, fmt.Errorf("invalid Google ID token: %w", err)
 	}
 
-	// 2. Check if OAuth account already exists
-	oauthAccount, err := s.oauthRepo.FindByProviderUserID(models.OAuthProviderGoogle, googlePayload.Subject)
+	// 2. Find or create user and link OAuth account
+	user, err := s.findOrCreateOAuthUser(googleIdentity(googlePayload))
 	if err != nil {
-		return nil, fmt.Errorf("failed to check OAuth account: %w", err)
+		return nil, err
 	}
 
-	var user *models.User
+	// 3. Generate JWT tokens
+	return s.generateAuthResponse(user, meta)
+}
+
+// AppleLogin handles Sign in with Apple login
+// Flow:
+// 1. Verify Apple identity token (and nonce, if sent)
+// 2. Find or create user; only verified, non-relay emails link to existing users
+// 3. Link/update OAuth account, keeping the name Apple sent on first login
+// 4. Generate JWT tokens
+func (s *AuthService) AppleLogin(ctx context.Context, req *models.AppleLoginRequest, meta *models.SessionMetadata) (*models.AuthResponse, error) {
+	// 1. Verify Apple identity token
+	applePayload, err := s.appleVerifier.VerifyIDToken(ctx, req.IDToken, req.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Apple identity token: %w", err)
+	}
+
+	// 2. Find or create user and link OAuth account
+	user, err := s.findOrCreateOAuthUser(appleIdentity(applePayload, req))
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Generate JWT tokens
+	return s.generateAuthResponse(user, meta)
+}
+
+// oauthIdentity is a user identity verified by an OAuth provider
+// Empty fields are unknown and never overwrite stored values
+type oauthIdentity struct {
+	Provider models.OAuthProvider
+	Subject  string
+	Email    string
+	Name     string
+	Picture  string
+	// LinkByEmail allows a new OAuth account to attach to an existing user with the same email
+	// (the provider verified it); the user's email must itself come from a linked provider
+	LinkByEmail bool
+}
+
+// googleIdentity converts a verified Google ID token payload
+func googleIdentity(payload *models.GoogleTokenPayload) *oauthIdentity {
+	return &oauthIdentity{
+		Provider:    models.OAuthProviderGoogle,
+		Subject:     payload.Subject,
+		Email:       payload.Email,
+		Name:        payload.Name,
+		Picture:     payload.Picture,
+		LinkByEmail: payload.Email != "" && payload.EmailVerified,
+	}
+}
+
+// appleIdentity converts a verified Apple identity token payload
+// The name only comes from the request, as Apple never puts it in the token.
+// Private relay addresses are unique to timingle, so they never match an existing user.
+func appleIdentity(payload *models.AppleTokenPayload, req *models.AppleLoginRequest) *oauthIdentity {
+	return &oauthIdentity{
+		Provider:    models.OAuthProviderApple,
+		Subject:     payload.Subject,
+		Email:       payload.Email,
+		Name:        strings.TrimSpace(strings.TrimSpace(req.GivenName) + " " + strings.TrimSpace(req.FamilyName)),
+		LinkByEmail: payload.Email != "" && payload.EmailVerified && !payload.IsPrivateEmail,
+	}
+}
+
+// findOrCreateOAuthUser returns the user linked to an OAuth identity,
+// linking it to an existing user by email or creating a new user as needed
+func (s *AuthService) findOrCreateOAuthUser(identity *oauthIdentity) (*models.User, error) {
+	// Check if OAuth account already exists
+	oauthAccount, err := s.oauthRepo.FindByProviderUserID(identity.Provider, identity.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check OAuth account: %w", err)
+	}
 
 	if oauthAccount != nil {
 		// Existing OAuth account - get the linked user
-		user, err = s.userRepo.FindByID(oauthAccount.UserID)
+		user, err := s.userRepo.FindByID(oauthAccount.UserID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to find user: %w", err)
 		}
 
 		// Update OAuth account info if changed
-		if needsUpdate(oauthAccount, googlePayload) {
-			oauthAccount.Email = &googlePayload.Email
-			oauthAccount.Name = &googlePayload.Name
-			oauthAccount.PictureURL = &googlePayload.Picture
+		if needsUpdate(oauthAccount, identity) {
+			applyIdentity(oauthAccount, identity)
 			_ = s.oauthRepo.Update(oauthAccount)
 		}
-	} else {
-		// New OAuth account - check if user exists by email
-		user, err = s.userRepo.FindByEmail(googlePayload.Email)
+
+		// Fill in a name that was missing when the user was created
+		if (user.Name == nil || *user.Name == "") && identity.Name != "" {
+			user.Name = &identity.Name
+			if err := s.userRepo.Update(user); err != nil {
+				// Log error but continue
+				fmt.Printf("Warning: failed to update name of user %d: %v\n", user.ID, err)
+			}
+		}
+
+		return user, nil
+	}
+
+	// New OAuth account - check if user exists by email
+	var user *models.User
+	if identity.LinkByEmail && identity.Email != "" {
+		user, err = s.userRepo.FindByEmail(identity.Email)
 		if err != nil {
 			return nil, fmt.Errorf("failed to find user by email: %w", err)
 		}
+	}
 
-		if user == nil {
-			// Create new user
-			user, err = s.userRepo.CreateOAuthUser(
-				googlePayload.Email,
-				googlePayload.Name,
-				googlePayload.Picture,
-			)
-			if err != nil {
-				return nil, fmt.Errorf("failed to create user: %w", err)
-			}
+	// Anyone can put an address in their profile (PATCH /me), so only link when
+	// another linked provider vouched for the user's email
+	if user != nil {
+		accounts, err := s.oauthRepo.FindByUserID(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check OAuth accounts: %w", err)
 		}
-
-		// Create OAuth account link
-		newOAuthAccount := &models.OAuthAccount{
-			UserID:         user.ID,
-			Provider:       models.OAuthProviderGoogle,
-			ProviderUserID: googlePayload.Subject,
-			Email:          &googlePayload.Email,
-			Name:           &googlePayload.Name,
-			PictureURL:     &googlePayload.Picture,
+		if !hasProviderEmail(accounts, identity.Email) {
+			user = nil
 		}
-		if err := s.oauthRepo.Create(newOAuthAccount); err != nil {
-			return nil, fmt.Errorf("failed to link OAuth account: %w", err)
+	}
+
+	if user == nil {
+		// Create new user
+		user, err = s.userRepo.CreateOAuthUser(identity.Email, identity.Name, identity.Picture)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create user: %w", err)
 		}
 	}
 
-	// 3. Generate JWT tokens
-	return s.generateAuthResponse(user)
+	// Create OAuth account link
+	newOAuthAccount := &models.OAuthAccount{
+		UserID:         user.ID,
+		Provider:       identity.Provider,
+		ProviderUserID: identity.Subject,
+	}
+	applyIdentity(newOAuthAccount, identity)
+	if err := s.oauthRepo.Create(newOAuthAccount); err != nil {
+		return nil, fmt.Errorf("failed to link OAuth account: %w", err)
+	}
+
+	return user, nil
+}
+
+// hasProviderEmail checks if one of the OAuth accounts received the email from its provider
+func hasProviderEmail(accounts []*models.OAuthAccount, email string) bool {
+	for _, account := range accounts {
+		if account.Email != nil && strings.EqualFold(*account.Email, email) {
+			return true
+		}
+	}
+	return false
 }
 
 // needsUpdate checks if OAuth account info needs to be updated
-func needsUpdate(account *models.OAuthAccount, payload *models.GoogleTokenPayload) bool {
-	if account.Email == nil || *account.Email != payload.Email {
+func needsUpdate(account *models.OAuthAccount, identity *oauthIdentity) bool {
+	if identity.Email != "" && (account.Email == nil || *account.Email != identity.Email) {
 		return true
 	}
-	if account.Name == nil || *account.Name != payload.Name {
+	if identity.Name != "" && (account.Name == nil || *account.Name != identity.Name) {
 		return true
 	}
-	if account.PictureURL == nil || *account.PictureURL != payload.Picture {
+	if identity.Picture != "" && (account.PictureURL == nil || *account.PictureURL != identity.Picture) {
 		return true
 	}
 	return false
 }
 
+// applyIdentity copies the known profile fields of an identity to an OAuth account
+func applyIdentity(account *models.OAuthAccount, identity *oauthIdentity) {
+	if identity.Email != "" {
+		email := identity.Email
+		account.Email = &email
+	}
+	if identity.Name != "" {
+		name := identity.Name
+		account.Name = &name
+	}
+	if identity.Picture != "" {
+		picture := identity.Picture
+		account.PictureURL = &picture
+	}
+}
+
 // GoogleLoginWithCalendar handles Google OAuth login with Calendar scope
 // This saves the access token and refresh token for Calendar API access
-func (s *AuthService) GoogleLoginWithCalendar(ctx context.Context, req *models.GoogleCalendarLoginRequest) (*models.AuthResponse, error) {
+func (s *AuthService) GoogleLoginWithCalendar(ctx context.Context, req *models.GoogleCalendarLoginRequest, meta *models.SessionMetadata) (*models.AuthResponse, error) {
 	// 1. Verify Google ID token (same as regular login)
 	googlePayload, err := s.googleVerifier.VerifyIDToken(ctx, req.IDToken)
 	if err != nil {
@@ -257,10 +514,8 @@ func (s *AuthService) GoogleLoginWithCalendar(ctx context.Context, req *models.G
 		}
 
 		// Update profile info if changed
-		if needsUpdate(oauthAccount, googlePayload) {
-			oauthAccount.Email = &googlePayload.Email
-			oauthAccount.Name = &googlePayload.Name
-			oauthAccount.PictureURL = &googlePayload.Picture
+		if identity := googleIdentity(googlePayload); needsUpdate(oauthAccount, identity) {
+			applyIdentity(oauthAccount, identity)
 			_ = s.oauthRepo.Update(oauthAccount)
 		}
 	} else {
@@ -306,7 +561,7 @@ func (s *AuthService) GoogleLoginWithCalendar(ctx context.Context, req *models.G
 	}
 
 	// 3. Generate JWT tokens
-	return s.generateAuthResponse(user)
+	return s.generateAuthResponse(user, meta)
 }
 
 // GetValidAccessToken returns a valid access token for the user's Google account
@@ -322,6 +577,9 @@ func (s *AuthService) GetValidAccessToken(ctx context.Context, userID int64) (st
 	if !oauthAccount.HasCalendarScope() {
 		return "", fmt.Errorf("calendar permission not granted")
 	}
+	if oauthAccount.NeedsReconsent() {
+		return "", ErrCalendarReconsentRequired
+	}
 	if oauthAccount.AccessToken == nil {
 		return "", fmt.Errorf("no access token available")
 	}
@@ -334,6 +592,13 @@ func (s *AuthService) GetValidAccessToken(ctx context.Context, userID int64) (st
 
 		// Refresh the token
 		tokenResp, err := s.googleVerifier.RefreshAccessToken(ctx, *oauthAccount.RefreshToken)
+		if errors.Is(err, utils.ErrGoogleTokenRevoked) {
+			// Stop using the account until the user signs in again
+			if err := s.oauthRepo.MarkReconsentRequired(oauthAccount.ID); err != nil {
+				fmt.Printf("Warning: failed to mark Google account of user %d for re-consent: %v\n", userID, err)
+			}
+			return "", ErrCalendarReconsentRequired
+		}
 		if err != nil {
 			return "", fmt.Errorf("failed to refresh token: %w", err)
 		}
@@ -367,10 +632,28 @@ func (s *AuthService) GetValidAccessToken(ctx context.Context, userID int64) (st
 	return *oauthAccount.AccessToken, nil
 }
 
-// generateAuthResponse generates access and refresh tokens for a user
-func (s *AuthService) generateAuthResponse(user *models.User) (*models.AuthResponse, error) {
+// checkActiveUser rejects suspended and deleted users
+func checkActiveUser(user *models.User) error {
+	switch user.Status {
+	case models.UserStatusSuspended:
+		return fmt.Errorf("account suspended")
+	case models.UserStatusDeleted:
+		return fmt.Errorf("account deleted")
+	}
+	return nil
+}
+
+// generateAuthResponse starts a new session and generates its access and refresh tokens
+func (s *AuthService) generateAuthResponse(user *models.User, meta *models.SessionMetadata) (*models.AuthResponse, error) {
+	if err := checkActiveUser(user); err != nil {
+		return nil, err
+	}
+
+	// Each login starts a new token family
+	familyID := uuid.New().String()
+
 	// Generate access token
-	accessToken, err := s.jwtManager.GenerateAccessToken(user)
+	accessToken, err := s.jwtManager.GenerateAccessToken(user, familyID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
@@ -384,9 +667,11 @@ func (s *AuthService) generateAuthResponse(user *models.User) (*models.AuthRespo
 	// Save refresh token to database
 	refreshToken := &models.RefreshToken{
 		UserID:    user.ID,
-		Token:     refreshTokenString,
+		TokenHash: hashRefreshToken(refreshTokenString),
+		FamilyID:  familyID,
 		ExpiresAt: time.Now().Add(s.jwtManager.GetRefreshExpiry()),
 	}
+	applySessionMetadata(refreshToken, meta)
 
 	if err := s.authRepo.SaveRefreshToken(refreshToken); err != nil {
 		return nil, fmt.Errorf("failed to save refresh token: %w", err)
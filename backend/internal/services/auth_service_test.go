@@ -0,0 +1,169 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+func TestHashRefreshToken(t *testing.T) {
+	hash := hashRefreshToken("a1b2c3d4e5f6")
+
+	if len(hash) != 64 {
+		t.Errorf("Expected hex SHA-256 (64 chars), got %d chars", len(hash))
+	}
+	if hash != hashRefreshToken("a1b2c3d4e5f6") {
+		t.Errorf("Expected deterministic hash for lookups")
+	}
+	if hash == hashRefreshToken("a1b2c3d4e5f7") {
+		t.Errorf("Expected different tokens to hash differently")
+	}
+}
+
+func TestApplySessionMetadata(t *testing.T) {
+	platform, userAgent, oldIP, newIP := "IOS", "timingle/1.0", "10.0.0.1", "10.0.0.2"
+	token := &models.RefreshToken{Platform: &platform, UserAgent: &userAgent, IPAddress: &oldIP}
+
+	// A refresh from a new network keeps the device but updates the IP
+	applySessionMetadata(token, &models.SessionMetadata{IPAddress: &newIP})
+
+	if token.Platform == nil || *token.Platform != "IOS" {
+		t.Errorf("Expected platform to be kept, got %v", token.Platform)
+	}
+	if token.UserAgent == nil || *token.UserAgent != userAgent {
+		t.Errorf("Expected user agent to be kept, got %v", token.UserAgent)
+	}
+	if token.IPAddress == nil || *token.IPAddress != newIP {
+		t.Errorf("Expected IP %s, got %v", newIP, token.IPAddress)
+	}
+
+	applySessionMetadata(token, nil)
+	if token.IPAddress == nil || *token.IPAddress != newIP {
+		t.Errorf("Expected nil metadata to leave the token unchanged")
+	}
+}
+
+func TestAppleIdentity(t *testing.T) {
+	tests := []struct {
+		name            string
+		payload         *models.AppleTokenPayload
+		req             *models.AppleLoginRequest
+		wantName        string
+		wantLinkByEmail bool
+	}{
+		{
+			name:            "first login sends the name",
+			payload:         &models.AppleTokenPayload{Subject: "s1", Email: "kim@example.com", EmailVerified: true},
+			req:             &models.AppleLoginRequest{GivenName: " Minji ", FamilyName: "Kim"},
+			wantName:        "Minji Kim",
+			wantLinkByEmail: true,
+		},
+		{
+			name:            "later logins have no name",
+			payload:         &models.AppleTokenPayload{Subject: "s1", Email: "kim@example.com", EmailVerified: true},
+			req:             &models.AppleLoginRequest{},
+			wantName:        "",
+			wantLinkByEmail: true,
+		},
+		{
+			name:            "private relay email never links by email",
+			payload:         &models.AppleTokenPayload{Subject: "s2", Email: "x1@privaterelay.appleid.com", EmailVerified: true, IsPrivateEmail: true},
+			req:             &models.AppleLoginRequest{GivenName: "Minji"},
+			wantName:        "Minji",
+			wantLinkByEmail: false,
+		},
+		{
+			name:            "unverified email never links by email",
+			payload:         &models.AppleTokenPayload{Subject: "s3", Email: "kim@example.com"},
+			req:             &models.AppleLoginRequest{},
+			wantLinkByEmail: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity := appleIdentity(tt.payload, tt.req)
+			if identity.Provider != models.OAuthProviderApple {
+				t.Errorf("Expected apple provider, got %s", identity.Provider)
+			}
+			if identity.Name != tt.wantName {
+				t.Errorf("Expected name %q, got %q", tt.wantName, identity.Name)
+			}
+			if identity.LinkByEmail != tt.wantLinkByEmail {
+				t.Errorf("Expected LinkByEmail %v, got %v", tt.wantLinkByEmail, identity.LinkByEmail)
+			}
+		})
+	}
+}
+
+func TestGoogleIdentityLinksOnlyVerifiedEmail(t *testing.T) {
+	verified := googleIdentity(&models.GoogleTokenPayload{Subject: "g1", Email: "kim@example.com", EmailVerified: true})
+	if !verified.LinkByEmail {
+		t.Error("Expected a verified email to link by email")
+	}
+
+	unverified := googleIdentity(&models.GoogleTokenPayload{Subject: "g2", Email: "kim@example.com"})
+	if unverified.LinkByEmail {
+		t.Error("Expected an unverified email never to link by email")
+	}
+}
+
+func TestHasProviderEmail(t *testing.T) {
+	email := "Kim@Example.com"
+	accounts := []*models.OAuthAccount{
+		{Provider: models.OAuthProviderApple},
+		{Provider: models.OAuthProviderGoogle, Email: &email},
+	}
+
+	if !hasProviderEmail(accounts, "kim@example.com") {
+		t.Error("Expected the provider email to match case-insensitively")
+	}
+	if hasProviderEmail(accounts, "lee@example.com") {
+		t.Error("Expected a different email not to match")
+	}
+	// Users without linked providers only have a self-entered profile email
+	if hasProviderEmail(nil, "kim@example.com") {
+		t.Error("Expected no match without OAuth accounts")
+	}
+}
+
+func TestApplyIdentityKeepsKnownFields(t *testing.T) {
+	name := "Minji Kim"
+	account := &models.OAuthAccount{Name: &name}
+
+	// A later Apple login carries no name; it must not erase the stored one
+	identity := &oauthIdentity{Provider: models.OAuthProviderApple, Subject: "s1", Email: "kim@example.com"}
+	if !needsUpdate(account, identity) {
+		t.Fatalf("Expected new email to need an update")
+	}
+	applyIdentity(account, identity)
+
+	if account.Name == nil || *account.Name != name {
+		t.Errorf("Expected name to be kept, got %v", account.Name)
+	}
+	if account.Email == nil || *account.Email != "kim@example.com" {
+		t.Errorf("Expected email to be set, got %v", account.Email)
+	}
+	if needsUpdate(account, identity) {
+		t.Errorf("Expected no update once fields match")
+	}
+}
+
+func TestCheckActiveUser(t *testing.T) {
+	tests := []struct {
+		status  models.UserStatus
+		wantErr bool
+	}{
+		{status: models.UserStatusActive},
+		{status: ""}, // Not yet loaded from the database
+		{status: models.UserStatusSuspended, wantErr: true},
+		{status: models.UserStatusDeleted, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := checkActiveUser(&models.User{ID: 1, Status: tt.status})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("status %q: expected error %v, got %v", tt.status, tt.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,393 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/repositories"
+)
+
+const (
+	maxAvailabilityRange        = 31 * 24 * time.Hour
+	maxAvailabilityParticipants = 20
+	defaultAvailabilityLimit    = 10
+	maxAvailabilityLimit        = 50
+)
+
+// ErrAvailabilityNotAllowed is returned for participants whose schedule the requester may not see
+var ErrAvailabilityNotAllowed = errors.New("participants must be your friends or members of an event you created")
+
+// WorkingHours is the daily window, in each user's timezone, in which slots may be suggested
+type WorkingHours struct {
+	Start           time.Duration // Offset from local midnight
+	End             time.Duration // Offset from local midnight
+	IncludeWeekends bool
+}
+
+// ParseWorkingHours parses "HH:MM" start and end times
+func ParseWorkingHours(start, end string, includeWeekends bool) (WorkingHours, error) {
+	startOffset, err := parseClock(start)
+	if err != nil {
+		return WorkingHours{}, fmt.Errorf("invalid workday start: %w", err)
+	}
+
+	endOffset, err := parseClock(end)
+	if err != nil {
+		return WorkingHours{}, fmt.Errorf("invalid workday end: %w", err)
+	}
+
+	if endOffset <= startOffset {
+		return WorkingHours{}, fmt.Errorf("workday end must be after workday start")
+	}
+
+	return WorkingHours{Start: startOffset, End: endOffset, IncludeWeekends: includeWeekends}, nil
+}
+
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// AvailabilityService finds common free time across users
+type AvailabilityService struct {
+	eventService    *EventService
+	userRepo        *repositories.UserRepository
+	eventRepo       *repositories.EventRepository
+	friendRepo      *repositories.FriendRepository
+	calendarService *CalendarService
+	hours           WorkingHours
+	slotStep        time.Duration
+}
+
+// NewAvailabilityService creates a new availability service
+func NewAvailabilityService(
+	eventService *EventService,
+	userRepo *repositories.UserRepository,
+	eventRepo *repositories.EventRepository,
+	friendRepo *repositories.FriendRepository,
+	calendarService *CalendarService,
+	hours WorkingHours,
+	slotStep time.Duration,
+) *AvailabilityService {
+	if slotStep <= 0 {
+		slotStep = 30 * time.Minute
+	}
+	return &AvailabilityService{
+		eventService:    eventService,
+		userRepo:        userRepo,
+		eventRepo:       eventRepo,
+		friendRepo:      friendRepo,
+		calendarService: calendarService,
+		hours:           hours,
+		slotStep:        slotStep,
+	}
+}
+
+// availabilityParticipant holds the per-user inputs of a search
+type availabilityParticipant struct {
+	location *time.Location
+	free     []models.TimeWindow
+}
+
+// FindCommonSlots returns slots of the requested duration when the requester
+// and all participants are free, best first
+func (s *AvailabilityService) FindCommonSlots(ctx context.Context, requesterID int64, req *models.FindSlotsRequest) ([]*models.CandidateSlot, error) {
+	if !req.RangeEnd.After(req.RangeStart) {
+		return nil, fmt.Errorf("range end must be after range start")
+	}
+	if req.RangeEnd.Sub(req.RangeStart) > maxAvailabilityRange {
+		return nil, fmt.Errorf("range must not exceed 31 days")
+	}
+
+	hours := s.hours
+	if req.WorkdayStart != nil || req.WorkdayEnd != nil {
+		start, end := formatClock(hours.Start), formatClock(hours.End)
+		if req.WorkdayStart != nil {
+			start = *req.WorkdayStart
+		}
+		if req.WorkdayEnd != nil {
+			end = *req.WorkdayEnd
+		}
+		var err error
+		hours, err = ParseWorkingHours(start, end, hours.IncludeWeekends)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Requester is always included
+	userIDs := []int64{requesterID}
+	seen := map[int64]bool{requesterID: true}
+	for _, id := range req.ParticipantIDs {
+		if !seen[id] {
+			seen[id] = true
+			userIDs = append(userIDs, id)
+		}
+	}
+	if len(userIDs) > maxAvailabilityParticipants {
+		return nil, fmt.Errorf("too many participants (max %d)", maxAvailabilityParticipants)
+	}
+	for _, userID := range userIDs[1:] {
+		if err := s.checkParticipant(requesterID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	window := models.TimeWindow{Start: req.RangeStart, End: req.RangeEnd}
+	participants := make([]*availabilityParticipant, 0, len(userIDs))
+	for _, userID := range userIDs {
+		participant, err := s.loadParticipant(ctx, userID, window, hours)
+		if err != nil {
+			return nil, err
+		}
+		participants = append(participants, participant)
+	}
+
+	common := participants[0].free
+	for _, participant := range participants[1:] {
+		common = intersectWindows(common, participant.free)
+	}
+
+	duration := time.Duration(req.DurationMinutes) * time.Minute
+	slots := candidateSlots(common, duration, s.slotStep)
+	for _, slot := range slots {
+		slot.Score = scoreSlot(slot, participants, hours)
+	}
+
+	sort.SliceStable(slots, func(i, j int) bool {
+		if slots[i].Score != slots[j].Score {
+			return slots[i].Score > slots[j].Score
+		}
+		return slots[i].StartTime.Before(slots[j].StartTime)
+	})
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultAvailabilityLimit
+	}
+	if limit > maxAvailabilityLimit {
+		limit = maxAvailabilityLimit
+	}
+	if len(slots) > limit {
+		slots = slots[:limit]
+	}
+
+	return slots, nil
+}
+
+// checkParticipant verifies the requester may see a user's busy times:
+// the user is a friend or a member of an event the requester created, and neither blocked the other
+func (s *AvailabilityService) checkParticipant(requesterID, userID int64) error {
+	blocked, err := s.friendRepo.IsBlocked(requesterID, userID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrAvailabilityNotAllowed
+	}
+
+	friends, err := s.friendRepo.AreFriends(requesterID, userID)
+	if err != nil {
+		return err
+	}
+	if friends {
+		return nil
+	}
+
+	member, err := s.eventRepo.HasParticipantInCreatedEvents(requesterID, userID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return ErrAvailabilityNotAllowed
+	}
+
+	return nil
+}
+
+// loadParticipant computes a user's free windows from working hours, timingle events and Google Calendar
+func (s *AvailabilityService) loadParticipant(ctx context.Context, userID int64, window models.TimeWindow, hours WorkingHours) (*availabilityParticipant, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user %d not found", userID)
+	}
+
+	location, err := time.LoadLocation(user.Timezone)
+	if err != nil || user.Timezone == "" {
+		location = time.UTC
+	}
+
+	busy := []models.TimeWindow{}
+
+	events, err := s.eventService.GetUserEvents(userID, "", &window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load events for user %d: %w", userID, err)
+	}
+	for _, event := range events {
+		if event.Status == models.EventStatusCanceled {
+			continue
+		}
+		busy = append(busy, models.TimeWindow{Start: event.StartTime, End: event.EndTime})
+	}
+
+	hasAccess, err := s.calendarService.HasCalendarAccess(ctx, userID)
+	if err != nil {
+		fmt.Printf("Warning: failed to check calendar access for user %d: %v\n", userID, err)
+	}
+	if hasAccess {
+		blocks, err := s.calendarService.GetBusyBlocks(ctx, userID, window.Start, window.End)
+		if err != nil {
+			// Log error but continue with timingle events only
+			fmt.Printf("Warning: failed to get busy blocks for user %d: %v\n", userID, err)
+		} else {
+			busy = append(busy, blocks...)
+		}
+	}
+
+	return &availabilityParticipant{
+		location: location,
+		free:     subtractWindows(workingWindows(location, window, hours), busy),
+	}, nil
+}
+
+func formatClock(offset time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(offset/time.Hour), int(offset%time.Hour/time.Minute))
+}
+
+// workingWindows returns the working hours of each day in loc, clipped to window
+func workingWindows(loc *time.Location, window models.TimeWindow, hours WorkingHours) []models.TimeWindow {
+	result := []models.TimeWindow{}
+
+	first := window.Start.In(loc)
+	day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc)
+	for day.Before(window.End) {
+		next := time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+
+		weekend := day.Weekday() == time.Saturday || day.Weekday() == time.Sunday
+		if hours.IncludeWeekends || !weekend {
+			// Build from wall clock so DST days keep the same local hours
+			start := time.Date(day.Year(), day.Month(), day.Day(), int(hours.Start/time.Hour), int(hours.Start%time.Hour/time.Minute), 0, 0, loc)
+			end := time.Date(day.Year(), day.Month(), day.Day(), int(hours.End/time.Hour), int(hours.End%time.Hour/time.Minute), 0, 0, loc)
+
+			if start.Before(window.Start) {
+				start = window.Start
+			}
+			if end.After(window.End) {
+				end = window.End
+			}
+			if end.After(start) {
+				result = append(result, models.TimeWindow{Start: start, End: end})
+			}
+		}
+
+		day = next
+	}
+
+	return result
+}
+
+// subtractWindows removes busy periods from sorted, non-overlapping free windows
+func subtractWindows(free, busy []models.TimeWindow) []models.TimeWindow {
+	sorted := make([]models.TimeWindow, len(busy))
+	copy(sorted, busy)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	result := []models.TimeWindow{}
+	for _, window := range free {
+		cursor := window.Start
+		for _, block := range sorted {
+			if !block.End.After(cursor) || !block.Start.Before(window.End) {
+				continue
+			}
+			if block.Start.After(cursor) {
+				result = append(result, models.TimeWindow{Start: cursor, End: block.Start})
+			}
+			if block.End.After(cursor) {
+				cursor = block.End
+			}
+		}
+		if window.End.After(cursor) {
+			result = append(result, models.TimeWindow{Start: cursor, End: window.End})
+		}
+	}
+
+	return result
+}
+
+// intersectWindows returns the overlap of two sorted, non-overlapping window lists
+func intersectWindows(a, b []models.TimeWindow) []models.TimeWindow {
+	result := []models.TimeWindow{}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		start := a[i].Start
+		if b[j].Start.After(start) {
+			start = b[j].Start
+		}
+		end := a[i].End
+		if b[j].End.Before(end) {
+			end = b[j].End
+		}
+		if end.After(start) {
+			result = append(result, models.TimeWindow{Start: start, End: end})
+		}
+
+		if a[i].End.Before(b[j].End) {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return result
+}
+
+// candidateSlots lists slots of the given duration inside free windows, starting on step boundaries
+func candidateSlots(free []models.TimeWindow, duration, step time.Duration) []*models.CandidateSlot {
+	slots := []*models.CandidateSlot{}
+	for _, window := range free {
+		start := window.Start.Truncate(step)
+		if start.Before(window.Start) {
+			start = start.Add(step)
+		}
+		for !start.Add(duration).After(window.End) {
+			slots = append(slots, &models.CandidateSlot{
+				StartTime: start,
+				EndTime:   start.Add(duration),
+			})
+			start = start.Add(step)
+		}
+	}
+
+	return slots
+}
+
+// scoreSlot rates a slot from 0 to 1 by how close it is to the middle of each participant's working day
+func scoreSlot(slot *models.CandidateSlot, participants []*availabilityParticipant, hours WorkingHours) float64 {
+	if len(participants) == 0 {
+		return 0
+	}
+
+	halfDay := float64(hours.End-hours.Start) / 2
+	midOffset := hours.Start + (hours.End-hours.Start)/2
+	slotMid := slot.StartTime.Add(slot.EndTime.Sub(slot.StartTime) / 2)
+
+	total := 0.0
+	for _, participant := range participants {
+		local := slotMid.In(participant.location)
+		dayMid := time.Date(local.Year(), local.Month(), local.Day(), int(midOffset/time.Hour), int(midOffset%time.Hour/time.Minute), 0, 0, participant.location)
+		distance := math.Abs(float64(local.Sub(dayMid)))
+		total += 1 - math.Min(distance/halfDay, 1)
+	}
+
+	return math.Round(total/float64(len(participants))*1000) / 1000
+}
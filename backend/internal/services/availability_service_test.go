@@ -0,0 +1,120 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+func at(day, hour, minute int) time.Time {
+	return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestParseWorkingHours(t *testing.T) {
+	hours, err := ParseWorkingHours("09:30", "18:00", false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if hours.Start != 9*time.Hour+30*time.Minute || hours.End != 18*time.Hour {
+		t.Errorf("Unexpected hours: %+v", hours)
+	}
+
+	if _, err := ParseWorkingHours("18:00", "09:00", false); err == nil {
+		t.Error("Expected error when end is before start")
+	}
+	if _, err := ParseWorkingHours("9am", "18:00", false); err == nil {
+		t.Error("Expected error for invalid clock")
+	}
+}
+
+func TestWorkingWindows_SkipsWeekendsAndUsesTimezone(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Seoul")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	hours := WorkingHours{Start: 9 * time.Hour, End: 18 * time.Hour}
+
+	// Friday 2024-01-05 through Monday 2024-01-08 (UTC)
+	window := models.TimeWindow{Start: at(5, 0, 0), End: at(8, 12, 0)}
+	got := workingWindows(loc, window, hours)
+
+	if len(got) != 2 {
+		t.Fatalf("Expected Friday and Monday windows, got %d: %v", len(got), got)
+	}
+	// 09:00 KST on Friday is 00:00 UTC
+	if !got[0].Start.Equal(at(5, 0, 0)) || !got[0].End.Equal(at(5, 9, 0)) {
+		t.Errorf("Unexpected Friday window: %v", got[0])
+	}
+	if !got[1].Start.Equal(at(8, 0, 0)) || !got[1].End.Equal(at(8, 9, 0)) {
+		t.Errorf("Unexpected Monday window: %v", got[1])
+	}
+}
+
+func TestSubtractAndIntersectWindows(t *testing.T) {
+	free := []models.TimeWindow{{Start: at(2, 9, 0), End: at(2, 18, 0)}}
+	busy := []models.TimeWindow{
+		{Start: at(2, 13, 0), End: at(2, 14, 0)},
+		{Start: at(2, 8, 0), End: at(2, 10, 0)},
+		{Start: at(2, 13, 30), End: at(2, 15, 0)},
+	}
+
+	gotA := subtractWindows(free, busy)
+	expectedA := []models.TimeWindow{
+		{Start: at(2, 10, 0), End: at(2, 13, 0)},
+		{Start: at(2, 15, 0), End: at(2, 18, 0)},
+	}
+	assertWindows(t, expectedA, gotA)
+
+	b := []models.TimeWindow{
+		{Start: at(2, 12, 0), End: at(2, 16, 0)},
+	}
+	expected := []models.TimeWindow{
+		{Start: at(2, 12, 0), End: at(2, 13, 0)},
+		{Start: at(2, 15, 0), End: at(2, 16, 0)},
+	}
+	assertWindows(t, expected, intersectWindows(gotA, b))
+}
+
+func TestCandidateSlots(t *testing.T) {
+	free := []models.TimeWindow{{Start: at(2, 10, 10), End: at(2, 12, 0)}}
+
+	slots := candidateSlots(free, time.Hour, 30*time.Minute)
+
+	expected := []time.Time{at(2, 10, 30), at(2, 11, 0)}
+	if len(slots) != len(expected) {
+		t.Fatalf("Expected %d slots, got %d", len(expected), len(slots))
+	}
+	for i, start := range expected {
+		if !slots[i].StartTime.Equal(start) {
+			t.Errorf("Slot %d: expected %s, got %s", i, start, slots[i].StartTime)
+		}
+	}
+}
+
+func TestScoreSlot_PrefersMiddleOfDay(t *testing.T) {
+	hours := WorkingHours{Start: 9 * time.Hour, End: 17 * time.Hour}
+	participants := []*availabilityParticipant{{location: time.UTC}}
+
+	midday := &models.CandidateSlot{StartTime: at(2, 12, 30), EndTime: at(2, 13, 30)}
+	morning := &models.CandidateSlot{StartTime: at(2, 9, 0), EndTime: at(2, 10, 0)}
+
+	if scoreSlot(midday, participants, hours) != 1 {
+		t.Errorf("Expected midday slot to score 1, got %v", scoreSlot(midday, participants, hours))
+	}
+	if scoreSlot(morning, participants, hours) >= scoreSlot(midday, participants, hours) {
+		t.Error("Expected morning slot to score lower than midday slot")
+	}
+}
+
+func assertWindows(t *testing.T, expected, got []models.TimeWindow) {
+	t.Helper()
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d windows, got %d: %v", len(expected), len(got), got)
+	}
+	for i := range expected {
+		if !got[i].Start.Equal(expected[i].Start) || !got[i].End.Equal(expected[i].End) {
+			t.Errorf("Window %d: expected %v, got %v", i, expected[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/repositories"
+	"github.com/khchoi-tnh/timingle/pkg/ical"
+	"github.com/khchoi-tnh/timingle/pkg/rrule"
+	"github.com/khchoi-tnh/timingle/pkg/utils"
+)
+
+const (
+	// feedTokenBytes is the random length of a feed token (32 URL-safe characters)
+	feedTokenBytes = 24
+
+	// feedRefreshInterval is how often calendar apps are asked to refetch the feed
+	feedRefreshInterval = time.Hour
+
+	feedCalendarName = "timingle"
+)
+
+// ErrCalendarFeedNotFound is returned for an unknown or revoked feed
+var ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+// CalendarFeedService exports events as iCalendar (.ics) for Apple Calendar, Outlook and other apps
+// A user can download a single event or subscribe to a secret feed URL listing all their events
+type CalendarFeedService struct {
+	feedRepo  *repositories.CalendarFeedRepository
+	eventRepo *repositories.EventRepository
+	baseURL   string // Public API URL the feed URLs are built on
+}
+
+// NewCalendarFeedService creates a new calendar feed service
+func NewCalendarFeedService(
+	feedRepo *repositories.CalendarFeedRepository,
+	eventRepo *repositories.EventRepository,
+	baseURL string,
+) *CalendarFeedService {
+	return &CalendarFeedService{
+		feedRepo:  feedRepo,
+		eventRepo: eventRepo,
+		baseURL:   baseURL,
+	}
+}
+
+// CreateFeed creates the user's subscription feed, or replaces its URL if one exists
+// The returned URL is the only time the token is shown; the previous URL stops working
+func (s *CalendarFeedService) CreateFeed(userID int64) (*models.CalendarFeedResponse, error) {
+	token, err := utils.GenerateRandomString(feedTokenBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate feed token: %w", err)
+	}
+
+	feed := &models.CalendarFeed{
+		UserID:    userID,
+		TokenHash: hashFeedToken(token),
+	}
+	if err := s.feedRepo.Upsert(feed); err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/feeds/%s.ics", s.baseURL, token)
+	return &models.CalendarFeedResponse{
+		Active:    true,
+		URL:       url,
+		WebcalURL: webcalURL(url),
+		CreatedAt: &feed.CreatedAt,
+	}, nil
+}
+
+// GetFeed returns whether the user has a subscription feed (without its URL)
+func (s *CalendarFeedService) GetFeed(userID int64) (*models.CalendarFeedResponse, error) {
+	feed, err := s.feedRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if feed == nil {
+		return &models.CalendarFeedResponse{Active: false}, nil
+	}
+
+	return &models.CalendarFeedResponse{
+		Active:         true,
+		CreatedAt:      &feed.CreatedAt,
+		LastAccessedAt: feed.LastAccessedAt,
+	}, nil
+}
+
+// RevokeFeed deletes the user's subscription feed; subscribed apps stop receiving updates
+func (s *CalendarFeedService) RevokeFeed(userID int64) error {
+	deleted, err := s.feedRepo.Delete(userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrCalendarFeedNotFound
+	}
+	return nil
+}
+
+// Feed returns the iCalendar feed a token belongs to: every event the owner created or
+// participates in, with canceled events and occurrences as STATUS:CANCELLED
+func (s *CalendarFeedService) Feed(token string) ([]byte, error) {
+	if token == "" {
+		return nil, ErrCalendarFeedNotFound
+	}
+
+	feed, err := s.feedRepo.FindByTokenHash(hashFeedToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if feed == nil {
+		return nil, ErrCalendarFeedNotFound
+	}
+
+	events, err := s.eventRepo.FindMemberEvents(feed.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &ical.Calendar{
+		Name:    feedCalendarName,
+		Method:  "PUBLISH",
+		Stamp:   time.Now(),
+		Refresh: feedRefreshInterval,
+	}
+	for _, event := range events {
+		vevents, err := s.icalEvents(event)
+		if err != nil {
+			// Log error but continue
+			fmt.Printf("Warning: failed to export event %d to feed: %v\n", event.ID, err)
+			continue
+		}
+		calendar.Events = append(calendar.Events, vevents...)
+	}
+
+	if err := s.feedRepo.Touch(feed.UserID); err != nil {
+		// Log error but continue
+		fmt.Printf("Warning: failed to update calendar feed access time: %v\n", err)
+	}
+
+	return calendar.Bytes(), nil
+}
+
+// EventICS returns a single event as an iCalendar file
+// Only members (creator or participants) can download it
+func (s *CalendarFeedService) EventICS(eventID, userID int64) ([]byte, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("event not found")
+	}
+
+	if event.CreatorID != userID {
+		isParticipant, err := s.eventRepo.IsUserParticipant(eventID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !isParticipant {
+			return nil, fmt.Errorf("user is not a member of this event")
+		}
+	}
+
+	vevents, err := s.icalEvents(event)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &ical.Calendar{
+		Method: "PUBLISH",
+		Stamp:  time.Now(),
+		Events: vevents,
+	}
+	return calendar.Bytes(), nil
+}
+
+// icalEvents loads the occurrence overrides of a recurring event and converts it to VEVENTs
+func (s *CalendarFeedService) icalEvents(event *models.Event) ([]*ical.Event, error) {
+	var overrides []*models.EventOccurrenceOverride
+	if event.IsRecurring() {
+		var err error
+		overrides, err = s.eventRepo.FindOccurrenceOverrides(event.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return toICalEvents(event, overrides)
+}
+
+// toICalEvents converts an event to a VEVENT, plus one VEVENT per overridden occurrence
+// (same UID with RECURRENCE-ID), so calendar apps show changed and canceled occurrences
+func toICalEvents(event *models.Event, overrides []*models.EventOccurrenceOverride) ([]*ical.Event, error) {
+	base := &ical.Event{
+		UID:          eventUID(event.ID),
+		Summary:      event.Title,
+		Description:  stringValue(event.Description),
+		Location:     stringValue(event.Location),
+		Status:       icalStatus(event.Status),
+		Start:        event.StartTime,
+		End:          event.EndTime,
+		AllDay:       event.AllDay,
+		Created:      event.CreatedAt,
+		LastModified: event.UpdatedAt,
+	}
+	if !event.IsRecurring() {
+		return []*ical.Event{base}, nil
+	}
+
+	rule, err := icalRecurrenceRule(event)
+	if err != nil {
+		return nil, err
+	}
+	// Recurrences keep their wall-clock time in the event's zone across DST changes
+	base.Zone = event.Zone()
+	base.RecurrenceRule = rule
+	base.ExDates = event.RecurrenceExDates
+
+	events := []*ical.Event{base}
+	duration := event.EndTime.Sub(event.StartTime)
+	for _, override := range overrides {
+		if event.IsExcluded(override.OccurrenceStart) {
+			continue
+		}
+
+		occurrence := *base
+		occurrence.RecurrenceRule = ""
+		occurrence.ExDates = nil
+		recurrenceID := override.OccurrenceStart
+		occurrence.RecurrenceID = &recurrenceID
+		occurrence.Start = override.OccurrenceStart
+		occurrence.End = override.OccurrenceStart.Add(duration)
+		occurrence.LastModified = override.UpdatedAt
+		if override.Title != nil {
+			occurrence.Summary = *override.Title
+		}
+		if override.Description != nil {
+			occurrence.Description = *override.Description
+		}
+		if override.Location != nil {
+			occurrence.Location = *override.Location
+		}
+		if override.StartTime != nil {
+			occurrence.Start = *override.StartTime
+		}
+		if override.EndTime != nil {
+			occurrence.End = *override.EndTime
+		}
+		if override.IsCanceled {
+			occurrence.Status = ical.StatusCancelled
+		}
+		events = append(events, &occurrence)
+	}
+
+	return events, nil
+}
+
+// icalRecurrenceRule returns the RRULE of an event for export
+// UNTIL of an all-day event must be a date, like its DTSTART
+func icalRecurrenceRule(event *models.Event) (string, error) {
+	rule, err := rrule.Parse(*event.RecurrenceRule)
+	if err != nil {
+		return "", fmt.Errorf("invalid recurrence rule: %w", err)
+	}
+
+	value := rule.String()
+	if event.AllDay && !rule.Until.IsZero() {
+		until := rule.Until.UTC()
+		value = strings.Replace(value, "UNTIL="+until.Format("20060102T150405Z"), "UNTIL="+until.Format("20060102"), 1)
+	}
+	return value, nil
+}
+
+// eventUID returns the iCalendar UID of an event, stable across downloads and feed refreshes
+// so calendar apps update their copy instead of adding a duplicate
+func eventUID(eventID int64) string {
+	return fmt.Sprintf("event-%d@timingle", eventID)
+}
+
+// icalStatus maps an event status to an iCalendar STATUS
+func icalStatus(status models.EventStatus) string {
+	switch status {
+	case models.EventStatusProposed:
+		return ical.StatusTentative
+	case models.EventStatusCanceled:
+		return ical.StatusCancelled
+	default:
+		return ical.StatusConfirmed
+	}
+}
+
+// hashFeedToken hashes a feed token for storage and lookup
+func hashFeedToken(token string) string {
+	return hashRefreshToken(token)
+}
+
+// webcalURL returns the webcal:// form of a feed URL, which opens the subscribe dialog of calendar apps
+func webcalURL(url string) string {
+	for _, scheme := range []string{"https://", "http://"} {
+		if strings.HasPrefix(url, scheme) {
+			return "webcal://" + strings.TrimPrefix(url, scheme)
+		}
+	}
+	return url
+}
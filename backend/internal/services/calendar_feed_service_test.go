@@ -0,0 +1,132 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/pkg/ical"
+)
+
+func TestToICalEvents(t *testing.T) {
+	location := "Cafe"
+	event := &models.Event{
+		ID:        42,
+		Title:     "Lunch",
+		Location:  &location,
+		StartTime: time.Date(2026, 3, 2, 3, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2026, 3, 2, 4, 0, 0, 0, time.UTC),
+		Status:    models.EventStatusCanceled,
+		Timezone:  "Asia/Seoul",
+	}
+
+	events, err := toICalEvents(event, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 VEVENT, got %d", len(events))
+	}
+
+	vevent := events[0]
+	if vevent.UID != "event-42@timingle" {
+		t.Errorf("Expected a stable UID, got %q", vevent.UID)
+	}
+	if vevent.Status != ical.StatusCancelled {
+		t.Errorf("Expected a canceled event to be CANCELLED, got %q", vevent.Status)
+	}
+	if vevent.Location != location || vevent.Zone != nil {
+		t.Errorf("Expected location %q and a UTC single event, got %q, %v", location, vevent.Location, vevent.Zone)
+	}
+}
+
+func TestToICalEventsRecurringOverrides(t *testing.T) {
+	rule := "FREQ=WEEKLY"
+	start := time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC) // 09:00 in New York
+	event := &models.Event{
+		ID:                7,
+		Title:             "Standup",
+		StartTime:         start,
+		EndTime:           start.Add(30 * time.Minute),
+		Status:            models.EventStatusConfirmed,
+		Timezone:          "America/New_York",
+		RecurrenceRule:    &rule,
+		RecurrenceExDates: []time.Time{start.AddDate(0, 0, 21)},
+	}
+
+	moved := time.Date(2026, 3, 16, 15, 0, 0, 0, time.UTC)
+	movedEnd := moved.Add(time.Hour)
+	title := "Standup (moved)"
+	overrides := []*models.EventOccurrenceOverride{
+		{EventID: 7, OccurrenceStart: start.AddDate(0, 0, 7), IsCanceled: true},
+		{EventID: 7, OccurrenceStart: time.Date(2026, 3, 16, 13, 0, 0, 0, time.UTC), Title: &title, StartTime: &moved, EndTime: &movedEnd},
+		// Excluded occurrences are left out
+		{EventID: 7, OccurrenceStart: start.AddDate(0, 0, 21), IsCanceled: true},
+	}
+
+	events, err := toICalEvents(event, overrides)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Expected the series and 2 overrides, got %d", len(events))
+	}
+
+	series := events[0]
+	if series.RecurrenceRule != "FREQ=WEEKLY" || series.Zone == nil || series.Zone.String() != "America/New_York" {
+		t.Errorf("Expected a weekly series in America/New_York, got %q in %v", series.RecurrenceRule, series.Zone)
+	}
+	if len(series.ExDates) != 1 {
+		t.Errorf("Expected the EXDATE to be kept, got %v", series.ExDates)
+	}
+
+	canceled := events[1]
+	if canceled.UID != series.UID || canceled.RecurrenceID == nil || !canceled.RecurrenceID.Equal(start.AddDate(0, 0, 7)) {
+		t.Errorf("Expected the canceled occurrence to reference the series, got %q %v", canceled.UID, canceled.RecurrenceID)
+	}
+	if canceled.Status != ical.StatusCancelled || canceled.RecurrenceRule != "" {
+		t.Errorf("Expected a CANCELLED occurrence without RRULE, got %q %q", canceled.Status, canceled.RecurrenceRule)
+	}
+
+	changed := events[2]
+	if changed.Summary != title || !changed.Start.Equal(moved) || !changed.End.Equal(movedEnd) {
+		t.Errorf("Expected the changed occurrence at %v, got %q %v - %v", moved, changed.Summary, changed.Start, changed.End)
+	}
+	if changed.Status != ical.StatusConfirmed {
+		t.Errorf("Expected the changed occurrence to keep the series status, got %q", changed.Status)
+	}
+	if series.Summary != "Standup" {
+		t.Errorf("Expected the series to be unchanged, got %q", series.Summary)
+	}
+}
+
+func TestICalRecurrenceRuleAllDayUntil(t *testing.T) {
+	rule := "FREQ=DAILY;UNTIL=20260310T000000Z"
+	event := &models.Event{AllDay: true, RecurrenceRule: &rule}
+
+	value, err := icalRecurrenceRule(event)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if value != "FREQ=DAILY;UNTIL=20260310" {
+		t.Errorf("Expected UNTIL as a date, got %q", value)
+	}
+
+	event.AllDay = false
+	if value, _ := icalRecurrenceRule(event); value != rule {
+		t.Errorf("Expected a timed rule to keep UNTIL in UTC, got %q", value)
+	}
+}
+
+func TestWebcalURL(t *testing.T) {
+	tests := map[string]string{
+		"https://timingle.app/feeds/abc.ics":  "webcal://timingle.app/feeds/abc.ics",
+		"http://localhost:8080/feeds/abc.ics": "webcal://localhost:8080/feeds/abc.ics",
+	}
+
+	for url, expected := range tests {
+		if got := webcalURL(url); got != expected {
+			t.Errorf("webcalURL(%q) = %q, expected %q", url, got, expected)
+		}
+	}
+}
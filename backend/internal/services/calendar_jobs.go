@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+// calendarJobTimeout bounds the Google API calls of one calendar sync job
+const calendarJobTimeout = 2 * time.Minute
+
+// CalendarJobPolicy holds the retry settings of calendar sync jobs
+type CalendarJobPolicy struct {
+	MaxAttempts int           // Deliveries before a failing job is dropped
+	BackoffBase time.Duration // Delay before the first retry, doubled for every further attempt
+	BackoffMax  time.Duration // Upper bound of the retry delay
+}
+
+// calendarJobBackoff returns the delay before retrying a job that failed on the given attempt (1-based)
+func calendarJobBackoff(attempt int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
+
+// CalendarJobConsumer runs calendar sync jobs from the CALENDAR_SYNC stream (cmd/worker)
+type CalendarJobConsumer struct {
+	js                  nats.JetStreamContext
+	calendarService     *CalendarService
+	calendarSyncService *CalendarSyncService
+	policy              CalendarJobPolicy
+	sub                 *nats.Subscription
+}
+
+// NewCalendarJobConsumer creates a new calendar sync job consumer
+func NewCalendarJobConsumer(
+	js nats.JetStreamContext,
+	calendarService *CalendarService,
+	calendarSyncService *CalendarSyncService,
+	policy CalendarJobPolicy,
+) *CalendarJobConsumer {
+	return &CalendarJobConsumer{
+		js:                  js,
+		calendarService:     calendarService,
+		calendarSyncService: calendarSyncService,
+		policy:              policy,
+	}
+}
+
+// Start subscribes the durable calendar sync consumer
+func (c *CalendarJobConsumer) Start() error {
+	sub, err := c.js.Subscribe(CalendarSyncSubject, c.handle,
+		nats.Durable("calendar-sync"),
+		nats.ManualAck(),
+		nats.AckWait(calendarJobTimeout+30*time.Second),
+		nats.MaxDeliver(c.policy.MaxAttempts),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", CalendarSyncSubject, err)
+	}
+	c.sub = sub
+	return nil
+}
+
+// Stop removes the subscription; the durable consumer keeps its position
+func (c *CalendarJobConsumer) Stop() {
+	if c.sub != nil {
+		c.sub.Unsubscribe()
+		c.sub = nil
+	}
+}
+
+// handle runs a job and acknowledges it, or schedules a retry with exponential backoff
+func (c *CalendarJobConsumer) handle(msg *nats.Msg) {
+	var job models.CalendarSyncJob
+	if err := json.Unmarshal(msg.Data, &job); err != nil {
+		// Malformed payloads will never succeed; drop them
+		log.Printf("Failed to unmarshal calendar sync job: %v", err)
+		msg.Term()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), calendarJobTimeout)
+	defer cancel()
+
+	err := c.run(ctx, &job)
+	if err == nil {
+		msg.Ack()
+		return
+	}
+
+	// Retrying cannot help until the user signs in with Google again
+	if errors.Is(err, ErrCalendarReconsentRequired) {
+		log.Printf("Dropped calendar sync job %s of user %d: %v", job.Kind, job.UserID, err)
+		msg.Term()
+		return
+	}
+
+	attempt := 1
+	if meta, metaErr := msg.Metadata(); metaErr == nil {
+		attempt = int(meta.NumDelivered)
+	}
+	if attempt >= c.policy.MaxAttempts {
+		log.Printf("Gave up calendar sync job %s of user %d after %d attempts: %v", job.Kind, job.UserID, attempt, err)
+		msg.Term()
+		return
+	}
+
+	delay := calendarJobBackoff(attempt, c.policy.BackoffBase, c.policy.BackoffMax)
+	log.Printf("Calendar sync job %s of user %d failed (attempt %d), retrying in %s: %v", job.Kind, job.UserID, attempt, delay, err)
+	msg.NakWithDelay(delay)
+}
+
+// run does the work of a job
+func (c *CalendarJobConsumer) run(ctx context.Context, job *models.CalendarSyncJob) error {
+	switch job.Kind {
+	case models.CalendarSyncJobUpdateCopy:
+		return c.calendarService.updateCopy(ctx, job)
+	case models.CalendarSyncJobDeleteCopy:
+		return c.calendarService.deleteCopy(ctx, job)
+	case models.CalendarSyncJobPull:
+		_, err := c.calendarSyncService.SyncUser(ctx, job.UserID)
+		return err
+	case models.CalendarSyncJobReconcile:
+		if err := c.calendarService.reconcile(ctx, job.UserID); err != nil {
+			return err
+		}
+		_, err := c.calendarSyncService.SyncUser(ctx, job.UserID)
+		return err
+	default:
+		log.Printf("Unknown calendar sync job kind %q", job.Kind)
+		return nil
+	}
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalendarJobBackoff(t *testing.T) {
+	base := 30 * time.Second
+	max := 10 * time.Minute
+
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{5, 8 * time.Minute},
+		{6, 10 * time.Minute}, // capped
+		{50, 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := calendarJobBackoff(tt.attempt, base, max); got != tt.expected {
+			t.Errorf("Attempt %d: expected %s, got %s", tt.attempt, tt.expected, got)
+		}
+	}
+}
@@ -2,34 +2,65 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
+	"github.com/nats-io/nats.go"
 	"golang.org/x/oauth2"
 	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
 
 	"github.com/khchoi-tnh/timingle/internal/models"
 	"github.com/khchoi-tnh/timingle/internal/repositories"
 )
 
+// CalendarSyncSubject is the CALENDAR_SYNC stream subject calendar sync jobs are published to
+const CalendarSyncSubject = "calendar.sync.job"
+
+// dateLayout is the format of all-day (date-only) Google Calendar times
+const dateLayout = "2006-01-02"
+
+// EXDATE value layouts of Google Calendar recurrences (iCalendar DATE and local DATE-TIME)
+const (
+	recurrenceDateLayout     = "20060102"
+	recurrenceDateTimeLayout = "20060102T150405"
+)
+
 // CalendarService handles Google Calendar integration
 type CalendarService struct {
 	authService *AuthService
 	eventRepo   *repositories.EventRepository
+	userRepo    *repositories.UserRepository
 	oauthRepo   *repositories.OAuthRepository
+	syncRepo    *repositories.CalendarSyncRepository
+	nats        nats.JetStreamContext
+	apiURL      string // Google Calendar API base URL, empty for the default
 }
 
 // NewCalendarService creates a new calendar service
+// apiURL overrides the Google Calendar API base URL (e.g. a local stand-in); empty uses Google
 func NewCalendarService(
 	authService *AuthService,
 	eventRepo *repositories.EventRepository,
+	userRepo *repositories.UserRepository,
 	oauthRepo *repositories.OAuthRepository,
+	syncRepo *repositories.CalendarSyncRepository,
+	nats nats.JetStreamContext,
+	apiURL string,
 ) *CalendarService {
 	return &CalendarService{
 		authService: authService,
 		eventRepo:   eventRepo,
+		userRepo:    userRepo,
 		oauthRepo:   oauthRepo,
+		syncRepo:    syncRepo,
+		nats:        nats,
+		apiURL:      apiURL,
 	}
 }
 
@@ -41,6 +72,7 @@ type CalendarEvent struct {
 	Location    string    `json:"location,omitempty"`
 	StartTime   time.Time `json:"start_time"`
 	EndTime     time.Time `json:"end_time"`
+	AllDay      bool      `json:"all_day,omitempty"`
 	HtmlLink    string    `json:"html_link,omitempty"`
 }
 
@@ -51,6 +83,11 @@ func (s *CalendarService) getCalendarService(ctx context.Context, userID int64)
 		return nil, fmt.Errorf("failed to get access token: %w", err)
 	}
 
+	return newCalendarClient(ctx, accessToken, s.apiURL)
+}
+
+// newCalendarClient creates a Google Calendar API client authorized with an access token
+func newCalendarClient(ctx context.Context, accessToken, apiURL string) (*calendar.Service, error) {
 	// Create OAuth2 token source
 	token := &oauth2.Token{
 		AccessToken: accessToken,
@@ -58,8 +95,13 @@ func (s *CalendarService) getCalendarService(ctx context.Context, userID int64)
 	}
 	tokenSource := oauth2.StaticTokenSource(token)
 
+	opts := []option.ClientOption{option.WithTokenSource(tokenSource)}
+	if apiURL != "" {
+		opts = append(opts, option.WithEndpoint(apiURL))
+	}
+
 	// Create calendar service
-	calendarService, err := calendar.NewService(ctx, option.WithTokenSource(tokenSource))
+	calendarService, err := calendar.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create calendar service: %w", err)
 	}
@@ -87,40 +129,47 @@ func (s *CalendarService) GetCalendarEvents(ctx context.Context, userID int64, s
 
 	result := make([]*CalendarEvent, 0, len(events.Items))
 	for _, item := range events.Items {
-		event := &CalendarEvent{
-			ID:          item.Id,
-			Summary:     item.Summary,
-			Description: item.Description,
-			Location:    item.Location,
-			HtmlLink:    item.HtmlLink,
-		}
+		result = append(result, toCalendarEvent(item))
+	}
 
-		// Parse start time
-		if item.Start != nil {
-			if item.Start.DateTime != "" {
-				t, _ := time.Parse(time.RFC3339, item.Start.DateTime)
-				event.StartTime = t
-			} else if item.Start.Date != "" {
-				t, _ := time.Parse("2006-01-02", item.Start.Date)
-				event.StartTime = t
-			}
-		}
+	return result, nil
+}
 
-		// Parse end time
-		if item.End != nil {
-			if item.End.DateTime != "" {
-				t, _ := time.Parse(time.RFC3339, item.End.DateTime)
-				event.EndTime = t
-			} else if item.End.Date != "" {
-				t, _ := time.Parse("2006-01-02", item.End.Date)
-				event.EndTime = t
-			}
-		}
+// GetBusyBlocks retrieves busy periods from user's primary Google Calendar
+func (s *CalendarService) GetBusyBlocks(ctx context.Context, userID int64, startTime, endTime time.Time) ([]models.TimeWindow, error) {
+	calendarService, err := s.getCalendarService(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
 
-		result = append(result, event)
+	resp, err := calendarService.Freebusy.Query(&calendar.FreeBusyRequest{
+		TimeMin: startTime.Format(time.RFC3339),
+		TimeMax: endTime.Format(time.RFC3339),
+		Items:   []*calendar.FreeBusyRequestItem{{Id: "primary"}},
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query free/busy: %w", err)
 	}
 
-	return result, nil
+	primary, ok := resp.Calendars["primary"]
+	if !ok {
+		return []models.TimeWindow{}, nil
+	}
+
+	busy := make([]models.TimeWindow, 0, len(primary.Busy))
+	for _, period := range primary.Busy {
+		start, err := time.Parse(time.RFC3339, period.Start)
+		if err != nil {
+			continue
+		}
+		end, err := time.Parse(time.RFC3339, period.End)
+		if err != nil {
+			continue
+		}
+		busy = append(busy, models.TimeWindow{Start: start, End: end})
+	}
+
+	return busy, nil
 }
 
 // CreateCalendarEvent creates a new event in user's Google Calendar
@@ -130,56 +179,370 @@ func (s *CalendarService) CreateCalendarEvent(ctx context.Context, userID int64,
 		return nil, err
 	}
 
-	description := ""
-	if event.Description != nil {
-		description = *event.Description
+	createdEvent, err := insertGoogleEvent(ctx, calendarService, primaryCalendarID, event, s.userZone(userID, event))
+	if err != nil {
+		return nil, err
 	}
 
-	location := ""
-	if event.Location != nil {
-		location = *event.Location
+	return toCalendarEvent(createdEvent), nil
+}
+
+// UpdateCalendarEvent updates an existing event in user's Google Calendar
+func (s *CalendarService) UpdateCalendarEvent(ctx context.Context, userID int64, calendarEventID string, event *models.Event) (*CalendarEvent, error) {
+	calendarService, err := s.getCalendarService(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	calEvent := &calendar.Event{
-		Summary:     event.Title,
-		Description: description,
-		Location:    location,
-		Start: &calendar.EventDateTime{
-			DateTime: event.StartTime.Format(time.RFC3339),
-			TimeZone: "Asia/Seoul",
-		},
-		End: &calendar.EventDateTime{
-			DateTime: event.EndTime.Format(time.RFC3339),
-			TimeZone: "Asia/Seoul",
-		},
+	updatedEvent, err := updateGoogleEvent(ctx, calendarService, primaryCalendarID, calendarEventID, event, s.userZone(userID, event))
+	if err != nil {
+		return nil, err
 	}
 
-	createdEvent, err := calendarService.Events.Insert("primary", calEvent).Do()
+	return toCalendarEvent(updatedEvent), nil
+}
+
+// DeleteCalendarEvent deletes an event from user's Google Calendar
+func (s *CalendarService) DeleteCalendarEvent(ctx context.Context, userID int64, calendarEventID string) error {
+	calendarService, err := s.getCalendarService(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create calendar event: %w", err)
+		return err
 	}
 
-	startTime, _ := time.Parse(time.RFC3339, createdEvent.Start.DateTime)
-	endTime, _ := time.Parse(time.RFC3339, createdEvent.End.DateTime)
+	return deleteGoogleEvent(ctx, calendarService, primaryCalendarID, calendarEventID)
+}
 
-	return &CalendarEvent{
-		ID:          createdEvent.Id,
-		Summary:     createdEvent.Summary,
-		Description: createdEvent.Description,
-		Location:    createdEvent.Location,
-		StartTime:   startTime,
-		EndTime:     endTime,
-		HtmlLink:    createdEvent.HtmlLink,
-	}, nil
+// SyncEventToCalendar syncs a timingle event to user's Google Calendar
+// Every member gets their own copy, tracked per user in calendar_event_mappings.
+// A copy the user deleted in Google is created again.
+func (s *CalendarService) SyncEventToCalendar(ctx context.Context, userID int64, eventID int64) (*CalendarEvent, error) {
+	// Get the timingle event
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find event: %w", err)
+	}
+	if event == nil {
+		return nil, fmt.Errorf("event not found")
+	}
+
+	if event.CreatorID != userID {
+		isParticipant, err := s.eventRepo.IsUserParticipant(eventID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !isParticipant {
+			return nil, fmt.Errorf("only event members can sync event")
+		}
+	}
+
+	calendarService, err := s.getCalendarService(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, err := s.syncRepo.FindMapping(eventID, userID, models.OAuthProviderGoogle)
+	if err != nil {
+		return nil, err
+	}
+	if mapping == nil {
+		mapping = &models.CalendarEventMapping{
+			EventID:    eventID,
+			UserID:     userID,
+			Provider:   models.OAuthProviderGoogle,
+			CalendarID: primaryCalendarID,
+		}
+	}
+
+	item, err := s.writeCopy(ctx, calendarService, mapping, event)
+	if isGoogleEventGone(err) {
+		mapping.ExternalEventID = ""
+		item, err = s.writeCopy(ctx, calendarService, mapping, event)
+	}
+	if err != nil {
+		s.recordSyncFailure(mapping, err)
+		return nil, err
+	}
+
+	return toCalendarEvent(item), nil
 }
 
-// UpdateCalendarEvent updates an existing event in user's Google Calendar
-func (s *CalendarService) UpdateCalendarEvent(ctx context.Context, userID int64, calendarEventID string, event *models.Event) (*CalendarEvent, error) {
+// EnqueueEventChange queues a job for every member's copy of an event after the event changed
+// The worker updates the copies, or deletes them when the event was canceled.
+func (s *CalendarService) EnqueueEventChange(eventID int64) {
+	mappings, err := s.syncRepo.FindMappingsByEventID(eventID)
+	if err != nil {
+		fmt.Printf("Warning: failed to find calendar copies of event %d: %v\n", eventID, err)
+		return
+	}
+
+	for _, mapping := range mappings {
+		job := &models.CalendarSyncJob{
+			Kind:    models.CalendarSyncJobUpdateCopy,
+			UserID:  mapping.UserID,
+			EventID: eventID,
+		}
+		if err := s.enqueue(job); err != nil {
+			// Log error but continue: the periodic reconciliation catches the copy up
+			fmt.Printf("Warning: failed to queue calendar update of event %d for user %d: %v\n", eventID, mapping.UserID, err)
+		}
+	}
+}
+
+// FindEventCopies returns every member's copy of an event
+// Load them before deleting the event, as its mappings are deleted with it.
+func (s *CalendarService) FindEventCopies(eventID int64) ([]*models.CalendarEventMapping, error) {
+	return s.syncRepo.FindMappingsByEventID(eventID)
+}
+
+// EnqueueCopyDeletions queues a job deleting each given copy of a deleted event from its member's calendar
+func (s *CalendarService) EnqueueCopyDeletions(mappings []*models.CalendarEventMapping) {
+	for _, mapping := range mappings {
+		job := &models.CalendarSyncJob{
+			Kind:            models.CalendarSyncJobDeleteCopy,
+			UserID:          mapping.UserID,
+			EventID:         mapping.EventID,
+			CalendarID:      mapping.CalendarID,
+			ExternalEventID: mapping.ExternalEventID,
+		}
+		if err := s.enqueue(job); err != nil {
+			// Log error but continue
+			fmt.Printf("Warning: failed to queue calendar deletion of event %d for user %d: %v\n", mapping.EventID, mapping.UserID, err)
+		}
+	}
+}
+
+// RemoveUserCopies deletes all of a user's copies from their calendar and forgets them
+// It runs synchronously because it is used right before the user's Google access is revoked
+// (account deletion), after which queued jobs could no longer reach the calendar.
+// Every copy is tried; the last error is returned.
+func (s *CalendarService) RemoveUserCopies(ctx context.Context, userID int64) error {
+	mappings, err := s.syncRepo.FindMappingsByUserID(userID, models.OAuthProviderGoogle)
+	if err != nil {
+		return err
+	}
+	if len(mappings) == 0 {
+		return nil
+	}
+
 	calendarService, err := s.getCalendarService(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, mapping := range mappings {
+		if err := deleteGoogleEvent(ctx, calendarService, mapping.CalendarID, mapping.ExternalEventID); err != nil {
+			lastErr = err
+			continue
+		}
+		if err := s.syncRepo.DeleteMapping(mapping.ID); err != nil {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+// EnqueueReconciliation queues a reconciliation job for every user with a usable Calendar grant and returns how many were queued
+// Jobs are deduplicated per period, so several workers ticking in the same period queue each user once.
+func (s *CalendarService) EnqueueReconciliation(now time.Time, period time.Duration) (int, error) {
+	accounts, err := s.oauthRepo.FindAccountsWithCalendarScope()
+	if err != nil {
+		return 0, err
+	}
+
+	queued := 0
+	for _, account := range accounts {
+		if account.Provider != models.OAuthProviderGoogle || account.NeedsReconsent() {
+			continue
+		}
+
+		job := &models.CalendarSyncJob{Kind: models.CalendarSyncJobReconcile, UserID: account.UserID}
+		msgID := fmt.Sprintf("calendar-reconcile-%d-%d", account.UserID, now.Truncate(period).Unix())
+		if err := s.enqueue(job, nats.MsgId(msgID)); err != nil {
+			// Log error but continue
+			fmt.Printf("Warning: failed to queue calendar reconciliation for user %d: %v\n", account.UserID, err)
+			continue
+		}
+		queued++
+	}
+
+	return queued, nil
+}
+
+// enqueue publishes a calendar sync job to the CALENDAR_SYNC stream
+func (s *CalendarService) enqueue(job *models.CalendarSyncJob, opts ...nats.PubOpt) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal calendar sync job: %w", err)
+	}
+
+	if _, err := s.nats.Publish(CalendarSyncSubject, data, opts...); err != nil {
+		return fmt.Errorf("failed to publish calendar sync job: %w", err)
+	}
+
+	return nil
+}
+
+// updateCopy runs an UPDATE_COPY job: writes the event's current state to the member's copy
+func (s *CalendarService) updateCopy(ctx context.Context, job *models.CalendarSyncJob) error {
+	mapping, err := s.syncRepo.FindMapping(job.EventID, job.UserID, models.OAuthProviderGoogle)
+	if err != nil {
+		return err
+	}
+	if mapping == nil {
+		return nil // Unlinked or deleted with the event since the job was queued
+	}
+
+	event, err := s.eventRepo.FindByID(job.EventID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.propagate(ctx, mapping, event); err != nil {
+		s.recordSyncFailure(mapping, err)
+		return err
+	}
+
+	return nil
+}
+
+// deleteCopy runs a DELETE_COPY job: deletes a deleted event's copy from the member's calendar
+func (s *CalendarService) deleteCopy(ctx context.Context, job *models.CalendarSyncJob) error {
+	calendarService, err := s.getCalendarService(ctx, job.UserID)
+	if err != nil {
+		return err
+	}
+
+	return deleteGoogleEvent(ctx, calendarService, job.CalendarID, job.ExternalEventID)
+}
+
+// reconcile brings all of a user's copies up to date: copies that failed or are older than their
+// event are written again, and copies of events the user is no longer a member of are deleted
+// Every copy is tried; the last error is returned so the job is retried.
+func (s *CalendarService) reconcile(ctx context.Context, userID int64) error {
+	mappings, err := s.syncRepo.FindMappingsByUserID(userID, models.OAuthProviderGoogle)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, mapping := range mappings {
+		event, err := s.eventRepo.FindByID(mapping.EventID)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		isMember := event.CreatorID == userID
+		if !isMember {
+			if isMember, err = s.eventRepo.IsUserParticipant(event.ID, userID); err != nil {
+				lastErr = err
+				continue
+			}
+		}
+
+		if !isMember {
+			err = s.removeCopy(ctx, mapping)
+		} else if mapping.SyncState == models.CalendarSyncStateFailed || mapping.LastSyncedAt == nil || event.UpdatedAt.After(*mapping.LastSyncedAt) {
+			if err = s.propagate(ctx, mapping, event); err != nil {
+				s.recordSyncFailure(mapping, err)
+			}
+		}
+
+		if errors.Is(err, ErrCalendarReconsentRequired) {
+			return err
+		}
+		if err != nil {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+// propagate writes an event change to one member's copy
+func (s *CalendarService) propagate(ctx context.Context, mapping *models.CalendarEventMapping, event *models.Event) error {
+	if event.Status == models.EventStatusCanceled {
+		return s.removeCopy(ctx, mapping)
+	}
+
+	calendarService, err := s.getCalendarService(ctx, mapping.UserID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.writeCopy(ctx, calendarService, mapping, event)
+	if isGoogleEventGone(err) {
+		// The user deleted the copy in Google, so they no longer want it
+		return s.syncRepo.DeleteMapping(mapping.ID)
+	}
+	return err
+}
+
+// removeCopy deletes a member's copy from their calendar and forgets it
+func (s *CalendarService) removeCopy(ctx context.Context, mapping *models.CalendarEventMapping) error {
+	calendarService, err := s.getCalendarService(ctx, mapping.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := deleteGoogleEvent(ctx, calendarService, mapping.CalendarID, mapping.ExternalEventID); err != nil {
+		return err
+	}
+	return s.syncRepo.DeleteMapping(mapping.ID)
+}
+
+// writeCopy creates or updates a member's copy of an event and records it as synced
+func (s *CalendarService) writeCopy(ctx context.Context, calendarService *calendar.Service, mapping *models.CalendarEventMapping, event *models.Event) (*calendar.Event, error) {
+	loc := s.userZone(mapping.UserID, event)
+
+	var item *calendar.Event
+	var err error
+	if mapping.ExternalEventID == "" {
+		item, err = insertGoogleEvent(ctx, calendarService, mapping.CalendarID, event, loc)
+	} else {
+		item, err = updateGoogleEvent(ctx, calendarService, mapping.CalendarID, mapping.ExternalEventID, event, loc)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	mapping.ExternalEventID = item.Id
+	mapping.ETag = &item.Etag
+	if err := s.syncRepo.SaveSyncedMapping(mapping); err != nil {
+		// Log but don't fail - the copy was written
+		fmt.Printf("Warning: failed to save calendar mapping of event %d: %v\n", mapping.EventID, err)
+	}
+
+	return item, nil
+}
+
+// recordSyncFailure marks a stored copy as failed; copies that were never written are not recorded
+func (s *CalendarService) recordSyncFailure(mapping *models.CalendarEventMapping, syncErr error) {
+	if mapping.ID == 0 {
+		return
+	}
+	if err := s.syncRepo.MarkMappingFailed(mapping.ID, syncErr.Error()); err != nil {
+		fmt.Printf("Warning: failed to record calendar sync error of event %d: %v\n", mapping.EventID, err)
+	}
+}
+
+// userZone returns the timezone a user's copy of an event is written in: the user's
+// profile timezone, or the event's zone when the user has none
+func (s *CalendarService) userZone(userID int64, event *models.Event) *time.Location {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || validateTimezone(user.Timezone) != nil {
+		return event.Zone()
+	}
+	return models.LoadZone(user.Timezone)
+}
+
+// newGoogleEvent converts a timingle event to the Google Calendar event written to members' calendars
+// Timed events are written in loc (the calendar owner's zone); all-day events as dates.
+// Recurring events carry their RRULE and EXDATEs and are written in the event's zone,
+// which the recurrences keep their wall-clock time in.
+func newGoogleEvent(event *models.Event, loc *time.Location) (*calendar.Event, error) {
 	description := ""
 	if event.Description != nil {
 		description = *event.Description
@@ -190,86 +553,169 @@ func (s *CalendarService) UpdateCalendarEvent(ctx context.Context, userID int64,
 		location = *event.Location
 	}
 
-	calEvent := &calendar.Event{
+	item := &calendar.Event{
 		Summary:     event.Title,
 		Description: description,
 		Location:    location,
-		Start: &calendar.EventDateTime{
-			DateTime: event.StartTime.Format(time.RFC3339),
-			TimeZone: "Asia/Seoul",
-		},
-		End: &calendar.EventDateTime{
-			DateTime: event.EndTime.Format(time.RFC3339),
-			TimeZone: "Asia/Seoul",
-		},
 	}
 
-	updatedEvent, err := calendarService.Events.Update("primary", calendarEventID, calEvent).Do()
+	if event.IsRecurring() {
+		recurrence, err := googleRecurrence(event)
+		if err != nil {
+			return nil, err
+		}
+		item.Recurrence = recurrence
+		loc = event.Zone()
+	}
+
+	if event.AllDay {
+		item.Start = &calendar.EventDateTime{Date: event.StartTime.UTC().Format(dateLayout)}
+		item.End = &calendar.EventDateTime{Date: event.EndTime.UTC().Format(dateLayout)}
+		return item, nil
+	}
+
+	item.Start = &calendar.EventDateTime{
+		DateTime: event.StartTime.In(loc).Format(time.RFC3339),
+		TimeZone: loc.String(),
+	}
+	item.End = &calendar.EventDateTime{
+		DateTime: event.EndTime.In(loc).Format(time.RFC3339),
+		TimeZone: loc.String(),
+	}
+	return item, nil
+}
+
+// googleRecurrence returns the RRULE and EXDATE lines of a recurring event's Google copy
+// EXDATEs use the form of the start: dates for all-day events, local times in the event's zone otherwise
+func googleRecurrence(event *models.Event) ([]string, error) {
+	rule, err := icalRecurrenceRule(event)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update calendar event: %w", err)
+		return nil, err
+	}
+
+	recurrence := []string{"RRULE:" + rule}
+	if len(event.RecurrenceExDates) == 0 {
+		return recurrence, nil
+	}
+
+	values := make([]string, len(event.RecurrenceExDates))
+	for i, exdate := range event.RecurrenceExDates {
+		if event.AllDay {
+			values[i] = exdate.UTC().Format(recurrenceDateLayout)
+		} else {
+			values[i] = exdate.In(event.Zone()).Format(recurrenceDateTimeLayout)
+		}
+	}
+
+	params := ";TZID=" + event.Zone().String()
+	if event.AllDay {
+		params = ";VALUE=DATE"
 	}
+	return append(recurrence, "EXDATE"+params+":"+strings.Join(values, ",")), nil
+}
 
-	startTime, _ := time.Parse(time.RFC3339, updatedEvent.Start.DateTime)
-	endTime, _ := time.Parse(time.RFC3339, updatedEvent.End.DateTime)
+// toCalendarEvent converts a Google Calendar event for API responses
+func toCalendarEvent(item *calendar.Event) *CalendarEvent {
+	startTime, allDay := parseCalendarEventTime(item.Start)
+	endTime, _ := parseCalendarEventTime(item.End)
 
 	return &CalendarEvent{
-		ID:          updatedEvent.Id,
-		Summary:     updatedEvent.Summary,
-		Description: updatedEvent.Description,
-		Location:    updatedEvent.Location,
+		ID:          item.Id,
+		Summary:     item.Summary,
+		Description: item.Description,
+		Location:    item.Location,
 		StartTime:   startTime,
 		EndTime:     endTime,
-		HtmlLink:    updatedEvent.HtmlLink,
-	}, nil
+		AllDay:      allDay,
+		HtmlLink:    item.HtmlLink,
+	}
 }
 
-// DeleteCalendarEvent deletes an event from user's Google Calendar
-func (s *CalendarService) DeleteCalendarEvent(ctx context.Context, userID int64, calendarEventID string) error {
-	calendarService, err := s.getCalendarService(ctx, userID)
-	if err != nil {
-		return err
+// parseCalendarEventTime parses a Google event start or end; all-day values are dates at UTC midnight
+func parseCalendarEventTime(value *calendar.EventDateTime) (time.Time, bool) {
+	if value == nil {
+		return time.Time{}, false
 	}
+	if value.DateTime != "" {
+		t, _ := time.Parse(time.RFC3339, value.DateTime)
+		return t, false
+	}
+	t, _ := time.Parse(dateLayout, value.Date)
+	return t, value.Date != ""
+}
 
-	err = calendarService.Events.Delete("primary", calendarEventID).Do()
+// insertGoogleEvent creates a copy of an event in a calendar
+func insertGoogleEvent(ctx context.Context, calendarService *calendar.Service, calendarID string, event *models.Event, loc *time.Location) (*calendar.Event, error) {
+	item, err := newGoogleEvent(event, loc)
 	if err != nil {
-		return fmt.Errorf("failed to delete calendar event: %w", err)
+		return nil, err
 	}
 
-	return nil
+	createdEvent, err := calendarService.Events.Insert(calendarID, item).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create calendar event: %w", err)
+	}
+	return createdEvent, nil
 }
 
-// SyncEventToCalendar syncs a timingle event to user's Google Calendar
-// Returns the Google Calendar event ID
-func (s *CalendarService) SyncEventToCalendar(ctx context.Context, userID int64, eventID int64) (*CalendarEvent, error) {
-	// Get the timingle event
-	event, err := s.eventRepo.FindByID(eventID)
+// updateGoogleEvent overwrites a copy of an event in a calendar
+func updateGoogleEvent(ctx context.Context, calendarService *calendar.Service, calendarID, calendarEventID string, event *models.Event, loc *time.Location) (*calendar.Event, error) {
+	item, err := newGoogleEvent(event, loc)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find event: %w", err)
+		return nil, err
 	}
-	if event == nil {
-		return nil, fmt.Errorf("event not found")
+
+	updatedEvent, err := calendarService.Events.Update(calendarID, calendarEventID, item).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to update calendar event: %w", err)
 	}
+	return updatedEvent, nil
+}
 
-	// Check if event already has a Google Calendar ID
-	if event.GoogleCalendarID != nil && *event.GoogleCalendarID != "" {
-		// Update existing calendar event
-		return s.UpdateCalendarEvent(ctx, userID, *event.GoogleCalendarID, event)
+// deleteGoogleEvent deletes a copy of an event from a calendar; a copy that is already gone is not an error
+func deleteGoogleEvent(ctx context.Context, calendarService *calendar.Service, calendarID, calendarEventID string) error {
+	err := calendarService.Events.Delete(calendarID, calendarEventID).Context(ctx).Do()
+	if err != nil && !isGoogleEventGone(err) {
+		return fmt.Errorf("failed to delete calendar event: %w", err)
 	}
+	return nil
+}
+
+// isGoogleEventGone reports whether a Calendar API error means the event no longer exists
+// Google answers 410 Gone for deleted events and 404 for unknown ones.
+func isGoogleEventGone(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && (apiErr.Code == http.StatusGone || apiErr.Code == http.StatusNotFound)
+}
 
-	// Create new calendar event
-	calEvent, err := s.CreateCalendarEvent(ctx, userID, event)
+// GetSyncStatus returns a user's calendar access, push channel and copy sync state
+func (s *CalendarService) GetSyncStatus(userID int64) (*models.CalendarSyncStatus, error) {
+	status := &models.CalendarSyncStatus{}
+
+	oauthAccount, err := s.oauthRepo.FindByUserIDAndProvider(userID, models.OAuthProviderGoogle)
 	if err != nil {
 		return nil, err
 	}
+	if oauthAccount != nil {
+		status.HasCalendarAccess = oauthAccount.HasCalendarScope() && !oauthAccount.NeedsReconsent()
+		status.NeedsReconsent = oauthAccount.NeedsReconsent()
+	}
 
-	// Save the Google Calendar ID to the event
-	err = s.eventRepo.UpdateGoogleCalendarID(eventID, calEvent.ID)
+	channel, err := s.syncRepo.FindByUserID(userID, models.OAuthProviderGoogle)
 	if err != nil {
-		// Log but don't fail - the event was created successfully
-		fmt.Printf("Warning: failed to save Google Calendar ID: %v\n", err)
+		return nil, err
+	}
+	if channel != nil {
+		status.WatchEnabled = true
+		status.WatchExpiresAt = &channel.ExpiresAt
+		status.LastPulledAt = channel.LastSyncedAt
+	}
+
+	if err := s.syncRepo.SummarizeMappings(userID, models.OAuthProviderGoogle, status); err != nil {
+		return nil, err
 	}
 
-	return calEvent, nil
+	return status, nil
 }
 
 // HasCalendarAccess checks if a user has Google Calendar access
@@ -281,5 +727,5 @@ func (s *CalendarService) HasCalendarAccess(ctx context.Context, userID int64) (
 	if oauthAccount == nil {
 		return false, nil
 	}
-	return oauthAccount.HasCalendarScope(), nil
+	return oauthAccount.HasCalendarScope() && !oauthAccount.NeedsReconsent(), nil
 }
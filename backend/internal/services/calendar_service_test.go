@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+func TestNewGoogleEvent(t *testing.T) {
+	description := "분기별 모임"
+	event := &models.Event{
+		Title:       "팀 저녁",
+		Description: &description,
+		StartTime:   time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+		EndTime:     time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC),
+	}
+
+	seoul, _ := time.LoadLocation("Asia/Seoul")
+	item, err := newGoogleEvent(event, seoul)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if item.Summary != "팀 저녁" || item.Description != description || item.Location != "" {
+		t.Errorf("Unexpected copy %+v", item)
+	}
+	if item.Start.DateTime != "2026-03-01T18:00:00+09:00" || item.End.DateTime != "2026-03-01T20:00:00+09:00" {
+		t.Errorf("Expected RFC3339 times in the owner's zone, got %s - %s", item.Start.DateTime, item.End.DateTime)
+	}
+	if item.Start.TimeZone != "Asia/Seoul" || item.End.TimeZone != "Asia/Seoul" {
+		t.Errorf("Expected the owner's zone, got %s - %s", item.Start.TimeZone, item.End.TimeZone)
+	}
+}
+
+func TestNewGoogleEventRecurring(t *testing.T) {
+	rule := "FREQ=WEEKLY;BYDAY=MO"
+	event := &models.Event{
+		Title:             "주간 회의",
+		StartTime:         time.Date(2025, 3, 3, 14, 0, 0, 0, time.UTC), // 09:00 EST
+		EndTime:           time.Date(2025, 3, 3, 15, 0, 0, 0, time.UTC),
+		Timezone:          "America/New_York",
+		RecurrenceRule:    &rule,
+		RecurrenceExDates: []time.Time{time.Date(2025, 3, 17, 13, 0, 0, 0, time.UTC)}, // 09:00 EDT
+	}
+
+	// The series is written in the event's zone, not the owner's
+	seoul, _ := time.LoadLocation("Asia/Seoul")
+	item, err := newGoogleEvent(event, seoul)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if item.Start.DateTime != "2025-03-03T09:00:00-05:00" || item.Start.TimeZone != "America/New_York" {
+		t.Errorf("Expected the start in the event's zone, got %s (%s)", item.Start.DateTime, item.Start.TimeZone)
+	}
+	expected := []string{"RRULE:FREQ=WEEKLY;BYDAY=MO", "EXDATE;TZID=America/New_York:20250317T090000"}
+	if len(item.Recurrence) != len(expected) {
+		t.Fatalf("Expected recurrence %v, got %v", expected, item.Recurrence)
+	}
+	for i := range expected {
+		if item.Recurrence[i] != expected[i] {
+			t.Errorf("Expected %s, got %s", expected[i], item.Recurrence[i])
+		}
+	}
+}
+
+func TestNewGoogleEventAllDay(t *testing.T) {
+	event := &models.Event{
+		Title:     "워크숍",
+		StartTime: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+		AllDay:    true,
+	}
+
+	// The dates are the same in every zone
+	losAngeles, _ := time.LoadLocation("America/Los_Angeles")
+	item, err := newGoogleEvent(event, losAngeles)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if item.Start.Date != "2026-03-01" || item.End.Date != "2026-03-03" {
+		t.Errorf("Expected dates 2026-03-01 - 2026-03-03, got %s - %s", item.Start.Date, item.End.Date)
+	}
+	if item.Start.DateTime != "" || item.Start.TimeZone != "" {
+		t.Errorf("Expected a date-only start, got %+v", item.Start)
+	}
+
+	copied := toCalendarEvent(item)
+	if !copied.AllDay || !copied.StartTime.Equal(event.StartTime) || !copied.EndTime.Equal(event.EndTime) {
+		t.Errorf("Expected the all-day dates back, got %+v", copied)
+	}
+}
+
+func TestUpdateGoogleEventWritesCopy(t *testing.T) {
+	var received calendar.Event
+	client := newTestCalendarClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/calendars/primary/events/copy-1" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		received.Id = "copy-1"
+		received.Etag = `"3181161784712000"`
+		json.NewEncoder(w).Encode(&received)
+	})
+
+	event := &models.Event{
+		Title:     "팀 저녁",
+		StartTime: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC),
+	}
+	item, err := updateGoogleEvent(context.Background(), client, "primary", "copy-1", event, time.UTC)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if received.Summary != "팀 저녁" {
+		t.Errorf("Expected the event to be written, got %q", received.Summary)
+	}
+	if item.Etag != `"3181161784712000"` {
+		t.Errorf("Expected the ETag of the written copy, got %q", item.Etag)
+	}
+}
+
+func TestGoogleEventGone(t *testing.T) {
+	for status, gone := range map[int]bool{
+		http.StatusGone:                true,
+		http.StatusNotFound:            true,
+		http.StatusForbidden:           false,
+		http.StatusInternalServerError: false,
+	} {
+		client := newTestCalendarClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			w.Write([]byte(`{"error":{"message":"` + http.StatusText(status) + `"}}`))
+		})
+
+		_, err := updateGoogleEvent(context.Background(), client, "primary", "copy-1", &models.Event{}, time.UTC)
+		if isGoogleEventGone(err) != gone {
+			t.Errorf("Status %d: expected gone=%v, got error %v", status, gone, err)
+		}
+
+		// Deleting a copy that is already gone succeeds
+		err = deleteGoogleEvent(context.Background(), client, "primary", "copy-1")
+		if (err == nil) != gone {
+			t.Errorf("Status %d: expected delete error only when not gone, got %v", status, err)
+		}
+	}
+}
@@ -0,0 +1,470 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/repositories"
+	"github.com/khchoi-tnh/timingle/pkg/utils"
+)
+
+// primaryCalendarID is the calendar timingle events are synced to
+const primaryCalendarID = "primary"
+
+var (
+	// ErrUnknownCalendarChannel is returned for notifications of channels that are not (or no longer) registered
+	ErrUnknownCalendarChannel = errors.New("calendar channel not found")
+	// errSyncTokenExpired is returned when Google invalidated a sync token (410 Gone) and a full sync is needed
+	errSyncTokenExpired = errors.New("sync token expired")
+)
+
+// CalendarSyncPolicy holds Google Calendar push channel settings
+type CalendarSyncPolicy struct {
+	WatchTTL    time.Duration // Requested channel lifetime (Google caps it, about a week for events)
+	RenewWithin time.Duration // Channels expiring within this window are renewed
+	WebhookURL  string        // HTTPS address Google posts notifications to
+}
+
+// CalendarSyncService keeps timingle events and their Google Calendar copies in sync in both directions
+// timingle → Google is SyncEventToCalendar; Google → timingle uses push channels (events.watch)
+// and incremental sync tokens, applying only time and location edits (see googleEventUpdate).
+type CalendarSyncService struct {
+	calendarService *CalendarService
+	eventService    *EventService
+	eventRepo       *repositories.EventRepository
+	syncRepo        *repositories.CalendarSyncRepository
+	policy          CalendarSyncPolicy
+}
+
+// NewCalendarSyncService creates a new calendar sync service
+func NewCalendarSyncService(
+	calendarService *CalendarService,
+	eventService *EventService,
+	eventRepo *repositories.EventRepository,
+	syncRepo *repositories.CalendarSyncRepository,
+	policy CalendarSyncPolicy,
+) *CalendarSyncService {
+	return &CalendarSyncService{
+		calendarService: calendarService,
+		eventService:    eventService,
+		eventRepo:       eventRepo,
+		syncRepo:        syncRepo,
+		policy:          policy,
+	}
+}
+
+// Watch registers (or re-registers) a push channel for the user's primary calendar
+// The first registration runs a full sync to obtain a sync token; renewals keep the existing one.
+func (s *CalendarSyncService) Watch(ctx context.Context, userID int64) (*models.CalendarSyncChannel, error) {
+	client, err := s.calendarService.getCalendarService(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.syncRepo.FindByUserID(userID, models.OAuthProviderGoogle)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateRandomString(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate channel token: %w", err)
+	}
+
+	channel := &models.CalendarSyncChannel{
+		UserID:     userID,
+		Provider:   models.OAuthProviderGoogle,
+		CalendarID: primaryCalendarID,
+		ChannelID:  uuid.NewString(),
+		Token:      token,
+		ExpiresAt:  time.Now().Add(s.policy.WatchTTL),
+	}
+
+	registered, err := watchCalendar(ctx, client, channel, s.policy.WebhookURL)
+	if err != nil {
+		return nil, err
+	}
+	channel.ResourceID = &registered.ResourceId
+	if registered.Expiration > 0 {
+		channel.ExpiresAt = time.UnixMilli(registered.Expiration)
+	}
+
+	if err := s.syncRepo.UpsertChannel(channel); err != nil {
+		return nil, err
+	}
+
+	// Notifications of the replaced channel would no longer match, so stop them
+	if previous != nil {
+		if err := stopCalendarChannel(ctx, client, previous); err != nil {
+			// Log error but continue
+			fmt.Printf("Warning: failed to stop calendar channel %s: %v\n", previous.ChannelID, err)
+		}
+	}
+
+	if channel.SyncToken == nil {
+		if err := s.enqueuePull(userID); err != nil {
+			// Log error but continue: the next notification or reconciliation runs the full sync
+			fmt.Printf("Warning: failed to queue initial calendar sync of user %d: %v\n", userID, err)
+		}
+	}
+
+	return channel, nil
+}
+
+// Unwatch stops the user's push channel; timingle events are still pushed to Google on sync
+func (s *CalendarSyncService) Unwatch(ctx context.Context, userID int64) error {
+	channel, err := s.syncRepo.FindByUserID(userID, models.OAuthProviderGoogle)
+	if err != nil {
+		return err
+	}
+	if channel == nil {
+		return fmt.Errorf("calendar sync is not enabled")
+	}
+
+	return s.stop(ctx, channel)
+}
+
+// StopChannel stops the user's push channel if there is one
+// Used before the user's Google access goes away (account deletion)
+func (s *CalendarSyncService) StopChannel(ctx context.Context, userID int64) error {
+	channel, err := s.syncRepo.FindByUserID(userID, models.OAuthProviderGoogle)
+	if err != nil {
+		return err
+	}
+	if channel == nil {
+		return nil
+	}
+
+	return s.stop(ctx, channel)
+}
+
+// StopMergedChannel stops the source user's push channel when an account merge cannot move it
+// The channel follows the Google account to the target (UserRepository.Merge) unless the target already has one.
+func (s *CalendarSyncService) StopMergedChannel(ctx context.Context, sourceID, targetID int64) error {
+	target, err := s.syncRepo.FindByUserID(targetID, models.OAuthProviderGoogle)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return nil
+	}
+
+	return s.StopChannel(ctx, sourceID)
+}
+
+// stop stops a push channel at Google and deletes it
+func (s *CalendarSyncService) stop(ctx context.Context, channel *models.CalendarSyncChannel) error {
+	if client, err := s.calendarService.getCalendarService(ctx, channel.UserID); err == nil {
+		if err := stopCalendarChannel(ctx, client, channel); err != nil {
+			// Log error but continue: the channel expires on its own
+			fmt.Printf("Warning: failed to stop calendar channel %s: %v\n", channel.ChannelID, err)
+		}
+	}
+
+	return s.syncRepo.Delete(channel.UserID, channel.Provider)
+}
+
+// HandleNotification processes a push notification from Google Calendar
+// Notifications only say that something changed; a PULL job fetches the changes with the sync token.
+func (s *CalendarSyncService) HandleNotification(notification *models.CalendarNotification) error {
+	channel, err := s.syncRepo.FindByChannelID(notification.ChannelID)
+	if err != nil {
+		return ErrUnknownCalendarChannel
+	}
+	if subtle.ConstantTimeCompare([]byte(notification.Token), []byte(channel.Token)) != 1 {
+		return ErrUnknownCalendarChannel
+	}
+
+	// Google confirms a new channel with a "sync" message before any change
+	if notification.ResourceState == "sync" {
+		return nil
+	}
+
+	return s.enqueuePull(channel.UserID)
+}
+
+// SyncUser pulls the changes of a user's calendar and applies them; nothing is pulled without a channel
+func (s *CalendarSyncService) SyncUser(ctx context.Context, userID int64) (*models.CalendarSyncResult, error) {
+	channel, err := s.syncRepo.FindByUserID(userID, models.OAuthProviderGoogle)
+	if err != nil {
+		return nil, err
+	}
+	if channel == nil {
+		return &models.CalendarSyncResult{}, nil
+	}
+
+	client, err := s.calendarService.getCalendarService(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.sync(ctx, client, channel)
+}
+
+// enqueuePull queues a PULL job for a user's calendar
+func (s *CalendarSyncService) enqueuePull(userID int64) error {
+	return s.calendarService.enqueue(&models.CalendarSyncJob{Kind: models.CalendarSyncJobPull, UserID: userID})
+}
+
+// RenewExpiring re-registers the channels expiring within the renewal window and returns how many were renewed
+func (s *CalendarSyncService) RenewExpiring(ctx context.Context, now time.Time) (int, error) {
+	channels, err := s.syncRepo.FindExpiringBefore(now.Add(s.policy.RenewWithin))
+	if err != nil {
+		return 0, err
+	}
+
+	renewed := 0
+	for _, channel := range channels {
+		if _, err := s.Watch(ctx, channel.UserID); err != nil {
+			// Log error but continue
+			fmt.Printf("Warning: failed to renew calendar channel of user %d: %v\n", channel.UserID, err)
+			continue
+		}
+		renewed++
+	}
+
+	return renewed, nil
+}
+
+// sync pulls the changes since the channel's sync token and applies them to timingle events
+func (s *CalendarSyncService) sync(ctx context.Context, client *calendar.Service, channel *models.CalendarSyncChannel) (*models.CalendarSyncResult, error) {
+	syncToken := ""
+	if channel.SyncToken != nil {
+		syncToken = *channel.SyncToken
+	}
+
+	items, nextSyncToken, err := listCalendarChanges(ctx, client, channel.CalendarID, syncToken)
+	if errors.Is(err, errSyncTokenExpired) {
+		items, nextSyncToken, err = listCalendarChanges(ctx, client, channel.CalendarID, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.applyChanges(channel.UserID, items)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.syncRepo.UpdateSyncToken(channel.ID, &nextSyncToken, time.Now()); err != nil {
+		return nil, err
+	}
+	channel.SyncToken = &nextSyncToken
+
+	return result, nil
+}
+
+// applyChanges applies changed Google events of ownerID's calendar to the timingle events they are copies of
+func (s *CalendarSyncService) applyChanges(ownerID int64, items []*calendar.Event) (*models.CalendarSyncResult, error) {
+	result := &models.CalendarSyncResult{Changed: len(items)}
+
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.Id)
+	}
+	mappings, err := s.syncRepo.FindMappingsByExternalIDs(ownerID, models.OAuthProviderGoogle, ids)
+	if err != nil {
+		return nil, err
+	}
+	byExternalID := make(map[string]*models.CalendarEventMapping, len(mappings))
+	for _, mapping := range mappings {
+		byExternalID[mapping.ExternalEventID] = mapping
+	}
+
+	for _, item := range items {
+		mapping, ok := byExternalID[item.Id]
+		if !ok {
+			continue
+		}
+
+		// Notification of timingle's own write to the copy
+		if mapping.ETag != nil && *mapping.ETag == item.Etag {
+			continue
+		}
+
+		// Deleting a copy does not cancel an event other people take part in
+		if item.Status == "cancelled" {
+			if err := s.syncRepo.DeleteMapping(mapping.ID); err != nil {
+				return nil, err
+			}
+			result.Unlinked++
+			continue
+		}
+
+		event, err := s.eventRepo.FindByID(mapping.EventID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Only the creator can change an event; other members' edits stay in their own copy
+		if event.CreatorID != ownerID {
+			continue
+		}
+
+		req := googleEventUpdate(event, item)
+		if req == nil {
+			continue
+		}
+		if _, err := s.eventService.UpdateEvent(event.ID, ownerID, req); err != nil {
+			// Log error but continue: an invalid edit must not block the rest of the calendar
+			fmt.Printf("Warning: failed to apply Google Calendar change to event %d: %v\n", event.ID, err)
+			continue
+		}
+		result.Applied++
+	}
+
+	return result, nil
+}
+
+// googleEventUpdate returns the update a changed Google copy makes to its timingle event, nil to ignore it
+// Conflict rules:
+//   - timingle is the source of truth for the title, description, participants and status;
+//     only start, end and location edits are taken from Google
+//   - the edit is ignored if the timingle event changed after the Google copy (the copy is
+//     overwritten on the next sync to Google)
+//   - canceled, done and recurring events are not changed from Google
+//   - a copy turned all-day (or an all-day copy turned timed) is ignored; all-day events take
+//     date changes only
+func googleEventUpdate(event *models.Event, item *calendar.Event) *models.UpdateEventRequest {
+	if event.Status == models.EventStatusCanceled || event.Status == models.EventStatusDone || event.IsRecurring() {
+		return nil
+	}
+
+	updated, err := time.Parse(time.RFC3339, item.Updated)
+	if err != nil || !updated.After(event.UpdatedAt) {
+		return nil
+	}
+
+	parse := parseGoogleDateTime
+	if event.AllDay {
+		parse = parseGoogleDate
+	}
+	start, ok := parse(item.Start)
+	if !ok {
+		return nil
+	}
+	end, ok := parse(item.End)
+	if !ok || end.Before(start) {
+		return nil
+	}
+
+	req := &models.UpdateEventRequest{}
+	changed := false
+	if !start.Equal(event.StartTime) {
+		req.StartTime = &start
+		changed = true
+	}
+	if !end.Equal(event.EndTime) {
+		req.EndTime = &end
+		changed = true
+	}
+
+	location := strings.TrimSpace(item.Location)
+	current := ""
+	if event.Location != nil {
+		current = *event.Location
+	}
+	if location != current {
+		req.Location = &location
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return req
+}
+
+// parseGoogleDateTime parses a timed Google event start or end; all-day (date-only) values are not ok
+func parseGoogleDateTime(value *calendar.EventDateTime) (time.Time, bool) {
+	if value == nil || value.DateTime == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, value.DateTime)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// parseGoogleDate parses an all-day Google event start or end as UTC midnight; timed values are not ok
+func parseGoogleDate(value *calendar.EventDateTime) (time.Time, bool) {
+	if value == nil || value.Date == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(dateLayout, value.Date)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// watchCalendar registers a web_hook channel for the events of a calendar
+func watchCalendar(ctx context.Context, client *calendar.Service, channel *models.CalendarSyncChannel, webhookURL string) (*calendar.Channel, error) {
+	registered, err := client.Events.Watch(channel.CalendarID, &calendar.Channel{
+		Id:         channel.ChannelID,
+		Type:       "web_hook",
+		Address:    webhookURL,
+		Token:      channel.Token,
+		Expiration: channel.ExpiresAt.UnixMilli(),
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch calendar: %w", err)
+	}
+	return registered, nil
+}
+
+// stopCalendarChannel stops notifications of a channel
+func stopCalendarChannel(ctx context.Context, client *calendar.Service, channel *models.CalendarSyncChannel) error {
+	resourceID := ""
+	if channel.ResourceID != nil {
+		resourceID = *channel.ResourceID
+	}
+
+	err := client.Channels.Stop(&calendar.Channel{Id: channel.ChannelID, ResourceId: resourceID}).Context(ctx).Do()
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+		return nil // Already expired
+	}
+	return err
+}
+
+// listCalendarChanges lists the events changed since syncToken (every event when empty), including
+// deleted ones, and returns them with the token to continue from
+// An invalidated token returns errSyncTokenExpired.
+func listCalendarChanges(ctx context.Context, client *calendar.Service, calendarID, syncToken string) ([]*calendar.Event, string, error) {
+	call := client.Events.List(calendarID).ShowDeleted(true).MaxResults(250)
+	if syncToken != "" {
+		call = call.SyncToken(syncToken)
+	}
+
+	items := []*calendar.Event{}
+	nextSyncToken := ""
+	err := call.Pages(ctx, func(page *calendar.Events) error {
+		items = append(items, page.Items...)
+		if page.NextSyncToken != "" {
+			nextSyncToken = page.NextSyncToken
+		}
+		return nil
+	})
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusGone {
+		return nil, "", errSyncTokenExpired
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list calendar changes: %w", err)
+	}
+
+	return items, nextSyncToken, nil
+}
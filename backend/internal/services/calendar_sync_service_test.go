@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+func newSyncedEvent() *models.Event {
+	location := "강남역"
+	return &models.Event{
+		ID:        1,
+		Title:     "저녁 약속",
+		StartTime: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC),
+		Location:  &location,
+		CreatorID: 7,
+		Status:    models.EventStatusConfirmed,
+		UpdatedAt: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func newGoogleCopy(start, end, location, updated string) *calendar.Event {
+	return &calendar.Event{
+		Id:       "g1",
+		Start:    &calendar.EventDateTime{DateTime: start},
+		End:      &calendar.EventDateTime{DateTime: end},
+		Location: location,
+		Updated:  updated,
+	}
+}
+
+func TestGoogleEventUpdateAppliesTimeAndLocation(t *testing.T) {
+	event := newSyncedEvent()
+	item := newGoogleCopy("2026-03-01T20:00:00+09:00", "2026-03-01T21:30:00+09:00", "홍대입구역", "2026-02-10T00:00:00Z")
+
+	req := googleEventUpdate(event, item)
+	if req == nil {
+		t.Fatal("Expected an update for a newer Google edit")
+	}
+	if req.StartTime == nil || !req.StartTime.Equal(time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected start 11:00 UTC, got %v", req.StartTime)
+	}
+	if req.EndTime == nil || !req.EndTime.Equal(time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected end 12:30 UTC, got %v", req.EndTime)
+	}
+	if req.Location == nil || *req.Location != "홍대입구역" {
+		t.Errorf("Expected the new location, got %v", req.Location)
+	}
+	if req.Title != nil || req.Description != nil {
+		t.Error("Expected the title and description to stay owned by timingle")
+	}
+}
+
+func TestGoogleEventUpdateOnlyChangedFields(t *testing.T) {
+	event := newSyncedEvent()
+	item := newGoogleCopy("2026-03-01T19:00:00+09:00", "2026-03-01T20:00:00+09:00", "홍대입구역", "2026-02-10T00:00:00Z")
+
+	req := googleEventUpdate(event, item)
+	if req == nil {
+		t.Fatal("Expected an update for the location")
+	}
+	if req.StartTime != nil || req.EndTime != nil {
+		t.Errorf("Expected unchanged times to be left out, got %v - %v", req.StartTime, req.EndTime)
+	}
+}
+
+func TestGoogleEventUpdateIgnored(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(event *models.Event, item *calendar.Event)
+	}{
+		{
+			name:   "no change (echo of our own sync)",
+			modify: func(event *models.Event, item *calendar.Event) {},
+		},
+		{
+			name: "timingle changed after Google",
+			modify: func(event *models.Event, item *calendar.Event) {
+				item.Start.DateTime = "2026-03-01T20:00:00+09:00"
+				item.End.DateTime = "2026-03-01T21:00:00+09:00"
+				event.UpdatedAt = time.Date(2026, 2, 20, 0, 0, 0, 0, time.UTC)
+			},
+		},
+		{
+			name: "canceled event",
+			modify: func(event *models.Event, item *calendar.Event) {
+				item.Location = "홍대입구역"
+				event.Status = models.EventStatusCanceled
+			},
+		},
+		{
+			name: "recurring event",
+			modify: func(event *models.Event, item *calendar.Event) {
+				item.Location = "홍대입구역"
+				rule := "FREQ=WEEKLY"
+				event.RecurrenceRule = &rule
+			},
+		},
+		{
+			name: "all-day edit",
+			modify: func(event *models.Event, item *calendar.Event) {
+				item.Start = &calendar.EventDateTime{Date: "2026-03-01"}
+				item.End = &calendar.EventDateTime{Date: "2026-03-02"}
+			},
+		},
+		{
+			name: "end before start",
+			modify: func(event *models.Event, item *calendar.Event) {
+				item.End.DateTime = "2026-03-01T18:00:00+09:00"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := newSyncedEvent()
+			item := newGoogleCopy("2026-03-01T19:00:00+09:00", "2026-03-01T20:00:00+09:00", "강남역", "2026-02-10T00:00:00Z")
+			tt.modify(event, item)
+
+			if req := googleEventUpdate(event, item); req != nil {
+				t.Errorf("Expected no update, got %+v", req)
+			}
+		})
+	}
+}
+
+func newTestCalendarClient(t *testing.T, handler http.HandlerFunc) *calendar.Service {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	client, err := newCalendarClient(context.Background(), "test-token", srv.URL+"/")
+	if err != nil {
+		t.Fatalf("Failed to create calendar client: %v", err)
+	}
+	return client
+}
+
+func TestListCalendarChangesFollowsPages(t *testing.T) {
+	var syncTokens []string
+	client := newTestCalendarClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/calendars/primary/events" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			t.Errorf("Expected the access token, got %q", r.Header.Get("Authorization"))
+		}
+		if r.URL.Query().Get("showDeleted") != "true" {
+			t.Error("Expected deleted events to be listed")
+		}
+		syncTokens = append(syncTokens, r.URL.Query().Get("syncToken"))
+
+		page := &calendar.Events{}
+		if r.URL.Query().Get("pageToken") == "" {
+			page.Items = []*calendar.Event{{Id: "a"}, {Id: "b"}}
+			page.NextPageToken = "page-2"
+		} else {
+			page.Items = []*calendar.Event{{Id: "c", Status: "cancelled"}}
+			page.NextSyncToken = "sync-2"
+		}
+		json.NewEncoder(w).Encode(page)
+	})
+
+	items, nextSyncToken, err := listCalendarChanges(context.Background(), client, "primary", "sync-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 3 || items[2].Id != "c" {
+		t.Errorf("Expected the items of both pages, got %d", len(items))
+	}
+	if nextSyncToken != "sync-2" {
+		t.Errorf("Expected the sync token of the last page, got %q", nextSyncToken)
+	}
+	if len(syncTokens) != 2 || syncTokens[0] != "sync-1" {
+		t.Errorf("Expected the sync token on every page, got %v", syncTokens)
+	}
+}
+
+func TestListCalendarChangesExpiredSyncToken(t *testing.T) {
+	client := newTestCalendarClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusGone)
+		w.Write([]byte(`{"error":{"code":410,"message":"Sync token is no longer valid, a full sync is required."}}`))
+	})
+
+	_, _, err := listCalendarChanges(context.Background(), client, "primary", "old")
+	if !errors.Is(err, errSyncTokenExpired) {
+		t.Errorf("Expected errSyncTokenExpired, got %v", err)
+	}
+}
+
+func TestWatchCalendarRegistersWebhook(t *testing.T) {
+	var received calendar.Channel
+	client := newTestCalendarClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/calendars/primary/events/watch" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		json.NewEncoder(w).Encode(&calendar.Channel{
+			Id:         received.Id,
+			ResourceId: "resource-1",
+			Expiration: 1767225600000,
+		})
+	})
+
+	channel := &models.CalendarSyncChannel{
+		CalendarID: "primary",
+		ChannelID:  "channel-1",
+		Token:      "secret",
+		ExpiresAt:  time.UnixMilli(1767225600000),
+	}
+	registered, err := watchCalendar(context.Background(), client, channel, "https://timingle.app/api/v1/calendar/webhook")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if received.Type != "web_hook" || received.Address != "https://timingle.app/api/v1/calendar/webhook" {
+		t.Errorf("Expected a web_hook channel to the webhook URL, got %s %s", received.Type, received.Address)
+	}
+	if received.Id != "channel-1" || received.Token != "secret" {
+		t.Errorf("Expected the channel ID and token to be sent, got %s %s", received.Id, received.Token)
+	}
+	if registered.ResourceId != "resource-1" {
+		t.Errorf("Expected the resource ID from Google, got %q", registered.ResourceId)
+	}
+}
@@ -18,6 +18,7 @@ type ChatService struct {
 	chatRepo     *repositories.ChatRepository
 	userRepo     *repositories.UserRepository
 	eventService *EventService
+	mediaService *MediaService
 	hub          *ws.Hub
 	nats         nats.JetStreamContext
 }
@@ -27,6 +28,7 @@ func NewChatService(
 	chatRepo *repositories.ChatRepository,
 	userRepo *repositories.UserRepository,
 	eventService *EventService,
+	mediaService *MediaService,
 	hub *ws.Hub,
 	nats nats.JetStreamContext,
 ) *ChatService {
@@ -34,6 +36,7 @@ func NewChatService(
 		chatRepo:     chatRepo,
 		userRepo:     userRepo,
 		eventService: eventService,
+		mediaService: mediaService,
 		hub:          hub,
 		nats:         nats,
 	}
@@ -55,6 +58,28 @@ func (s *ChatService) VerifyEventAccess(eventID, userID int64) error {
 
 // SendMessage handles sending a chat message
 func (s *ChatService) SendMessage(userID, eventID int64, wsMsg *models.WSMessage) error {
+	if err := s.eventService.checkCanMessage(eventID, userID); err != nil {
+		return err
+	}
+
+	messageType := "text"
+	var attachments []string
+	switch wsMsg.MessageType {
+	case "", "text":
+		if len(wsMsg.Attachments) > 0 {
+			return fmt.Errorf("attachments require message_type image")
+		}
+	case "image":
+		messageType = "image"
+		checked, err := s.mediaService.CheckChatAttachments(userID, eventID, wsMsg.Attachments)
+		if err != nil {
+			return err
+		}
+		attachments = checked
+	default:
+		return fmt.Errorf("invalid message type: %s", wsMsg.MessageType)
+	}
+
 	// Get user info
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
@@ -80,7 +105,8 @@ func (s *ChatService) SendMessage(userID, eventID int64, wsMsg *models.WSMessage
 		SenderName:       name,
 		SenderProfileURL: profileURL,
 		Message:          wsMsg.Message,
-		MessageType:      "text",
+		MessageType:      messageType,
+		Attachments:      attachments,
 		ReplyTo:          wsMsg.ReplyTo,
 		IsDeleted:        false,
 	}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/repositories"
+)
+
+// contactLookupWindow is the period the per-user lookup limit applies to
+const contactLookupWindow = 24 * time.Hour
+
+// ErrContactSyncRateLimited is returned when a user looked up too many numbers
+var ErrContactSyncRateLimited = errors.New("too many contacts looked up, please try again later")
+
+// ContactSyncPolicy limits how many numbers a user can look up
+// Phone numbers are few enough that their hashes can be brute forced, so the
+// limits, not the hashing, are what keep the endpoint from enumerating users
+type ContactSyncPolicy struct {
+	MaxHashesPerRequest int
+	DailyLimit          int // Distinct numbers per user per 24h; re-syncing the same address book is free
+}
+
+// ContactService matches address book numbers against timingle users
+type ContactService struct {
+	userRepo   *repositories.UserRepository
+	friendRepo *repositories.FriendRepository
+	redis      *redis.Client
+	policy     ContactSyncPolicy
+}
+
+// NewContactService creates a new contact service
+func NewContactService(
+	userRepo *repositories.UserRepository,
+	friendRepo *repositories.FriendRepository,
+	redis *redis.Client,
+	policy ContactSyncPolicy,
+) *ContactService {
+	return &ContactService{
+		userRepo:   userRepo,
+		friendRepo: friendRepo,
+		redis:      redis,
+		policy:     policy,
+	}
+}
+
+func contactLookupKey(userID int64) string {
+	return "contacts:lookups:" + strconv.FormatInt(userID, 10)
+}
+
+// Sync returns the users among the uploaded phone hashes with their relation to userID
+// The user themselves and users who blocked them are never returned
+func (s *ContactService) Sync(ctx context.Context, userID int64, req *models.ContactSyncRequest) (*models.ContactSyncResponse, error) {
+	hashes, err := normalizePhoneHashes(req.PhoneHashes)
+	if err != nil {
+		return nil, err
+	}
+	if s.policy.MaxHashesPerRequest > 0 && len(hashes) > s.policy.MaxHashesPerRequest {
+		return nil, fmt.Errorf("too many phone hashes (max %d)", s.policy.MaxHashesPerRequest)
+	}
+
+	if err := s.checkLookupLimit(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	found, err := s.userRepo.FindByPhoneHashes(hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	otherIDs := make([]int64, 0, len(found))
+	for _, match := range found {
+		otherIDs = append(otherIDs, match.UserID)
+	}
+	relations, err := s.friendRepo.FindRelations(userID, otherIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []*models.ContactMatch{}
+	for _, match := range found {
+		if match.UserID == userID {
+			continue
+		}
+		relation, hidden := contactRelation(userID, match.UserID, relations)
+		if hidden {
+			continue
+		}
+		match.Relation = relation
+		matches = append(matches, match)
+	}
+
+	return &models.ContactSyncResponse{Matches: matches}, nil
+}
+
+// checkLookupLimit counts the distinct hashes a user looked up in the current window
+func (s *ContactService) checkLookupLimit(ctx context.Context, userID int64, hashes []string) error {
+	if s.policy.DailyLimit <= 0 {
+		return nil
+	}
+
+	members := make([]interface{}, len(hashes))
+	for i, hash := range hashes {
+		members[i] = hash
+	}
+
+	key := contactLookupKey(userID)
+	pipe := s.redis.TxPipeline()
+	pipe.PFAdd(ctx, key, members...)
+	count := pipe.PFCount(ctx, key)
+	ttl := pipe.TTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to check contact lookup limit: %w", err)
+	}
+
+	// The window starts with the first lookup
+	if ttl.Val() < 0 {
+		s.redis.Expire(ctx, key, contactLookupWindow)
+	}
+
+	if count.Val() > int64(s.policy.DailyLimit) {
+		return ErrContactSyncRateLimited
+	}
+	return nil
+}
+
+// normalizePhoneHashes validates hex SHA-256 digests, lowercases them and drops duplicates
+func normalizePhoneHashes(hashes []string) ([]string, error) {
+	seen := make(map[string]bool, len(hashes))
+	normalized := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		hash = strings.ToLower(strings.TrimSpace(hash))
+		if len(hash) != 64 || strings.Trim(hash, "0123456789abcdef") != "" {
+			return nil, fmt.Errorf("invalid phone hash: %q", hash)
+		}
+		if !seen[hash] {
+			seen[hash] = true
+			normalized = append(normalized, hash)
+		}
+	}
+	return normalized, nil
+}
+
+// contactRelation derives a contact's relation to userID from the friendship rows
+// between them; hidden is true when the contact blocked the user
+func contactRelation(userID, otherID int64, friendships []*models.Friendship) (models.ContactRelation, bool) {
+	relation := models.ContactRelationNone
+	for _, friendship := range friendships {
+		outgoing := friendship.UserID == userID && friendship.FriendID == otherID
+		incoming := friendship.UserID == otherID && friendship.FriendID == userID
+		if !outgoing && !incoming {
+			continue
+		}
+
+		switch {
+		case incoming && friendship.Status == models.FriendshipStatusBlocked:
+			return "", true
+		case outgoing && friendship.Status == models.FriendshipStatusBlocked:
+			relation = models.ContactRelationBlocked
+		case friendship.Status == models.FriendshipStatusAccepted && relation == models.ContactRelationNone:
+			relation = models.ContactRelationFriend
+		case outgoing && friendship.Status == models.FriendshipStatusPending:
+			relation = models.ContactRelationRequestSent
+		case incoming && friendship.Status == models.FriendshipStatusPending && relation == models.ContactRelationNone:
+			relation = models.ContactRelationRequestReceived
+		}
+	}
+	return relation, false
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+func TestNormalizePhoneHashes(t *testing.T) {
+	sum := sha256.Sum256([]byte("+821012345678"))
+	hash := hex.EncodeToString(sum[:])
+
+	got, err := normalizePhoneHashes([]string{hash, " " + strings.ToUpper(hash) + " "})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0] != hash {
+		t.Errorf("Expected duplicates to collapse to %q, got %v", hash, got)
+	}
+
+	invalid := []string{
+		"+821012345678", // Raw number
+		hash[:63],       // Too short
+		hash[:63] + "g", // Not hex
+		hash + "0",      // Too long
+	}
+	for _, value := range invalid {
+		if _, err := normalizePhoneHashes([]string{value}); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestContactRelation(t *testing.T) {
+	const me, other = int64(1), int64(2)
+	row := func(from, to int64, status models.FriendshipStatus) *models.Friendship {
+		return &models.Friendship{UserID: from, FriendID: to, Status: status}
+	}
+
+	tests := []struct {
+		name       string
+		rows       []*models.Friendship
+		want       models.ContactRelation
+		wantHidden bool
+	}{
+		{name: "no relation", want: models.ContactRelationNone},
+		{
+			name: "friends",
+			rows: []*models.Friendship{row(me, other, models.FriendshipStatusAccepted), row(other, me, models.FriendshipStatusAccepted)},
+			want: models.ContactRelationFriend,
+		},
+		{name: "request sent", rows: []*models.Friendship{row(me, other, models.FriendshipStatusPending)}, want: models.ContactRelationRequestSent},
+		{name: "request received", rows: []*models.Friendship{row(other, me, models.FriendshipStatusPending)}, want: models.ContactRelationRequestReceived},
+		{name: "blocked by me", rows: []*models.Friendship{row(me, other, models.FriendshipStatusBlocked)}, want: models.ContactRelationBlocked},
+		{
+			name:       "blocked by them is hidden",
+			rows:       []*models.Friendship{row(me, other, models.FriendshipStatusBlocked), row(other, me, models.FriendshipStatusBlocked)},
+			wantHidden: true,
+		},
+		{name: "rows of other users are ignored", rows: []*models.Friendship{row(other, 3, models.FriendshipStatusBlocked)}, want: models.ContactRelationNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, hidden := contactRelation(me, other, tt.rows)
+			if hidden != tt.wantHidden {
+				t.Fatalf("Expected hidden %v, got %v", tt.wantHidden, hidden)
+			}
+			if !hidden && got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
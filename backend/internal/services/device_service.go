@@ -0,0 +1,62 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/repositories"
+)
+
+const maxDeviceTokenLength = 4096
+
+// DeviceService handles push notification device registration
+type DeviceService struct {
+	deviceRepo *repositories.DeviceRepository
+}
+
+// NewDeviceService creates a new device service
+func NewDeviceService(deviceRepo *repositories.DeviceRepository) *DeviceService {
+	return &DeviceService{
+		deviceRepo: deviceRepo,
+	}
+}
+
+// RegisterDevice registers or refreshes a device token for the user
+func (s *DeviceService) RegisterDevice(userID int64, req *models.RegisterDeviceRequest) (*models.DeviceToken, error) {
+	provider := models.PushProvider(strings.ToUpper(string(req.Provider)))
+	if !provider.IsValid() {
+		return nil, fmt.Errorf("invalid provider: %s", req.Provider)
+	}
+
+	token := strings.TrimSpace(req.Token)
+	if token == "" || len(token) > maxDeviceTokenLength {
+		return nil, fmt.Errorf("invalid device token")
+	}
+
+	var platform *string
+	if req.Platform != nil {
+		value := strings.ToUpper(*req.Platform)
+		if value != "ANDROID" && value != "IOS" && value != "WEB" {
+			return nil, fmt.Errorf("invalid platform: %s", *req.Platform)
+		}
+		platform = &value
+	}
+
+	device := &models.DeviceToken{
+		UserID:   userID,
+		Provider: provider,
+		Platform: platform,
+		Token:    token,
+	}
+	if err := s.deviceRepo.Upsert(device); err != nil {
+		return nil, err
+	}
+
+	return device, nil
+}
+
+// UnregisterDevice removes a device token of the user (e.g. on logout)
+func (s *DeviceService) UnregisterDevice(userID int64, req *models.UnregisterDeviceRequest) error {
+	return s.deviceRepo.Delete(userID, strings.TrimSpace(req.Token))
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 200
+)
+
+// fieldChange is a single field-level change recorded in event history
+type fieldChange struct {
+	Field    string
+	OldValue string
+	NewValue string
+}
+
+// historyFields returns the audited fields of an event as strings
+func historyFields(event *models.Event) [][2]string {
+	if event == nil {
+		event = &models.Event{}
+	}
+
+	exdates := make([]string, 0, len(event.RecurrenceExDates))
+	for _, exdate := range event.RecurrenceExDates {
+		exdates = append(exdates, exdate.UTC().Format(time.RFC3339))
+	}
+
+	return [][2]string{
+		{"title", event.Title},
+		{"description", stringValue(event.Description)},
+		{"start_time", timeValue(event.StartTime)},
+		{"end_time", timeValue(event.EndTime)},
+		{"location", stringValue(event.Location)},
+		{"status", string(event.Status)},
+		{"timezone", event.Timezone},
+		{"all_day", strconv.FormatBool(event.AllDay)},
+		{"recurrence_rule", stringValue(event.RecurrenceRule)},
+		{"recurrence_exdates", strings.Join(exdates, ",")},
+	}
+}
+
+// diffEvents returns the fields that differ between two event states (nil means "did not exist")
+func diffEvents(before, after *models.Event) []fieldChange {
+	oldFields := historyFields(before)
+	newFields := historyFields(after)
+
+	changes := []fieldChange{}
+	for i := range oldFields {
+		if oldFields[i][1] != newFields[i][1] {
+			changes = append(changes, fieldChange{
+				Field:    oldFields[i][0],
+				OldValue: oldFields[i][1],
+				NewValue: newFields[i][1],
+			})
+		}
+	}
+
+	return changes
+}
+
+// occurrenceSnapshot converts an occurrence response to an event for diffing
+func occurrenceSnapshot(occurrence *models.EventResponse) *models.Event {
+	return &models.Event{
+		Title:       occurrence.Title,
+		Description: occurrence.Description,
+		StartTime:   occurrence.StartTime,
+		EndTime:     occurrence.EndTime,
+		Location:    occurrence.Location,
+		Status:      occurrence.Status,
+	}
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func timeValue(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// recordHistory writes one history entry per changed field
+// An entry with an empty field name is written when there are no field changes
+// History is an audit log, so failures are logged and never fail the mutation
+func (s *EventService) recordHistory(eventID, actorID int64, changeType string, changes []fieldChange, metadata map[string]string) {
+	actorName := ""
+	if actor, err := s.userRepo.FindByID(actorID); err == nil && actor.Name != nil {
+		actorName = *actor.Name
+	}
+
+	if len(changes) == 0 {
+		changes = []fieldChange{{}}
+	}
+
+	changedAt := time.Now()
+	for _, change := range changes {
+		entry := &models.EventHistoryEntry{
+			EventID:    eventID,
+			ChangedAt:  changedAt,
+			ChangeID:   uuid.New(),
+			ActorID:    actorID,
+			ActorName:  actorName,
+			ChangeType: changeType,
+			FieldName:  change.Field,
+			OldValue:   change.OldValue,
+			NewValue:   change.NewValue,
+			Metadata:   metadata,
+		}
+		if err := s.chatRepo.SaveEventHistory(entry); err != nil {
+			fmt.Printf("Warning: failed to save event history for event %d: %v\n", eventID, err)
+		}
+	}
+}
+
+// recordParticipantHistory records a participant being added or removed
+func (s *EventService) recordParticipantHistory(eventID, actorID, participantID int64, changeType string) {
+	change := fieldChange{Field: "participant"}
+	if changeType == models.HistoryChangeParticipantAdded {
+		change.NewValue = strconv.FormatInt(participantID, 10)
+	} else {
+		change.OldValue = strconv.FormatInt(participantID, 10)
+	}
+
+	metadata := map[string]string{}
+	if participant, err := s.userRepo.FindByID(participantID); err == nil && participant.Name != nil {
+		metadata["participant_name"] = *participant.Name
+	}
+
+	s.recordHistory(eventID, actorID, changeType, []fieldChange{change}, metadata)
+}
+
+// GetEventHistory returns a page of an event's change history to one of its members
+func (s *EventService) GetEventHistory(eventID, userID int64, limit int, cursor string) (*models.EventHistoryPage, error) {
+	isMember, err := s.IsUserEventMember(eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, fmt.Errorf("user is not a member of this event")
+	}
+
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	if limit > maxHistoryLimit {
+		limit = maxHistoryLimit
+	}
+
+	var pageState []byte
+	if cursor != "" {
+		pageState, err = base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+	}
+
+	entries, nextPageState, err := s.chatRepo.GetEventHistory(eventID, limit, pageState)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.EventHistoryPage{Entries: entries}
+	if len(nextPageState) > 0 {
+		page.NextCursor = base64.RawURLEncoding.EncodeToString(nextPageState)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+func TestDiffEvents(t *testing.T) {
+	start := time.Date(2024, 1, 2, 19, 0, 0, 0, time.UTC)
+	before := &models.Event{
+		Title:     "Dinner",
+		StartTime: start,
+		EndTime:   start.Add(2 * time.Hour),
+		Location:  strPtr("Gangnam"),
+		Status:    models.EventStatusProposed,
+	}
+	after := *before
+	after.StartTime = start.Add(time.Hour)
+	after.Location = strPtr("Hongdae")
+
+	changes := diffEvents(before, &after)
+
+	expected := []fieldChange{
+		{Field: "start_time", OldValue: "2024-01-02T19:00:00Z", NewValue: "2024-01-02T20:00:00Z"},
+		{Field: "location", OldValue: "Gangnam", NewValue: "Hongdae"},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %d: %+v", len(expected), len(changes), changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("Change %d: expected %+v, got %+v", i, expected[i], changes[i])
+		}
+	}
+}
+
+func TestDiffEvents_Created(t *testing.T) {
+	start := time.Date(2024, 1, 2, 19, 0, 0, 0, time.UTC)
+	event := &models.Event{
+		Title:     "Dinner",
+		StartTime: start,
+		EndTime:   start.Add(2 * time.Hour),
+		Status:    models.EventStatusProposed,
+	}
+
+	changes := diffEvents(nil, event)
+
+	fields := map[string]fieldChange{}
+	for _, change := range changes {
+		fields[change.Field] = change
+	}
+	if len(fields) != 4 {
+		t.Errorf("Expected title, start_time, end_time and status, got %+v", changes)
+	}
+	if fields["start_time"].OldValue != "" || fields["start_time"].NewValue != "2024-01-02T19:00:00Z" {
+		t.Errorf("Unexpected start_time change: %+v", fields["start_time"])
+	}
+}
@@ -0,0 +1,585 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/pkg/rrule"
+)
+
+// normalizeRecurrenceRule validates an RRULE and returns its canonical form
+// Returns nil for an empty rule (non-recurring event)
+func normalizeRecurrenceRule(value *string) (*string, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+
+	rule, err := rrule.Parse(*value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence rule: %w", err)
+	}
+
+	normalized := rule.String()
+	return &normalized, nil
+}
+
+// isOccurrence checks that occurrenceStart is a (non-excluded) occurrence of a recurring event
+func isOccurrence(event *models.Event, occurrenceStart time.Time) (bool, error) {
+	if !event.IsRecurring() {
+		return false, nil
+	}
+
+	rule, err := rrule.Parse(*event.RecurrenceRule)
+	if err != nil {
+		return false, fmt.Errorf("invalid recurrence rule: %w", err)
+	}
+
+	return rule.Includes(event.SeriesStart(), occurrenceStart) && !event.IsExcluded(occurrenceStart), nil
+}
+
+// expandOccurrences expands a recurring event into the occurrences overlapping a window
+// Per-occurrence overrides and responses are applied to each occurrence
+func (s *EventService) expandOccurrences(event *models.Event, base *models.EventResponse, window *models.TimeWindow) ([]*models.EventResponse, error) {
+	rule, err := rrule.Parse(*event.RecurrenceRule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence rule: %w", err)
+	}
+
+	overrides, err := s.eventRepo.FindOccurrenceOverrides(event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := s.eventRepo.FindOccurrenceResponses(event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	overridesByStart := make(map[int64]*models.EventOccurrenceOverride, len(overrides))
+	for _, override := range overrides {
+		overridesByStart[override.OccurrenceStart.Unix()] = override
+	}
+
+	responsesByStart := make(map[int64][]*models.OccurrenceResponse)
+	for _, response := range responses {
+		key := response.OccurrenceStart.Unix()
+		responsesByStart[key] = append(responsesByStart[key], response)
+	}
+
+	duration := event.EndTime.Sub(event.StartTime)
+	occurrences := []*models.EventResponse{}
+	seen := make(map[int64]bool)
+
+	// Occurrences ending inside the window may start up to one duration before it
+	// Starts are computed in the event's zone and returned in UTC like stored times
+	for _, start := range rule.Between(event.SeriesStart(), window.Start.Add(-duration), window.End) {
+		start = start.UTC()
+		if event.IsExcluded(start) {
+			continue
+		}
+
+		key := start.Unix()
+		seen[key] = true
+
+		occurrence := buildOccurrence(base, start, duration, overridesByStart[key], responsesByStart[key])
+		if window.Overlaps(occurrence.StartTime, occurrence.EndTime) {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+
+	// Occurrences moved into the window from outside of it
+	for _, override := range overrides {
+		key := override.OccurrenceStart.Unix()
+		if seen[key] || (override.StartTime == nil && override.EndTime == nil) {
+			continue
+		}
+		if event.IsExcluded(override.OccurrenceStart) || !rule.Includes(event.SeriesStart(), override.OccurrenceStart) {
+			continue
+		}
+
+		occurrence := buildOccurrence(base, override.OccurrenceStart, duration, override, responsesByStart[key])
+		if window.Overlaps(occurrence.StartTime, occurrence.EndTime) {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+
+	return occurrences, nil
+}
+
+// buildOccurrence builds the response of a single occurrence from the series response
+func buildOccurrence(
+	base *models.EventResponse,
+	occurrenceStart time.Time,
+	duration time.Duration,
+	override *models.EventOccurrenceOverride,
+	responses []*models.OccurrenceResponse,
+) *models.EventResponse {
+	occurrence := *base
+	occurrence.StartTime = occurrenceStart
+	occurrence.EndTime = occurrenceStart.Add(duration)
+	occurrence.OccurrenceStart = &occurrenceStart
+	occurrence.OccurrenceResponses = responses
+
+	if override != nil {
+		if override.Title != nil {
+			occurrence.Title = *override.Title
+		}
+		if override.Description != nil {
+			occurrence.Description = override.Description
+		}
+		if override.StartTime != nil {
+			occurrence.StartTime = *override.StartTime
+		}
+		if override.EndTime != nil {
+			occurrence.EndTime = *override.EndTime
+		}
+		if override.Location != nil {
+			occurrence.Location = override.Location
+		}
+		if override.IsCanceled {
+			occurrence.Status = models.EventStatusCanceled
+		}
+	}
+
+	occurrence.SetDates()
+	return &occurrence
+}
+
+// GetOccurrence gets a single occurrence of a recurring event
+func (s *EventService) GetOccurrence(eventID int64, occurrenceStart time.Time) (*models.EventResponse, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("event not found")
+	}
+
+	ok, err := isOccurrence(event, occurrenceStart)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("occurrence not found")
+	}
+
+	base, err := s.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	override, err := s.eventRepo.FindOccurrenceOverride(eventID, occurrenceStart)
+	if err != nil {
+		return nil, err
+	}
+
+	allResponses, err := s.eventRepo.FindOccurrenceResponses(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []*models.OccurrenceResponse
+	for _, response := range allResponses {
+		if response.OccurrenceStart.Equal(occurrenceStart) {
+			responses = append(responses, response)
+		}
+	}
+
+	return buildOccurrence(base, occurrenceStart, event.EndTime.Sub(event.StartTime), override, responses), nil
+}
+
+// upcomingStarts returns the (occurrence) start times of an event inside the window
+func (s *EventService) upcomingStarts(event *models.Event, window *models.TimeWindow) ([]time.Time, error) {
+	if !event.IsRecurring() {
+		return []time.Time{event.StartTime}, nil
+	}
+
+	base, err := s.GetEvent(event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences, err := s.expandOccurrences(event, base, window)
+	if err != nil {
+		return nil, err
+	}
+
+	starts := []time.Time{}
+	for _, occurrence := range occurrences {
+		if occurrence.Status == models.EventStatusCanceled || !occurrence.StartTime.After(window.Start) {
+			continue
+		}
+		starts = append(starts, occurrence.StartTime)
+	}
+	return starts, nil
+}
+
+// updateOccurrence stores an override for a single occurrence ("this occurrence")
+func (s *EventService) updateOccurrence(event *models.Event, userID int64, req *models.UpdateEventRequest) (*models.EventResponse, error) {
+	if req.RecurrenceRule != nil || req.RecurrenceExDates != nil {
+		return nil, fmt.Errorf("recurrence can only be changed for the whole series")
+	}
+	if req.Timezone != nil || req.AllDay != nil {
+		return nil, fmt.Errorf("timezone and all-day can only be changed for the whole series")
+	}
+
+	occurrenceStart := *req.OccurrenceStart
+	ok, err := isOccurrence(event, occurrenceStart)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("occurrence not found")
+	}
+
+	override, err := s.eventRepo.FindOccurrenceOverride(event.ID, occurrenceStart)
+	if err != nil {
+		return nil, err
+	}
+	if override == nil {
+		override = &models.EventOccurrenceOverride{
+			EventID:         event.ID,
+			OccurrenceStart: occurrenceStart,
+		}
+	}
+
+	if req.Title != nil {
+		override.Title = req.Title
+	}
+	if req.Description != nil {
+		override.Description = req.Description
+	}
+	if req.StartTime != nil {
+		override.StartTime = req.StartTime
+	}
+	if req.EndTime != nil {
+		override.EndTime = req.EndTime
+	}
+	if req.Location != nil {
+		override.Location = req.Location
+	}
+	if req.Status != nil {
+		override.IsCanceled = *req.Status == models.EventStatusCanceled
+	}
+	override.UpdatedBy = &userID
+
+	// Validate times
+	startTime := occurrenceStart
+	if override.StartTime != nil {
+		startTime = *override.StartTime
+	}
+	endTime := occurrenceStart.Add(event.EndTime.Sub(event.StartTime))
+	if override.EndTime != nil {
+		endTime = *override.EndTime
+	}
+	if event.AllDay && (override.StartTime != nil || override.EndTime != nil) {
+		startTime, endTime = allDayRange(startTime, endTime)
+		override.StartTime, override.EndTime = &startTime, &endTime
+	}
+	if endTime.Before(startTime) {
+		return nil, fmt.Errorf("end time must be after start time")
+	}
+
+	before, err := s.GetOccurrence(event.ID, occurrenceStart)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.eventRepo.UpsertOccurrenceOverride(override); err != nil {
+		return nil, err
+	}
+
+	after, err := s.GetOccurrence(event.ID, occurrenceStart)
+	if err != nil {
+		return nil, err
+	}
+
+	if changes := diffEvents(occurrenceSnapshot(before), occurrenceSnapshot(after)); len(changes) > 0 {
+		s.recordHistory(event.ID, userID, models.HistoryChangeUpdated, changes, map[string]string{
+			"occurrence_start": occurrenceStart.UTC().Format(time.RFC3339),
+		})
+		s.resetReminders(event.ID)
+		if notificationType, ok := notificationTypeForChanges(changes); ok {
+			data := eventNotificationData(occurrenceSnapshot(after))
+			data["occurrence_start"] = occurrenceStart.UTC().Format(time.RFC3339)
+			s.notifyMembers(event, userID, notificationType, data)
+		}
+	}
+
+	return after, nil
+}
+
+// updateFollowingOccurrences splits a recurring series at an occurrence
+// ("this and following"): the original series ends before the occurrence and a
+// new series with the requested changes starts at it
+func (s *EventService) updateFollowingOccurrences(event *models.Event, userID int64, req *models.UpdateEventRequest) (*models.EventResponse, error) {
+	occurrenceStart := *req.OccurrenceStart
+	ok, err := isOccurrence(event, occurrenceStart)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("occurrence not found")
+	}
+
+	rule, err := rrule.Parse(*event.RecurrenceRule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence rule: %w", err)
+	}
+
+	headRule, tailRule := *rule, *rule
+	if rule.Count > 0 {
+		consumed := len(rule.Between(event.SeriesStart(), event.StartTime, occurrenceStart))
+		headRule.Count = consumed
+		tailRule.Count = rule.Count - consumed
+	} else {
+		headRule.Until = occurrenceStart.Add(-time.Second)
+	}
+
+	var headExDates, tailExDates []time.Time
+	for _, exdate := range event.RecurrenceExDates {
+		if exdate.Before(occurrenceStart) {
+			headExDates = append(headExDates, exdate)
+		} else {
+			tailExDates = append(tailExDates, exdate)
+		}
+	}
+
+	// Create the new series starting at the occurrence
+	tailRuleString := tailRule.String()
+	series := &models.Event{
+		Title:             event.Title,
+		Description:       event.Description,
+		StartTime:         occurrenceStart,
+		EndTime:           occurrenceStart.Add(event.EndTime.Sub(event.StartTime)),
+		Location:          event.Location,
+		CreatorID:         event.CreatorID,
+		Status:            event.Status,
+		Timezone:          event.Timezone,
+		AllDay:            event.AllDay,
+		RecurrenceRule:    &tailRuleString,
+		RecurrenceExDates: tailExDates,
+		ParentEventID:     &event.ID,
+	}
+	unchanged := *series
+	if err := applyEventUpdate(series, req); err != nil {
+		return nil, err
+	}
+
+	// Occurrences move with the new series' start, so excluded dates and
+	// per-occurrence state keep referring to the same occurrences
+	shift := series.StartTime.Sub(occurrenceStart)
+	if shift != 0 && req.RecurrenceExDates == nil {
+		series.RecurrenceExDates = shiftTimes(series.RecurrenceExDates, shift)
+		if series.AllDay {
+			series.RecurrenceExDates = allDayExDates(series.RecurrenceExDates)
+		}
+	}
+
+	if err := s.eventRepo.Create(series); err != nil {
+		return nil, fmt.Errorf("failed to create event series: %w", err)
+	}
+
+	// Carry participants and per-occurrence state over to the new series
+	participantIDs, err := s.eventRepo.FindParticipants(event.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find participants: %w", err)
+	}
+	for _, participantID := range participantIDs {
+		if err := s.eventRepo.AddParticipant(series.ID, participantID); err != nil {
+			// Log error but continue
+			fmt.Printf("Failed to add participant %d: %v\n", participantID, err)
+		}
+	}
+
+	dropped, err := s.unmatchedOccurrences(event.ID, series, occurrenceStart, shift)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.eventRepo.ReassignOccurrences(event.ID, series.ID, occurrenceStart, shift, dropped); err != nil {
+		return nil, err
+	}
+
+	splitMetadata := map[string]string{
+		"occurrence_start": occurrenceStart.UTC().Format(time.RFC3339),
+		"parent_event_id":  strconv.FormatInt(event.ID, 10),
+		"series_event_id":  strconv.FormatInt(series.ID, 10),
+	}
+	s.recordHistory(series.ID, userID, models.HistoryChangeCreated, diffEvents(nil, series), splitMetadata)
+
+	// End the original series before the occurrence
+	before := *event
+	headRuleString := headRule.String()
+	event.RecurrenceRule = &headRuleString
+	event.RecurrenceExDates = headExDates
+	if err := s.eventRepo.Update(event); err != nil {
+		return nil, fmt.Errorf("failed to update event: %w", err)
+	}
+
+	s.recordHistory(event.ID, userID, models.HistoryChangeUpdated, diffEvents(&before, event), splitMetadata)
+	s.resetReminders(event.ID)
+
+	if notificationType, ok := notificationTypeForChanges(diffEvents(&unchanged, series)); ok {
+		data := eventNotificationData(series)
+		data["occurrence_start"] = occurrenceStart.UTC().Format(time.RFC3339)
+		s.notifyMembers(series, userID, notificationType, data)
+	}
+
+	return s.GetEvent(series.ID)
+}
+
+// unmatchedOccurrences returns the occurrences of an event's overrides and responses
+// at or after since that are no longer occurrences of the new series once moved by shift
+// (its rule, time zone or all-day flag changed)
+func (s *EventService) unmatchedOccurrences(eventID int64, series *models.Event, since time.Time, shift time.Duration) ([]time.Time, error) {
+	overrides, err := s.eventRepo.FindOccurrenceOverrides(eventID)
+	if err != nil {
+		return nil, err
+	}
+	responses, err := s.eventRepo.FindOccurrenceResponses(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	var starts []time.Time
+	for _, override := range overrides {
+		starts = append(starts, override.OccurrenceStart)
+	}
+	for _, response := range responses {
+		starts = append(starts, response.OccurrenceStart)
+	}
+
+	return occurrencesOutsideSeries(series, starts, since, shift)
+}
+
+// occurrencesOutsideSeries returns the distinct starts at or after since that,
+// moved by shift, are not generated by the series' rule
+// Excluded dates are not checked: state of an excluded occurrence is kept for when it is restored.
+func occurrencesOutsideSeries(series *models.Event, starts []time.Time, since time.Time, shift time.Duration) ([]time.Time, error) {
+	var rule *rrule.Rule
+	if series.IsRecurring() {
+		parsed, err := rrule.Parse(*series.RecurrenceRule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recurrence rule: %w", err)
+		}
+		rule = parsed
+	}
+
+	seen := map[int64]bool{}
+	var outside []time.Time
+	for _, start := range starts {
+		if start.Before(since) || seen[start.UnixNano()] {
+			continue
+		}
+		seen[start.UnixNano()] = true
+		if rule == nil || !rule.Includes(series.SeriesStart(), start.Add(shift)) {
+			outside = append(outside, start)
+		}
+	}
+
+	return outside, nil
+}
+
+// shiftTimes moves each time by shift
+func shiftTimes(times []time.Time, shift time.Duration) []time.Time {
+	if len(times) == 0 {
+		return times
+	}
+	shifted := make([]time.Time, len(times))
+	for i, t := range times {
+		shifted[i] = t.Add(shift)
+	}
+	return shifted
+}
+
+// CancelOccurrence cancels a single occurrence of a recurring event
+func (s *EventService) CancelOccurrence(eventID, userID int64, occurrenceStart time.Time) error {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return fmt.Errorf("event not found")
+	}
+
+	// Check if user is the creator
+	if event.CreatorID != userID {
+		return fmt.Errorf("only creator can cancel event")
+	}
+
+	ok, err := isOccurrence(event, occurrenceStart)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("occurrence not found")
+	}
+
+	override, err := s.eventRepo.FindOccurrenceOverride(eventID, occurrenceStart)
+	if err != nil {
+		return err
+	}
+	if override == nil {
+		override = &models.EventOccurrenceOverride{
+			EventID:         eventID,
+			OccurrenceStart: occurrenceStart,
+		}
+	}
+	wasCanceled := override.IsCanceled
+	override.IsCanceled = true
+	override.UpdatedBy = &userID
+
+	if err := s.eventRepo.UpsertOccurrenceOverride(override); err != nil {
+		return err
+	}
+
+	if !wasCanceled {
+		s.recordHistory(eventID, userID, models.HistoryChangeCanceled, []fieldChange{{
+			Field:    "status",
+			OldValue: string(event.Status),
+			NewValue: string(models.EventStatusCanceled),
+		}}, map[string]string{
+			"occurrence_start": occurrenceStart.UTC().Format(time.RFC3339),
+		})
+		s.resetReminders(eventID)
+
+		occurrence := *event
+		occurrence.StartTime = occurrenceStart
+		occurrence.EndTime = occurrenceStart.Add(event.EndTime.Sub(event.StartTime))
+		data := eventNotificationData(&occurrence)
+		data["occurrence_start"] = occurrenceStart.UTC().Format(time.RFC3339)
+		s.notifyMembers(event, userID, models.NotificationEventCanceled, data)
+	}
+	return nil
+}
+
+// RespondToOccurrence records a participant's response to a single occurrence
+func (s *EventService) RespondToOccurrence(eventID, userID int64, req *models.RespondOccurrenceRequest) error {
+	if req.Status != models.ParticipantStatusAccepted && req.Status != models.ParticipantStatusDeclined {
+		return fmt.Errorf("status must be %s or %s", models.ParticipantStatusAccepted, models.ParticipantStatusDeclined)
+	}
+
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return fmt.Errorf("event not found")
+	}
+
+	isMember, err := s.IsUserEventMember(eventID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return fmt.Errorf("user is not a member of this event")
+	}
+
+	ok, err := isOccurrence(event, req.OccurrenceStart)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("occurrence not found")
+	}
+
+	return s.eventRepo.SaveOccurrenceResponse(&models.OccurrenceResponse{
+		EventID:         eventID,
+		OccurrenceStart: req.OccurrenceStart,
+		UserID:          userID,
+		Status:          req.Status,
+	})
+}
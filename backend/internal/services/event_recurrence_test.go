@@ -0,0 +1,153 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+func TestNormalizeRecurrenceRule(t *testing.T) {
+	empty := ""
+	if rule, err := normalizeRecurrenceRule(&empty); err != nil || rule != nil {
+		t.Errorf("Expected empty rule to normalize to nil, got %v, %v", rule, err)
+	}
+
+	value := "RRULE:freq=weekly;byday=TU"
+	rule, err := normalizeRecurrenceRule(&value)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if *rule != "FREQ=WEEKLY;BYDAY=TU" {
+		t.Errorf("Expected canonical rule, got %q", *rule)
+	}
+
+	invalid := "FREQ=SOMETIMES"
+	if _, err := normalizeRecurrenceRule(&invalid); err == nil {
+		t.Error("Expected error for invalid rule")
+	}
+}
+
+func TestIsOccurrence(t *testing.T) {
+	start := time.Date(2024, 1, 2, 19, 0, 0, 0, time.UTC)
+	rule := "FREQ=WEEKLY;COUNT=4"
+	event := &models.Event{
+		StartTime:         start,
+		EndTime:           start.Add(2 * time.Hour),
+		RecurrenceRule:    &rule,
+		RecurrenceExDates: []time.Time{start.AddDate(0, 0, 7)},
+	}
+
+	tests := []struct {
+		name     string
+		t        time.Time
+		expected bool
+	}{
+		{"first occurrence", start, true},
+		{"excluded occurrence", start.AddDate(0, 0, 7), false},
+		{"third occurrence", start.AddDate(0, 0, 14), true},
+		{"after COUNT", start.AddDate(0, 0, 28), false},
+		{"not on rule", start.Add(time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := isOccurrence(event, tt.t)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if ok != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, ok)
+			}
+		})
+	}
+}
+
+func TestOccurrencesOutsideSeries(t *testing.T) {
+	// Original series: Tuesdays 19:00; the tail moves to Wednesdays 20:00
+	original := time.Date(2024, 1, 2, 19, 0, 0, 0, time.UTC)
+	start := original.AddDate(0, 0, 15).Add(time.Hour)
+	shift := start.Sub(original.AddDate(0, 0, 14))
+	rule := "FREQ=WEEKLY"
+	series := &models.Event{
+		StartTime:      start,
+		EndTime:        start.Add(2 * time.Hour),
+		RecurrenceRule: &rule,
+	}
+
+	starts := []time.Time{
+		original.AddDate(0, 0, 7),  // before the split, stays on the original series
+		original.AddDate(0, 0, 14), // the split occurrence
+		original.AddDate(0, 0, 21),
+		original.AddDate(0, 0, 21), // override and response of the same occurrence
+	}
+
+	outside, err := occurrencesOutsideSeries(series, starts, original.AddDate(0, 0, 14), shift)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(outside) != 0 {
+		t.Errorf("Expected shifted occurrences to match the new series, got %v", outside)
+	}
+
+	// Every other week, the week after the split is no longer an occurrence
+	biweekly := "FREQ=WEEKLY;INTERVAL=2"
+	series.RecurrenceRule = &biweekly
+	outside, err = occurrencesOutsideSeries(series, starts, original.AddDate(0, 0, 14), shift)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(outside) != 1 || !outside[0].Equal(original.AddDate(0, 0, 21)) {
+		t.Errorf("Expected only %v outside the new rule, got %v", original.AddDate(0, 0, 21), outside)
+	}
+
+	// Without a rule the new event has no occurrences
+	series.RecurrenceRule = nil
+	outside, _ = occurrencesOutsideSeries(series, starts, original.AddDate(0, 0, 14), shift)
+	if len(outside) != 2 {
+		t.Errorf("Expected all occurrences from the split to be outside a single event, got %v", outside)
+	}
+}
+
+func TestBuildOccurrence(t *testing.T) {
+	start := time.Date(2024, 1, 2, 19, 0, 0, 0, time.UTC)
+	base := &models.EventResponse{
+		ID:        1,
+		Title:     "Study group",
+		StartTime: start,
+		EndTime:   start.Add(2 * time.Hour),
+		Status:    models.EventStatusConfirmed,
+	}
+
+	occurrenceStart := start.AddDate(0, 0, 7)
+	movedStart := occurrenceStart.Add(time.Hour)
+	override := &models.EventOccurrenceOverride{
+		OccurrenceStart: occurrenceStart,
+		Title:           strPtr("Study group (moved)"),
+		StartTime:       &movedStart,
+	}
+
+	occurrence := buildOccurrence(base, occurrenceStart, 2*time.Hour, override, nil)
+
+	if occurrence.Title != "Study group (moved)" {
+		t.Errorf("Expected overridden title, got %q", occurrence.Title)
+	}
+	if !occurrence.StartTime.Equal(movedStart) {
+		t.Errorf("Expected start %s, got %s", movedStart, occurrence.StartTime)
+	}
+	if !occurrence.EndTime.Equal(occurrenceStart.Add(2 * time.Hour)) {
+		t.Errorf("Expected end to follow the original occurrence, got %s", occurrence.EndTime)
+	}
+	if occurrence.OccurrenceStart == nil || !occurrence.OccurrenceStart.Equal(occurrenceStart) {
+		t.Errorf("Expected occurrence_start %s, got %v", occurrenceStart, occurrence.OccurrenceStart)
+	}
+	if base.Title != "Study group" {
+		t.Error("Base response must not be modified")
+	}
+
+	override.IsCanceled = true
+	canceled := buildOccurrence(base, occurrenceStart, 2*time.Hour, override, nil)
+	if canceled.Status != models.EventStatusCanceled {
+		t.Errorf("Expected CANCELED status, got %s", canceled.Status)
+	}
+}
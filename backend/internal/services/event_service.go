@@ -1,27 +1,61 @@
 package services
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/khchoi-tnh/timingle/internal/models"
 	"github.com/khchoi-tnh/timingle/internal/repositories"
+	"github.com/nats-io/nats.go"
 )
 
+// InviteSubject is the EVENTS stream subject invite notifications are published to
+const InviteSubject = "event.invited"
+
 // EventService handles event business logic
 type EventService struct {
-	eventRepo *repositories.EventRepository
-	userRepo  *repositories.UserRepository
+	eventRepo           *repositories.EventRepository
+	userRepo            *repositories.UserRepository
+	pollRepo            *repositories.PollRepository
+	chatRepo            *repositories.ChatRepository
+	attendanceRepo      *repositories.AttendanceRepository
+	reminderRepo        *repositories.ReminderRepository
+	friendRepo          *repositories.FriendRepository
+	inviteRepo          *repositories.InviteRepository
+	notificationService *NotificationService
+	calendarService     *CalendarService
+	nats                nats.JetStreamContext
 }
 
 // NewEventService creates a new event service
 func NewEventService(
 	eventRepo *repositories.EventRepository,
 	userRepo *repositories.UserRepository,
+	pollRepo *repositories.PollRepository,
+	chatRepo *repositories.ChatRepository,
+	attendanceRepo *repositories.AttendanceRepository,
+	reminderRepo *repositories.ReminderRepository,
+	friendRepo *repositories.FriendRepository,
+	inviteRepo *repositories.InviteRepository,
+	notificationService *NotificationService,
+	calendarService *CalendarService,
+	nats nats.JetStreamContext,
 ) *EventService {
 	return &EventService{
-		eventRepo: eventRepo,
-		userRepo:  userRepo,
+		eventRepo:           eventRepo,
+		userRepo:            userRepo,
+		pollRepo:            pollRepo,
+		chatRepo:            chatRepo,
+		attendanceRepo:      attendanceRepo,
+		reminderRepo:        reminderRepo,
+		friendRepo:          friendRepo,
+		inviteRepo:          inviteRepo,
+		notificationService: notificationService,
+		calendarService:     calendarService,
+		nats:                nats,
 	}
 }
 
@@ -32,29 +66,61 @@ func (s *EventService) CreateEvent(creatorID int64, req *models.CreateEventReque
 		return nil, fmt.Errorf("end time must be after start time")
 	}
 
+	// Validate recurrence rule
+	recurrenceRule, err := normalizeRecurrenceRule(req.RecurrenceRule)
+	if err != nil {
+		return nil, err
+	}
+
+	timezone, err := s.eventTimezone(creatorID, req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create event
 	event := &models.Event{
-		Title:       req.Title,
-		Description: req.Description,
-		StartTime:   req.StartTime,
-		EndTime:     req.EndTime,
-		Location:    req.Location,
-		CreatorID:   creatorID,
-		Status:      models.EventStatusProposed,
+		Title:             req.Title,
+		Description:       req.Description,
+		StartTime:         req.StartTime,
+		EndTime:           req.EndTime,
+		Location:          req.Location,
+		CreatorID:         creatorID,
+		Status:            models.EventStatusProposed,
+		Timezone:          timezone,
+		AllDay:            req.AllDay,
+		RecurrenceRule:    recurrenceRule,
+		RecurrenceExDates: req.RecurrenceExDates,
+	}
+	if event.AllDay {
+		event.StartTime, event.EndTime = allDayRange(req.StartTime, req.EndTime)
+		event.RecurrenceExDates = allDayExDates(req.RecurrenceExDates)
 	}
 
 	if err := s.eventRepo.Create(event); err != nil {
 		return nil, fmt.Errorf("failed to create event: %w", err)
 	}
 
+	s.recordHistory(event.ID, creatorID, models.HistoryChangeCreated, diffEvents(nil, event), nil)
+
 	// Add participants
 	if len(req.ParticipantIDs) > 0 {
+		invited := []int64{}
 		for _, participantID := range req.ParticipantIDs {
+			if err := s.checkNotBlocked(creatorID, participantID); err != nil {
+				// Log error but continue
+				fmt.Printf("Failed to add participant %d: %v\n", participantID, err)
+				continue
+			}
 			if err := s.eventRepo.AddParticipant(event.ID, participantID); err != nil {
 				// Log error but continue
 				fmt.Printf("Failed to add participant %d: %v\n", participantID, err)
+				continue
 			}
+			s.recordParticipantHistory(event.ID, creatorID, participantID, models.HistoryChangeParticipantAdded)
+			invited = append(invited, participantID)
 		}
+		s.publishInvite(event, creatorID, invited)
+		s.notifyUsers(invited, event, creatorID, models.NotificationEventInvited, eventNotificationData(event))
 	}
 
 	// Load event with participants
@@ -96,6 +162,8 @@ func (s *EventService) GetEvent(eventID int64) (*models.EventResponse, error) {
 }
 
 // UpdateEvent updates an event
+// For recurring events, req.Scope selects whether the change applies to a single
+// occurrence, to an occurrence and all following ones, or to the whole series
 func (s *EventService) UpdateEvent(eventID, userID int64, req *models.UpdateEventRequest) (*models.EventResponse, error) {
 	// Get existing event
 	event, err := s.eventRepo.FindByID(eventID)
@@ -108,7 +176,57 @@ func (s *EventService) UpdateEvent(eventID, userID int64, req *models.UpdateEven
 		return nil, fmt.Errorf("only creator can update event")
 	}
 
+	// Occurrence-level edits of a recurring event
+	if event.IsRecurring() && req.Scope != nil && *req.Scope != models.RecurrenceScopeAll {
+		if req.OccurrenceStart == nil {
+			return nil, fmt.Errorf("occurrence_start is required for scope %s", *req.Scope)
+		}
+
+		switch *req.Scope {
+		case models.RecurrenceScopeThis:
+			return s.updateOccurrence(event, userID, req)
+		case models.RecurrenceScopeThisAndFollowing:
+			// Splitting at the first occurrence is the same as editing the whole series
+			if !req.OccurrenceStart.Equal(event.StartTime) {
+				return s.updateFollowingOccurrences(event, userID, req)
+			}
+		default:
+			return nil, fmt.Errorf("invalid scope: %s", *req.Scope)
+		}
+	}
+
 	// Update fields
+	before := *event
+	if err := applyEventUpdate(event, req); err != nil {
+		return nil, err
+	}
+
+	// Update event
+	if err := s.eventRepo.Update(event); err != nil {
+		return nil, fmt.Errorf("failed to update event: %w", err)
+	}
+
+	if changes := diffEvents(&before, event); len(changes) > 0 {
+		s.recordHistory(eventID, userID, models.HistoryChangeUpdated, changes, nil)
+		s.resetReminders(eventID)
+		if notificationType, ok := notificationTypeForChanges(changes); ok {
+			s.notifyMembers(event, userID, notificationType, eventNotificationData(event))
+		}
+		s.calendarService.EnqueueEventChange(eventID)
+	}
+
+	// Return updated event
+	return s.GetEvent(eventID)
+}
+
+// applyEventUpdate applies the fields of an update request to an event and validates the result
+func applyEventUpdate(event *models.Event, req *models.UpdateEventRequest) error {
+	// Stored times of a timed event turned all-day keep their date in the event's zone
+	storedStart, storedEnd := event.StartTime.UTC(), event.EndTime.UTC()
+	if !event.AllDay {
+		storedStart, storedEnd = event.StartTime.In(event.Zone()), event.EndTime.In(event.Zone())
+	}
+
 	if req.Title != nil {
 		event.Title = *req.Title
 	}
@@ -127,19 +245,47 @@ func (s *EventService) UpdateEvent(eventID, userID int64, req *models.UpdateEven
 	if req.Status != nil {
 		event.Status = *req.Status
 	}
+	if req.RecurrenceRule != nil {
+		recurrenceRule, err := normalizeRecurrenceRule(req.RecurrenceRule)
+		if err != nil {
+			return err
+		}
+		event.RecurrenceRule = recurrenceRule
+		if recurrenceRule == nil {
+			event.RecurrenceExDates = nil
+		}
+	}
+	if req.RecurrenceExDates != nil {
+		event.RecurrenceExDates = req.RecurrenceExDates
+	}
+	if req.Timezone != nil {
+		if err := validateTimezone(*req.Timezone); err != nil {
+			return err
+		}
+		event.Timezone = *req.Timezone
+	}
+	if req.AllDay != nil {
+		event.AllDay = *req.AllDay
+	}
 
-	// Validate times
-	if event.EndTime.Before(event.StartTime) {
-		return nil, fmt.Errorf("end time must be after start time")
+	if event.AllDay {
+		start, end := storedStart, storedEnd
+		if req.StartTime != nil {
+			start = *req.StartTime
+		}
+		if req.EndTime != nil {
+			end = *req.EndTime
+		}
+		event.StartTime, event.EndTime = allDayRange(start, end)
+		event.RecurrenceExDates = allDayExDates(event.RecurrenceExDates)
 	}
 
-	// Update event
-	if err := s.eventRepo.Update(event); err != nil {
-		return nil, fmt.Errorf("failed to update event: %w", err)
+	// Validate times
+	if event.EndTime.Before(event.StartTime) {
+		return fmt.Errorf("end time must be after start time")
 	}
 
-	// Return updated event
-	return s.GetEvent(eventID)
+	return nil
 }
 
 // DeleteEvent deletes an event
@@ -155,20 +301,41 @@ func (s *EventService) DeleteEvent(eventID, userID int64) error {
 		return fmt.Errorf("only creator can delete event")
 	}
 
+	// Members' calendar copies are forgotten with the event, so find them first
+	copies, err := s.calendarService.FindEventCopies(eventID)
+	if err != nil {
+		// Log error but continue
+		fmt.Printf("Warning: failed to find calendar copies of event %d: %v\n", eventID, err)
+	}
+
 	// Delete event
-	return s.eventRepo.Delete(eventID)
+	if err := s.eventRepo.Delete(eventID); err != nil {
+		return err
+	}
+
+	s.calendarService.EnqueueCopyDeletions(copies)
+	return nil
 }
 
 // GetUserEvents gets all events for a user (created + participating)
-func (s *EventService) GetUserEvents(userID int64, status string) ([]*models.EventResponse, error) {
+// When a window is given, only events overlapping it are returned and recurring
+// events are expanded into one response per occurrence
+func (s *EventService) GetUserEvents(userID int64, status string, window *models.TimeWindow) ([]*models.EventResponse, error) {
+	// Occurrences can be canceled individually, so with a window the status
+	// filter is applied after expansion
+	queryStatus := status
+	if window != nil {
+		queryStatus = ""
+	}
+
 	// Get events created by user
-	createdEvents, err := s.eventRepo.FindByCreatorID(userID, status)
+	createdEvents, err := s.eventRepo.FindByCreatorID(userID, queryStatus)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find created events: %w", err)
 	}
 
 	// Get events user is participating in
-	participatingEvents, err := s.eventRepo.FindByParticipantID(userID, status)
+	participatingEvents, err := s.eventRepo.FindByParticipantID(userID, queryStatus)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find participating events: %w", err)
 	}
@@ -187,25 +354,42 @@ func (s *EventService) GetUserEvents(userID int64, status string) ([]*models.Eve
 	// Convert to responses
 	responses := []*models.EventResponse{}
 	for _, event := range eventMap {
+		if window != nil && !event.IsRecurring() && !window.Overlaps(event.StartTime, event.EndTime) {
+			continue
+		}
+
 		response, err := s.GetEvent(event.ID)
 		if err != nil {
 			// Log error but continue
 			fmt.Printf("Failed to load event %d: %v\n", event.ID, err)
 			continue
 		}
-		responses = append(responses, response)
-	}
 
-	// Sort by start time (newest first)
-	// Simple bubble sort for small datasets
-	for i := 0; i < len(responses); i++ {
-		for j := i + 1; j < len(responses); j++ {
-			if responses[i].StartTime.Before(responses[j].StartTime) {
-				responses[i], responses[j] = responses[j], responses[i]
+		if window == nil || !event.IsRecurring() {
+			if status == "" || string(response.Status) == status {
+				responses = append(responses, response)
+			}
+			continue
+		}
+
+		occurrences, err := s.expandOccurrences(event, response, window)
+		if err != nil {
+			// Log error but continue
+			fmt.Printf("Failed to expand event %d: %v\n", event.ID, err)
+			continue
+		}
+		for _, occurrence := range occurrences {
+			if status == "" || string(occurrence.Status) == status {
+				responses = append(responses, occurrence)
 			}
 		}
 	}
 
+	// Sort by start time (newest first)
+	sort.SliceStable(responses, func(i, j int) bool {
+		return responses[i].StartTime.After(responses[j].StartTime)
+	})
+
 	return responses, nil
 }
 
@@ -228,8 +412,115 @@ func (s *EventService) AddParticipant(eventID, userID, participantID int64) erro
 		return fmt.Errorf("participant user not found")
 	}
 
+	if err := s.checkNotBlocked(userID, participantID); err != nil {
+		return err
+	}
+
 	// Add participant
-	return s.eventRepo.AddParticipant(eventID, participantID)
+	if err := s.eventRepo.AddParticipant(eventID, participantID); err != nil {
+		return err
+	}
+
+	s.recordParticipantHistory(eventID, userID, participantID, models.HistoryChangeParticipantAdded)
+	s.publishInvite(event, userID, []int64{participantID})
+	s.notifyUsers([]int64{participantID}, event, userID, models.NotificationEventInvited, eventNotificationData(event))
+	return nil
+}
+
+// InviteFriends adds friends of the creator to an event as pending participants
+// Friends who already participate are skipped; returns the friends newly invited
+func (s *EventService) InviteFriends(eventID, userID int64, req *models.InviteFriendsRequest) (*models.InviteFriendsResponse, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("event not found")
+	}
+
+	if event.CreatorID != userID {
+		return nil, fmt.Errorf("only creator can invite friends")
+	}
+
+	if event.Status == models.EventStatusCanceled || event.Status == models.EventStatusDone {
+		return nil, fmt.Errorf("cannot invite friends to %s event", event.Status)
+	}
+
+	// Blocking removes friendships, so friends are never blocked
+	for _, friendID := range req.FriendIDs {
+		isFriend, err := s.friendRepo.AreFriends(userID, friendID)
+		if err != nil {
+			return nil, err
+		}
+		if !isFriend {
+			return nil, fmt.Errorf("user %d is not your friend", friendID)
+		}
+	}
+
+	invited := []int64{}
+	for _, friendID := range uniqueIDs(req.FriendIDs) {
+		isParticipant, err := s.inviteRepo.IsUserParticipant(eventID, friendID)
+		if err != nil {
+			return nil, err
+		}
+		if isParticipant {
+			continue
+		}
+
+		if err := s.inviteRepo.AddParticipantWithDetails(eventID, friendID, userID, models.InviteMethodFriend); err != nil {
+			return nil, err
+		}
+		s.recordParticipantHistory(eventID, userID, friendID, models.HistoryChangeParticipantAdded)
+		invited = append(invited, friendID)
+	}
+
+	s.publishInvite(event, userID, invited)
+	s.notifyUsers(invited, event, userID, models.NotificationEventInvited, eventNotificationData(event))
+
+	return &models.InviteFriendsResponse{Invited: invited}, nil
+}
+
+// uniqueIDs returns ids without duplicates, keeping the first occurrence order
+func uniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	unique := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// checkNotBlocked rejects adding a user to an event when either user blocked the other
+func (s *EventService) checkNotBlocked(userID, otherID int64) error {
+	blocked, err := s.friendRepo.IsBlocked(userID, otherID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return fmt.Errorf("cannot add this user to your event")
+	}
+	return nil
+}
+
+// checkCanMessage rejects chat messages from users the event creator has blocked, or who blocked the creator
+func (s *EventService) checkCanMessage(eventID, userID int64) error {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return fmt.Errorf("event not found")
+	}
+
+	if event.CreatorID == userID {
+		return nil
+	}
+
+	blocked, err := s.friendRepo.IsBlocked(event.CreatorID, userID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return fmt.Errorf("you cannot send messages in this event")
+	}
+	return nil
 }
 
 // RemoveParticipant removes a participant from an event
@@ -246,7 +537,12 @@ func (s *EventService) RemoveParticipant(eventID, userID, participantID int64) e
 	}
 
 	// Remove participant
-	return s.eventRepo.RemoveParticipant(eventID, participantID)
+	if err := s.eventRepo.RemoveParticipant(eventID, participantID); err != nil {
+		return err
+	}
+
+	s.recordParticipantHistory(eventID, userID, participantID, models.HistoryChangeParticipantRemoved)
+	return nil
 }
 
 // ConfirmParticipation confirms a user's participation in an event
@@ -262,7 +558,8 @@ func (s *EventService) ConfirmParticipation(eventID, userID int64) error {
 }
 
 // ConfirmEvent changes event status to CONFIRMED
-func (s *EventService) ConfirmEvent(eventID, userID int64) error {
+// If the event has a time poll, the chosen (or winning) slot becomes the event time
+func (s *EventService) ConfirmEvent(eventID, userID int64, req *models.ConfirmEventRequest) error {
 	// Get event
 	event, err := s.eventRepo.FindByID(eventID)
 	if err != nil {
@@ -274,10 +571,69 @@ func (s *EventService) ConfirmEvent(eventID, userID int64) error {
 		return fmt.Errorf("only creator can confirm event")
 	}
 
+	before := *event
+
+	// Apply poll slot
+	slot, err := s.selectPollSlot(eventID, req)
+	if err != nil {
+		return err
+	}
+	if slot != nil {
+		// Poll slots have times, so an all-day event becomes a timed one
+		event.StartTime = slot.StartTime
+		event.EndTime = slot.EndTime
+		event.AllDay = false
+	}
+
 	// Update status
 	status := models.EventStatusConfirmed
 	event.Status = status
-	return s.eventRepo.Update(event)
+	if err := s.eventRepo.Update(event); err != nil {
+		return err
+	}
+
+	var metadata map[string]string
+	if slot != nil {
+		metadata = map[string]string{"slot_id": strconv.FormatInt(slot.ID, 10)}
+	}
+	changes := diffEvents(&before, event)
+	s.recordHistory(eventID, userID, models.HistoryChangeConfirmed, changes, metadata)
+	if len(changes) > 0 {
+		s.resetReminders(eventID)
+		if notificationType, ok := notificationTypeForChanges(changes); ok {
+			s.notifyMembers(event, userID, notificationType, eventNotificationData(event))
+		}
+		s.calendarService.EnqueueEventChange(eventID)
+	}
+	return nil
+}
+
+// selectPollSlot returns the requested slot, or the winning slot when none is requested
+func (s *EventService) selectPollSlot(eventID int64, req *models.ConfirmEventRequest) (*models.TimeSlot, error) {
+	slots, err := s.pollRepo.FindSlotsByEventID(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req != nil && req.SlotID != nil {
+		for _, slot := range slots {
+			if slot.ID == *req.SlotID {
+				return slot, nil
+			}
+		}
+		return nil, fmt.Errorf("slot not found")
+	}
+
+	if len(slots) == 0 {
+		return nil, nil
+	}
+
+	votes, err := s.pollRepo.FindVotesByEventID(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	return tallySlots(slots, votes)[0].Slot, nil
 }
 
 // CancelEvent changes event status to CANCELED
@@ -299,9 +655,18 @@ func (s *EventService) CancelEvent(eventID, userID int64) error {
 	}
 
 	// Update status
+	before := *event
 	status := models.EventStatusCanceled
 	event.Status = status
-	return s.eventRepo.Update(event)
+	if err := s.eventRepo.Update(event); err != nil {
+		return err
+	}
+
+	s.recordHistory(eventID, userID, models.HistoryChangeCanceled, diffEvents(&before, event), nil)
+	s.resetReminders(eventID)
+	s.notifyMembers(event, userID, models.NotificationEventCanceled, eventNotificationData(event))
+	s.calendarService.EnqueueEventChange(eventID)
+	return nil
 }
 
 // MarkEventDone marks event as done (typically called after end_time)
@@ -327,9 +692,117 @@ func (s *EventService) MarkEventDone(eventID, userID int64) error {
 	}
 
 	// Update status
+	before := *event
 	status := models.EventStatusDone
 	event.Status = status
-	return s.eventRepo.Update(event)
+	if err := s.eventRepo.Update(event); err != nil {
+		return err
+	}
+
+	s.recordHistory(eventID, userID, models.HistoryChangeDone, diffEvents(&before, event), nil)
+
+	// Accepted participants who never checked in are no-shows
+	// (recurring events keep attendance per occurrence, recorded by the creator)
+	if !event.IsRecurring() {
+		noShows, err := s.attendanceRepo.MarkNoShows(eventID)
+		if err != nil {
+			// Log error but continue
+			fmt.Printf("Warning: failed to mark no-shows for event %d: %v\n", eventID, err)
+		}
+		for _, participantID := range noShows {
+			refreshReliabilityScore(s.attendanceRepo, participantID)
+		}
+	}
+
+	return nil
+}
+
+// resetReminders drops unsent reminders of an event after its time or status
+// changed; the reminder scheduler plans them again from the new state
+func (s *EventService) resetReminders(eventID int64) {
+	if err := s.reminderRepo.DeletePendingByEventID(eventID); err != nil {
+		fmt.Printf("Warning: failed to reset reminders for event %d: %v\n", eventID, err)
+	}
+}
+
+// publishInvite notifies newly added participants through the EVENTS stream
+func (s *EventService) publishInvite(event *models.Event, inviterID int64, userIDs []int64) {
+	if len(userIDs) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(&models.EventInvitedMessage{
+		EventID:     event.ID,
+		Title:       event.Title,
+		InviterID:   inviterID,
+		InviterName: s.userName(inviterID),
+		UserIDs:     userIDs,
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to marshal invite for event %d: %v\n", event.ID, err)
+		return
+	}
+
+	if _, err := s.nats.Publish(InviteSubject, data); err != nil {
+		// Log error but continue
+		fmt.Printf("Warning: failed to publish invite for event %d: %v\n", event.ID, err)
+	}
+}
+
+// notifyMembers adds an inbox notification for every member of an event except the actor
+func (s *EventService) notifyMembers(event *models.Event, actorID int64, notificationType models.NotificationType, data map[string]string) {
+	participantIDs, err := s.eventRepo.FindParticipants(event.ID)
+	if err != nil {
+		fmt.Printf("Warning: failed to find participants of event %d for notifications: %v\n", event.ID, err)
+		return
+	}
+
+	recipients := []int64{}
+	for _, memberID := range append([]int64{event.CreatorID}, participantIDs...) {
+		if memberID != actorID {
+			recipients = append(recipients, memberID)
+		}
+	}
+
+	s.notifyUsers(recipients, event, actorID, notificationType, data)
+}
+
+// notifyUsers adds an inbox notification about an event for the given users
+func (s *EventService) notifyUsers(userIDs []int64, event *models.Event, actorID int64, notificationType models.NotificationType, data map[string]string) {
+	if name := s.userName(actorID); name != "" {
+		data["actor_name"] = name
+	}
+	s.notificationService.Notify(userIDs, notificationType, &event.ID, &actorID, data)
+}
+
+// userName returns a user's display name, or "" when unknown
+func (s *EventService) userName(userID int64) string {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user.Name == nil {
+		return ""
+	}
+	return *user.Name
+}
+
+// notificationTypeForChanges returns the inbox notification for an event update, if it warrants one
+// Only cancellations and time changes are notified; other edits show up in history
+func notificationTypeForChanges(changes []fieldChange) (models.NotificationType, bool) {
+	timeChanged := false
+	for _, change := range changes {
+		switch change.Field {
+		case "status":
+			if change.NewValue == string(models.EventStatusCanceled) {
+				return models.NotificationEventCanceled, true
+			}
+		case "start_time", "end_time", "all_day":
+			timeChanged = true
+		}
+	}
+
+	if timeChanged {
+		return models.NotificationEventTimeChanged, true
+	}
+	return "", false
 }
 
 // IsUserEventMember checks if a user is a member (creator or participant) of an event
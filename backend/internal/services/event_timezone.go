@@ -0,0 +1,79 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+// maxZoneOffset is the largest UTC offset of any time zone (UTC+14, UTC-12)
+// All-day events begin at local midnight, so their start differs from the stored
+// UTC midnight by up to this much
+const maxZoneOffset = 14 * time.Hour
+
+// viewerZoneProfile selects the viewer's profile timezone in the tz query parameter
+const viewerZoneProfile = "viewer"
+
+// eventTimezone returns the zone of a new event: the requested one, or else the creator's profile timezone
+func (s *EventService) eventTimezone(creatorID int64, requested *string) (string, error) {
+	if requested != nil && *requested != "" {
+		if err := validateTimezone(*requested); err != nil {
+			return "", err
+		}
+		return *requested, nil
+	}
+
+	creator, err := s.userRepo.FindByID(creatorID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find creator: %w", err)
+	}
+	if validateTimezone(creator.Timezone) != nil {
+		return "UTC", nil
+	}
+	return creator.Timezone, nil
+}
+
+// allDayRange converts the start and end of an all-day event to the UTC midnights of their dates
+// The end date is exclusive; an end on or before the start date makes a single-day event
+func allDayRange(start, end time.Time) (time.Time, time.Time) {
+	startDate := models.DateOf(start)
+	endDate := models.DateOf(end)
+	if !endDate.After(startDate) {
+		endDate = startDate.AddDate(0, 0, 1)
+	}
+	return startDate, endDate
+}
+
+// allDayExDates converts EXDATE values of an all-day event to the UTC midnights of their dates
+func allDayExDates(exdates []time.Time) []time.Time {
+	if len(exdates) == 0 {
+		return exdates
+	}
+	dates := make([]time.Time, len(exdates))
+	for i, exdate := range exdates {
+		dates[i] = models.DateOf(exdate)
+	}
+	return dates
+}
+
+// ViewerZone resolves the tz query parameter of event reads
+// Empty keeps times as stored (UTC), "viewer" uses the viewer's profile timezone,
+// anything else must be an IANA zone
+func (s *EventService) ViewerZone(userID int64, tz string) (*time.Location, error) {
+	switch tz {
+	case "":
+		return nil, nil
+	case viewerZoneProfile:
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find user: %w", err)
+		}
+		return models.LoadZone(user.Timezone), nil
+	}
+
+	if err := validateTimezone(tz); err != nil {
+		return nil, err
+	}
+	return time.LoadLocation(tz)
+}
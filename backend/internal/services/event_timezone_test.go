@@ -0,0 +1,111 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+func TestAllDayRange(t *testing.T) {
+	seoul := time.FixedZone("KST", 9*60*60)
+
+	// The date is taken in the offset the client sent, not in UTC
+	start, end := allDayRange(time.Date(2026, 3, 1, 0, 0, 0, 0, seoul), time.Date(2026, 3, 3, 0, 0, 0, 0, seoul))
+	if !start.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected 2026-03-01 - 2026-03-03 at UTC midnight, got %v - %v", start, end)
+	}
+
+	// An end on the start date makes a single-day event
+	start, end = allDayRange(time.Date(2026, 3, 1, 0, 0, 0, 0, seoul), time.Date(2026, 3, 1, 23, 59, 0, 0, seoul))
+	if !end.Equal(start.AddDate(0, 0, 1)) {
+		t.Errorf("Expected a one-day event, got %v - %v", start, end)
+	}
+}
+
+func TestIsOccurrenceKeepsWallClockAcrossDST(t *testing.T) {
+	// Mondays 09:00 in New York; DST starts on 2026-03-08
+	rule := "FREQ=WEEKLY"
+	event := &models.Event{
+		StartTime:      time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC),
+		EndTime:        time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC),
+		Timezone:       "America/New_York",
+		RecurrenceRule: &rule,
+	}
+
+	after := time.Date(2026, 3, 9, 13, 0, 0, 0, time.UTC) // 09:00 EDT
+	if ok, err := isOccurrence(event, after); err != nil || !ok {
+		t.Errorf("Expected 09:00 EDT to be an occurrence, got %v, %v", ok, err)
+	}
+	if ok, _ := isOccurrence(event, after.Add(time.Hour)); ok {
+		t.Error("Expected 10:00 EDT not to be an occurrence")
+	}
+
+	// Without a zone the series keeps its UTC time
+	event.Timezone = "UTC"
+	if ok, _ := isOccurrence(event, after.Add(time.Hour)); !ok {
+		t.Error("Expected a UTC series to stay at 14:00 UTC")
+	}
+}
+
+func TestApplyEventUpdateAllDay(t *testing.T) {
+	// 2026-03-01 00:30 in Seoul is still February 28 in UTC
+	event := &models.Event{
+		StartTime: time.Date(2026, 2, 28, 15, 30, 0, 0, time.UTC),
+		EndTime:   time.Date(2026, 2, 28, 16, 30, 0, 0, time.UTC),
+		Timezone:  "Asia/Seoul",
+	}
+
+	allDay := true
+	if err := applyEventUpdate(event, &models.UpdateEventRequest{AllDay: &allDay}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !event.StartTime.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) || !event.EndTime.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the Seoul date 2026-03-01, got %v - %v", event.StartTime, event.EndTime)
+	}
+
+	invalid := "Mars/Olympus"
+	if err := applyEventUpdate(event, &models.UpdateEventRequest{Timezone: &invalid}); err == nil {
+		t.Error("Expected error for an unknown timezone")
+	}
+}
+
+func TestAllDayStartsAtLocalMidnight(t *testing.T) {
+	event := &models.Event{
+		StartTime: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		AllDay:    true,
+	}
+
+	seoul, _ := time.LoadLocation("Asia/Seoul")
+	start := event.StartIn(event.StartTime, seoul)
+	if !start.Equal(time.Date(2026, 2, 28, 15, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected midnight in Seoul, got %v", start)
+	}
+
+	planned := planReminderTimes(start, []int64{60}, time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC))
+	if len(planned) != 1 || !planned[0].RemindAt.Equal(time.Date(2026, 2, 28, 14, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected a reminder at 23:00 in Seoul, got %+v", planned)
+	}
+}
+
+func TestEventResponseIn(t *testing.T) {
+	seoul, _ := time.LoadLocation("Asia/Seoul")
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	timed := &models.EventResponse{StartTime: start, EndTime: start.Add(time.Hour), OccurrenceStart: &start}
+	timed.In(seoul)
+	if timed.StartTime.Format(time.RFC3339) != "2026-03-01T09:00:00+09:00" || timed.OccurrenceStart.Location() != seoul {
+		t.Errorf("Expected times in Seoul, got %v, %v", timed.StartTime, timed.OccurrenceStart)
+	}
+	if start.Location() != time.UTC {
+		t.Error("Expected the original occurrence start to be left untouched")
+	}
+
+	allDay := &models.EventResponse{StartTime: start, EndTime: start.AddDate(0, 0, 1), AllDay: true}
+	allDay.SetDates()
+	allDay.In(seoul)
+	if allDay.StartTime.Location() != time.UTC || allDay.StartDate != "2026-03-01" || allDay.EndDate != "2026-03-02" {
+		t.Errorf("Expected all-day dates to be kept, got %+v", allDay)
+	}
+}
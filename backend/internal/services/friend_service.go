@@ -0,0 +1,182 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/repositories"
+)
+
+// FriendService handles friend requests, friend lists and blocking
+type FriendService struct {
+	friendRepo          *repositories.FriendRepository
+	userRepo            *repositories.UserRepository
+	notificationService *NotificationService
+}
+
+// NewFriendService creates a new friend service
+func NewFriendService(
+	friendRepo *repositories.FriendRepository,
+	userRepo *repositories.UserRepository,
+	notificationService *NotificationService,
+) *FriendService {
+	return &FriendService{
+		friendRepo:          friendRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+	}
+}
+
+// SendRequest sends a friend request from userID to targetID
+// If the target already asked the user, their request is accepted instead.
+// Returns the resulting friendship status.
+func (s *FriendService) SendRequest(userID, targetID int64) (models.FriendshipStatus, error) {
+	if err := s.checkTarget(userID, targetID); err != nil {
+		return "", err
+	}
+
+	blocked, err := s.friendRepo.IsBlocked(userID, targetID)
+	if err != nil {
+		return "", err
+	}
+	if blocked {
+		return "", fmt.Errorf("cannot send a friend request to this user")
+	}
+
+	outgoing, err := s.friendRepo.Find(userID, targetID)
+	if err != nil {
+		return "", err
+	}
+	incoming, err := s.friendRepo.Find(targetID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	accept, err := friendRequestAction(outgoing, incoming)
+	if err != nil {
+		return "", err
+	}
+	if accept {
+		if err := s.AcceptRequest(userID, targetID); err != nil {
+			return "", err
+		}
+		return models.FriendshipStatusAccepted, nil
+	}
+
+	if err := s.friendRepo.CreateRequest(userID, targetID); err != nil {
+		return "", err
+	}
+
+	s.notify(targetID, userID, models.NotificationFriendRequested)
+	return models.FriendshipStatusPending, nil
+}
+
+// friendRequestAction decides what a new request does given the existing rows
+// between the two users: accept the other user's pending request, or create one
+func friendRequestAction(outgoing, incoming *models.Friendship) (bool, error) {
+	if outgoing != nil {
+		switch outgoing.Status {
+		case models.FriendshipStatusAccepted:
+			return false, fmt.Errorf("already friends")
+		case models.FriendshipStatusPending:
+			return false, fmt.Errorf("friend request already sent")
+		}
+	}
+
+	if incoming != nil && incoming.Status == models.FriendshipStatusPending {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// AcceptRequest accepts the friend request requesterID sent to userID
+func (s *FriendService) AcceptRequest(userID, requesterID int64) error {
+	if err := s.friendRepo.Accept(requesterID, userID); err != nil {
+		return err
+	}
+
+	s.notify(requesterID, userID, models.NotificationFriendAccepted)
+	return nil
+}
+
+// DeclineRequest declines the friend request requesterID sent to userID
+func (s *FriendService) DeclineRequest(userID, requesterID int64) error {
+	return s.friendRepo.DeleteRequest(requesterID, userID)
+}
+
+// CancelRequest cancels the friend request userID sent to targetID
+func (s *FriendService) CancelRequest(userID, targetID int64) error {
+	return s.friendRepo.DeleteRequest(userID, targetID)
+}
+
+// RemoveFriend ends a friendship for both users
+func (s *FriendService) RemoveFriend(userID, friendID int64) error {
+	return s.friendRepo.DeleteFriendship(userID, friendID)
+}
+
+// ListFriends returns the user's friends
+func (s *FriendService) ListFriends(userID int64) ([]*models.Friend, error) {
+	return s.friendRepo.FindOutgoing(userID, models.FriendshipStatusAccepted)
+}
+
+// ListRequests returns the pending friend requests the user received and sent
+func (s *FriendService) ListRequests(userID int64) (*models.FriendRequestsResponse, error) {
+	incoming, err := s.friendRepo.FindIncoming(userID, models.FriendshipStatusPending)
+	if err != nil {
+		return nil, err
+	}
+
+	outgoing, err := s.friendRepo.FindOutgoing(userID, models.FriendshipStatusPending)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.FriendRequestsResponse{
+		Incoming: incoming,
+		Outgoing: outgoing,
+	}, nil
+}
+
+// ListBlocked returns the users the user has blocked
+func (s *FriendService) ListBlocked(userID int64) ([]*models.Friend, error) {
+	return s.friendRepo.FindOutgoing(userID, models.FriendshipStatusBlocked)
+}
+
+// Block blocks a user, removing any friendship or pending request between the two
+func (s *FriendService) Block(userID, targetID int64) error {
+	if err := s.checkTarget(userID, targetID); err != nil {
+		return err
+	}
+
+	return s.friendRepo.Block(userID, targetID)
+}
+
+// Unblock lifts the user's block on targetID
+func (s *FriendService) Unblock(userID, targetID int64) error {
+	return s.friendRepo.Unblock(userID, targetID)
+}
+
+// checkTarget verifies the other user of a request exists and is not the user
+func (s *FriendService) checkTarget(userID, targetID int64) error {
+	if userID == targetID {
+		return fmt.Errorf("cannot add yourself as a friend")
+	}
+
+	target, err := s.userRepo.FindByID(targetID)
+	if err != nil || !target.IsActive() {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
+
+// notify sends a friend notification to userID about actorID
+func (s *FriendService) notify(userID, actorID int64, notificationType models.NotificationType) {
+	data := map[string]string{}
+	if actor, err := s.userRepo.FindByID(actorID); err == nil && actor.Name != nil {
+		data["actor_name"] = *actor.Name
+	}
+
+	s.notificationService.Notify([]int64{userID}, notificationType, nil, &actorID, data)
+}
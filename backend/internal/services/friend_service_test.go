@@ -0,0 +1,52 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+func TestFriendRequestAction(t *testing.T) {
+	row := func(status models.FriendshipStatus) *models.Friendship {
+		return &models.Friendship{UserID: 1, FriendID: 2, Status: status}
+	}
+
+	tests := []struct {
+		name       string
+		outgoing   *models.Friendship
+		incoming   *models.Friendship
+		wantAccept bool
+		wantErr    bool
+	}{
+		{name: "no relation creates a request"},
+		{name: "pending incoming request is accepted", incoming: row(models.FriendshipStatusPending), wantAccept: true},
+		{name: "already friends", outgoing: row(models.FriendshipStatusAccepted), incoming: row(models.FriendshipStatusAccepted), wantErr: true},
+		{name: "request already sent", outgoing: row(models.FriendshipStatusPending), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accept, err := friendRequestAction(tt.outgoing, tt.incoming)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if accept != tt.wantAccept {
+				t.Errorf("Expected accept %v, got %v", tt.wantAccept, accept)
+			}
+		})
+	}
+}
+
+func TestUniqueIDs(t *testing.T) {
+	got := uniqueIDs([]int64{3, 1, 3, 2, 1})
+	want := []int64{3, 1, 2}
+
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, got)
+		}
+	}
+}
@@ -10,19 +10,23 @@ import (
 
 // InviteService handles invite link business logic
 type InviteService struct {
-	inviteRepo *repositories.InviteRepository
-	eventRepo  *repositories.EventRepository
-	userRepo   *repositories.UserRepository
-	baseURL    string
+	inviteRepo          *repositories.InviteRepository
+	eventRepo           *repositories.EventRepository
+	userRepo            *repositories.UserRepository
+	friendRepo          *repositories.FriendRepository
+	notificationService *NotificationService
+	baseURL             string
 }
 
 // NewInviteService creates a new invite service
-func NewInviteService(inviteRepo *repositories.InviteRepository, eventRepo *repositories.EventRepository, userRepo *repositories.UserRepository, baseURL string) *InviteService {
+func NewInviteService(inviteRepo *repositories.InviteRepository, eventRepo *repositories.EventRepository, userRepo *repositories.UserRepository, friendRepo *repositories.FriendRepository, notificationService *NotificationService, baseURL string) *InviteService {
 	return &InviteService{
-		inviteRepo: inviteRepo,
-		eventRepo:  eventRepo,
-		userRepo:   userRepo,
-		baseURL:    baseURL,
+		inviteRepo:          inviteRepo,
+		eventRepo:           eventRepo,
+		userRepo:            userRepo,
+		friendRepo:          friendRepo,
+		notificationService: notificationService,
+		baseURL:             baseURL,
 	}
 }
 
@@ -108,16 +112,26 @@ func (s *InviteService) GetInviteInfo(code string, userID int64) (*models.Invite
 		action = "already_joined"
 	}
 
+	location := ""
+	if event.Location != nil {
+		location = *event.Location
+	}
+
+	creatorName := ""
+	if creator.Name != nil {
+		creatorName = *creator.Name
+	}
+
 	return &models.InviteInfoResponse{
 		Event: &models.EventSummary{
 			ID:        event.ID,
 			Title:     event.Title,
-			StartTime: event.StartTime,
-			Location:  event.Location,
+			StartTime: &event.StartTime,
+			Location:  location,
 		},
 		Creator: &models.UserSummary{
 			ID:   creator.ID,
-			Name: creator.Name,
+			Name: creatorName,
 		},
 		Action: action,
 	}, nil
@@ -148,6 +162,19 @@ func (s *InviteService) JoinViaInvite(code string, userID int64) (*models.JoinEv
 		return nil, fmt.Errorf("you are already a participant of this event")
 	}
 
+	// Users blocked by (or blocking) the event creator cannot join through a shared link
+	event, err := s.eventRepo.FindByID(link.EventID)
+	if err != nil {
+		return nil, fmt.Errorf("event not found")
+	}
+	blocked, err := s.friendRepo.IsBlocked(event.CreatorID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, fmt.Errorf("you cannot join this event")
+	}
+
 	// Add as participant
 	err = s.inviteRepo.AddParticipantWithDetails(link.EventID, userID, link.CreatedBy, models.InviteMethodLink)
 	if err != nil {
@@ -160,12 +187,35 @@ func (s *InviteService) JoinViaInvite(code string, userID int64) (*models.JoinEv
 		fmt.Printf("Warning: failed to increment use count: %v\n", err)
 	}
 
+	s.notifyJoined(link, userID)
+
 	return &models.JoinEventResponse{
 		Message: "이벤트에 참가했습니다",
 		EventID: link.EventID,
 	}, nil
 }
 
+// notifyJoined tells the event creator, and the link creator if different, that a user joined via link
+func (s *InviteService) notifyJoined(link *models.InviteLink, userID int64) {
+	event, err := s.eventRepo.FindByID(link.EventID)
+	if err != nil {
+		fmt.Printf("Warning: failed to load event %d for notification: %v\n", link.EventID, err)
+		return
+	}
+
+	recipients := []int64{event.CreatorID}
+	if link.CreatedBy != event.CreatorID {
+		recipients = append(recipients, link.CreatedBy)
+	}
+
+	data := eventNotificationData(event)
+	if user, err := s.userRepo.FindByID(userID); err == nil && user.Name != nil {
+		data["actor_name"] = *user.Name
+	}
+
+	s.notificationService.Notify(recipients, models.NotificationParticipantJoined, &event.ID, &userID, data)
+}
+
 // AcceptInvite accepts an event invitation
 func (s *InviteService) AcceptInvite(eventID, userID int64) error {
 	// Check if user is a participant
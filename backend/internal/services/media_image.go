@@ -0,0 +1,271 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+// maxImagePixels bounds decoded image size so a small file cannot expand into gigabytes of pixels
+const maxImagePixels = 40_000_000
+
+// ErrUnsupportedMediaType is returned for uploads that are not JPEG, PNG or GIF images
+var ErrUnsupportedMediaType = errors.New("unsupported media type: only JPEG, PNG and GIF images are allowed")
+
+// supportedImageTypes are the content types accepted for upload
+var supportedImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// processedImage is an upload ready to be stored
+type processedImage struct {
+	data        []byte
+	contentType string
+	width       int
+	height      int
+	thumbnail   []byte
+}
+
+// thumbnailContentType returns the format thumbnails of contentType are encoded in
+func thumbnailContentType(contentType string) string {
+	if contentType == "image/jpeg" {
+		return "image/jpeg"
+	}
+	return "image/png"
+}
+
+// processImage checks an upload by its content (not the declared type) and prepares it for storage
+// JPEG and PNG images are re-encoded, which drops EXIF (GPS location, camera
+// details) and other metadata; the EXIF orientation is applied first so the
+// picture keeps facing the right way. GIFs are kept as-is to preserve
+// animation, as the format carries no EXIF.
+func processImage(data []byte, maxDimension, thumbnailSize int) (*processedImage, error) {
+	contentType := http.DetectContentType(data)
+	if !supportedImageTypes[contentType] {
+		return nil, ErrUnsupportedMediaType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxImagePixels {
+		return nil, fmt.Errorf("image dimensions are too large")
+	}
+
+	var img image.Image
+	switch contentType {
+	case "image/jpeg":
+		img, err = jpeg.Decode(bytes.NewReader(data))
+	case "image/png":
+		img, err = png.Decode(bytes.NewReader(data))
+	case "image/gif":
+		img, err = gif.Decode(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+
+	result := &processedImage{contentType: contentType}
+	if contentType == "image/gif" {
+		result.data = data
+		result.width, result.height = config.Width, config.Height
+	} else {
+		if contentType == "image/jpeg" {
+			img = applyOrientation(toRGBA(img), jpegOrientation(data))
+		}
+		img = resizeToFit(img, maxDimension)
+		if result.data, err = encodeImage(img, contentType); err != nil {
+			return nil, err
+		}
+		result.width, result.height = img.Bounds().Dx(), img.Bounds().Dy()
+	}
+
+	if result.thumbnail, err = encodeImage(resizeToFit(img, thumbnailSize), thumbnailContentType(contentType)); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// encodeImage encodes img as JPEG or PNG
+func encodeImage(img image.Image, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if contentType == "image/jpeg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// toRGBA converts img to an RGBA image with its origin at (0, 0)
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Rect, img, bounds.Min, draw.Src)
+	return rgba
+}
+
+// resizeToFit scales img down (never up) so neither side exceeds maxSide, averaging source pixels
+func resizeToFit(img image.Image, maxSide int) image.Image {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if maxSide <= 0 || (srcW <= maxSide && srcH <= maxSide) {
+		return img
+	}
+
+	dstW, dstH := maxSide, maxSide
+	if srcW >= srcH {
+		dstH = max(1, srcH*maxSide/srcW)
+	} else {
+		dstW = max(1, srcW*maxSide/srcH)
+	}
+
+	src := toRGBA(img)
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for dy := 0; dy < dstH; dy++ {
+		y0, y1 := dy*srcH/dstH, max((dy+1)*srcH/dstH, dy*srcH/dstH+1)
+		for dx := 0; dx < dstW; dx++ {
+			x0, x1 := dx*srcW/dstW, max((dx+1)*srcW/dstW, dx*srcW/dstW+1)
+
+			var sum [4]int
+			for y := y0; y < y1; y++ {
+				row := src.Pix[y*src.Stride+x0*4 : y*src.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					sum[0] += int(row[i])
+					sum[1] += int(row[i+1])
+					sum[2] += int(row[i+2])
+					sum[3] += int(row[i+3])
+				}
+			}
+
+			count := (x1 - x0) * (y1 - y0)
+			offset := dy*dst.Stride + dx*4
+			for c := 0; c < 4; c++ {
+				dst.Pix[offset+c] = uint8(sum[c] / count)
+			}
+		}
+	}
+	return dst
+}
+
+// applyOrientation rotates/flips img according to an EXIF orientation value (1-8)
+func applyOrientation(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	srcW, srcH := img.Rect.Dx(), img.Rect.Dy()
+	dstW, dstH := srcW, srcH
+	if orientation >= 5 {
+		dstW, dstH = srcH, srcW
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		for x := 0; x < dstW; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // Mirrored horizontally
+				sx, sy = srcW-1-x, y
+			case 3: // Rotated 180
+				sx, sy = srcW-1-x, srcH-1-y
+			case 4: // Mirrored vertically
+				sx, sy = x, srcH-1-y
+			case 5: // Transposed
+				sx, sy = y, x
+			case 6: // Needs 90 clockwise rotation
+				sx, sy = y, srcH-1-x
+			case 7: // Transversed
+				sx, sy = srcW-1-y, srcH-1-x
+			case 8: // Needs 90 counter-clockwise rotation
+				sx, sy = srcW-1-y, x
+			}
+			copy(dst.Pix[y*dst.Stride+x*4:y*dst.Stride+x*4+4], img.Pix[sy*img.Stride+sx*4:sy*img.Stride+sx*4+4])
+		}
+	}
+	return dst
+}
+
+// jpegOrientation reads the EXIF orientation tag of a JPEG, 1 (normal) if absent
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for pos := 2; pos+4 <= len(data); {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		if marker == 0xDA || marker == 0xD9 { // Start of scan / end of image: no more metadata
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			return 1
+		}
+
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		pos += 2 + length
+	}
+	return 1
+}
+
+// exifOrientation reads the orientation tag (0x0112) from IFD0 of a TIFF-structured EXIF block
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+	return 1
+}
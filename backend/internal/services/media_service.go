@@ -0,0 +1,354 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/repositories"
+	"github.com/khchoi-tnh/timingle/internal/storage"
+)
+
+var (
+	// ErrMediaTooLarge is returned when an upload exceeds the size limit
+	ErrMediaTooLarge = errors.New("file is too large")
+	// ErrPresignNotSupported is returned for presigned uploads when the storage backend cannot presign URLs
+	ErrPresignNotSupported = errors.New("presigned uploads are not supported by this storage backend, upload the file directly")
+)
+
+// MediaPolicy limits and shapes uploaded images
+type MediaPolicy struct {
+	MaxUploadBytes int64
+	MaxDimension   int           // Longer side of stored images; larger images are scaled down
+	ThumbnailSize  int           // Longer side of thumbnails
+	URLExpiry      time.Duration // Lifetime of presigned upload and download URLs
+}
+
+// MediaContent is a stored object to send to a client: either a redirect to a
+// presigned URL or a body to stream
+type MediaContent struct {
+	ContentType string
+	RedirectURL string
+	Body        io.ReadCloser
+}
+
+// MediaService handles image uploads for profile pictures and chat messages
+type MediaService struct {
+	mediaRepo    *repositories.MediaRepository
+	eventService *EventService
+	storage      storage.Storage
+	baseURL      string
+	policy       MediaPolicy
+}
+
+// NewMediaService creates a new media service
+func NewMediaService(
+	mediaRepo *repositories.MediaRepository,
+	eventService *EventService,
+	storage storage.Storage,
+	baseURL string,
+	policy MediaPolicy,
+) *MediaService {
+	return &MediaService{
+		mediaRepo:    mediaRepo,
+		eventService: eventService,
+		storage:      storage,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		policy:       policy,
+	}
+}
+
+// Storage keys: presigned uploads land in uploads/ and are moved to media/ once processed
+func uploadKey(id uuid.UUID) string    { return "uploads/" + id.String() }
+func mediaKey(id uuid.UUID) string     { return "media/" + id.String() }
+func thumbnailKey(id uuid.UUID) string { return "thumbnails/" + id.String() }
+
+// Upload processes and stores an image sent through the API
+func (s *MediaService) Upload(ctx context.Context, userID int64, purpose models.MediaPurpose, eventID *int64, file io.Reader) (*models.MediaResponse, error) {
+	if err := s.checkPurpose(userID, purpose, eventID); err != nil {
+		return nil, err
+	}
+
+	data, err := s.readLimited(file)
+	if err != nil {
+		return nil, err
+	}
+
+	media := &models.MediaObject{
+		ID:      uuid.New(),
+		OwnerID: userID,
+		Purpose: purpose,
+		EventID: eventID,
+		Status:  models.MediaStatusReady,
+	}
+	if err := s.store(ctx, media, data); err != nil {
+		return nil, err
+	}
+
+	if err := s.mediaRepo.Create(media); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(media), nil
+}
+
+// CreateUpload reserves a media object and returns a presigned URL to upload the file to
+// The upload must be completed with CompleteUpload before the image can be used
+func (s *MediaService) CreateUpload(ctx context.Context, userID int64, req *models.CreateUploadRequest) (*models.UploadURLResponse, error) {
+	presigner, ok := s.storage.(storage.Presigner)
+	if !ok {
+		return nil, ErrPresignNotSupported
+	}
+
+	if err := s.checkPurpose(userID, req.Purpose, req.EventID); err != nil {
+		return nil, err
+	}
+	if !supportedImageTypes[req.ContentType] {
+		return nil, ErrUnsupportedMediaType
+	}
+	if req.SizeBytes > s.policy.MaxUploadBytes {
+		return nil, ErrMediaTooLarge
+	}
+
+	media := &models.MediaObject{
+		ID:          uuid.New(),
+		OwnerID:     userID,
+		Purpose:     req.Purpose,
+		EventID:     req.EventID,
+		Status:      models.MediaStatusPending,
+		ContentType: req.ContentType,
+		SizeBytes:   req.SizeBytes,
+	}
+
+	uploadURL, err := presigner.PresignPut(ctx, uploadKey(media.ID), req.ContentType, req.SizeBytes, s.policy.URLExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload URL: %w", err)
+	}
+
+	if err := s.mediaRepo.Create(media); err != nil {
+		return nil, err
+	}
+
+	return &models.UploadURLResponse{
+		Media:     s.toResponse(media),
+		UploadURL: uploadURL,
+		Method:    http.MethodPut,
+		Headers:   map[string]string{"Content-Type": req.ContentType},
+		ExpiresAt: time.Now().Add(s.policy.URLExpiry),
+	}, nil
+}
+
+// CompleteUpload processes a file uploaded to a presigned URL
+// The file is checked by its content, since the client could have uploaded anything
+func (s *MediaService) CompleteUpload(ctx context.Context, userID int64, mediaID uuid.UUID) (*models.MediaResponse, error) {
+	media, err := s.mediaRepo.FindByID(mediaID)
+	if err != nil || media.OwnerID != userID {
+		return nil, fmt.Errorf("media not found")
+	}
+	if media.Status != models.MediaStatusPending {
+		return nil, fmt.Errorf("upload already completed")
+	}
+
+	body, err := s.storage.Get(ctx, uploadKey(media.ID))
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("file has not been uploaded yet")
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err := s.readLimited(body)
+	body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.store(ctx, media, data); err != nil {
+		return nil, err
+	}
+
+	if err := s.mediaRepo.MarkReady(media); err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.Delete(ctx, uploadKey(media.ID)); err != nil {
+		// Log error but continue
+		fmt.Printf("Warning: failed to delete raw upload %s: %v\n", media.ID, err)
+	}
+
+	return s.toResponse(media), nil
+}
+
+// GetContent returns an image (or its thumbnail) if userID may see it
+// Profile images are visible to every user, chat images to the event's members only
+func (s *MediaService) GetContent(ctx context.Context, userID int64, mediaID uuid.UUID, thumbnail bool) (*MediaContent, error) {
+	media, err := s.mediaRepo.FindByID(mediaID)
+	if err != nil || media.Status != models.MediaStatusReady {
+		return nil, fmt.Errorf("media not found")
+	}
+
+	if media.Purpose == models.MediaPurposeChat && media.OwnerID != userID {
+		isMember, err := s.eventService.IsUserEventMember(*media.EventID, userID)
+		if err != nil || !isMember {
+			return nil, fmt.Errorf("media not found")
+		}
+	}
+
+	key, contentType := mediaKey(media.ID), media.ContentType
+	if thumbnail {
+		if !media.HasThumbnail {
+			return nil, fmt.Errorf("media not found")
+		}
+		key, contentType = thumbnailKey(media.ID), thumbnailContentType(media.ContentType)
+	}
+
+	if presigner, ok := s.storage.(storage.Presigner); ok {
+		url, err := presigner.PresignGet(ctx, key, s.policy.URLExpiry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create download URL: %w", err)
+		}
+		return &MediaContent{ContentType: contentType, RedirectURL: url}, nil
+	}
+
+	body, err := s.storage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return &MediaContent{ContentType: contentType, Body: body}, nil
+}
+
+// CheckChatAttachments verifies that the image IDs of a chat message are the
+// sender's completed uploads for the event, and returns them normalized
+func (s *MediaService) CheckChatAttachments(userID, eventID int64, attachments []string) ([]string, error) {
+	if len(attachments) == 0 {
+		return nil, fmt.Errorf("image messages need at least one attachment")
+	}
+	if len(attachments) > models.MaxChatAttachments {
+		return nil, fmt.Errorf("at most %d attachments are allowed", models.MaxChatAttachments)
+	}
+
+	ids := make([]uuid.UUID, 0, len(attachments))
+	for _, attachment := range attachments {
+		id, err := uuid.Parse(attachment)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attachment ID: %s", attachment)
+		}
+		ids = append(ids, id)
+	}
+
+	objects, err := s.mediaRepo.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[uuid.UUID]*models.MediaObject, len(objects))
+	for _, media := range objects {
+		found[media.ID] = media
+	}
+
+	normalized := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !isChatAttachment(found[id], userID, eventID) {
+			return nil, fmt.Errorf("attachment not found: %s", id)
+		}
+		normalized = append(normalized, id.String())
+	}
+
+	return normalized, nil
+}
+
+// isChatAttachment reports whether media can be attached by userID to a message in eventID
+func isChatAttachment(media *models.MediaObject, userID, eventID int64) bool {
+	return media != nil &&
+		media.Status == models.MediaStatusReady &&
+		media.Purpose == models.MediaPurposeChat &&
+		media.OwnerID == userID &&
+		media.EventID != nil && *media.EventID == eventID
+}
+
+// checkPurpose validates the purpose of an upload; chat images belong to an event the user is a member of
+func (s *MediaService) checkPurpose(userID int64, purpose models.MediaPurpose, eventID *int64) error {
+	switch purpose {
+	case models.MediaPurposeAvatar:
+		if eventID != nil {
+			return fmt.Errorf("event_id is only used for chat images")
+		}
+		return nil
+	case models.MediaPurposeChat:
+		if eventID == nil {
+			return fmt.Errorf("event_id is required for chat images")
+		}
+		isMember, err := s.eventService.IsUserEventMember(*eventID, userID)
+		if err != nil {
+			return err
+		}
+		if !isMember {
+			return fmt.Errorf("user is not a member of this event")
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid purpose: %s", purpose)
+	}
+}
+
+// readLimited reads an upload, failing once it exceeds the size limit
+func (s *MediaService) readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, s.policy.MaxUploadBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	if int64(len(data)) > s.policy.MaxUploadBytes {
+		return nil, ErrMediaTooLarge
+	}
+	return data, nil
+}
+
+// store processes an image and writes it and its thumbnail, filling in the media metadata
+func (s *MediaService) store(ctx context.Context, media *models.MediaObject, data []byte) error {
+	processed, err := processImage(data, s.policy.MaxDimension, s.policy.ThumbnailSize)
+	if err != nil {
+		return err
+	}
+
+	if err := s.storage.Put(ctx, mediaKey(media.ID), bytes.NewReader(processed.data), int64(len(processed.data)), processed.contentType); err != nil {
+		return err
+	}
+	thumbnailType := thumbnailContentType(processed.contentType)
+	if err := s.storage.Put(ctx, thumbnailKey(media.ID), bytes.NewReader(processed.thumbnail), int64(len(processed.thumbnail)), thumbnailType); err != nil {
+		return err
+	}
+
+	media.ContentType = processed.contentType
+	media.SizeBytes = int64(len(processed.data))
+	media.Width = processed.width
+	media.Height = processed.height
+	media.HasThumbnail = true
+	return nil
+}
+
+// toResponse converts a media object to its API response
+func (s *MediaService) toResponse(media *models.MediaObject) *models.MediaResponse {
+	response := &models.MediaResponse{
+		ID:          media.ID,
+		Purpose:     media.Purpose,
+		EventID:     media.EventID,
+		Status:      media.Status,
+		ContentType: media.ContentType,
+		SizeBytes:   media.SizeBytes,
+		Width:       media.Width,
+		Height:      media.Height,
+		CreatedAt:   media.CreatedAt,
+	}
+	if media.Status == models.MediaStatusReady {
+		response.URL = s.baseURL + "/api/v1/media/" + media.ID.String()
+		if media.HasThumbnail {
+			response.ThumbnailURL = response.URL + "/thumbnail"
+		}
+	}
+	return response
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+// jpegWithOrientation encodes a 40x20 image (left half red, right half blue)
+// with an EXIF block holding the orientation and a GPS marker
+func jpegWithOrientation(t *testing.T, orientation uint16) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= 20 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)      // One IFD0 entry
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112) // Orientation
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)      // SHORT
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+	tiff = append(tiff, "GPS 37.5665N 126.9780E"...)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	data := buf.Bytes()
+	return append(append([]byte{0xFF, 0xD8}, app1...), data[2:]...)
+}
+
+func TestProcessImageAppliesOrientationAndStripsEXIF(t *testing.T) {
+	data := jpegWithOrientation(t, 6)
+	if got := jpegOrientation(data); got != 6 {
+		t.Fatalf("Expected orientation 6, got %d", got)
+	}
+
+	processed, err := processImage(data, 2048, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if processed.contentType != "image/jpeg" {
+		t.Errorf("Expected image/jpeg, got %s", processed.contentType)
+	}
+	if bytes.Contains(processed.data, []byte("Exif")) || bytes.Contains(processed.data, []byte("GPS")) {
+		t.Error("Expected EXIF data to be stripped")
+	}
+
+	// Rotated 90 degrees clockwise: the red left half ends up on top
+	if processed.width != 20 || processed.height != 40 {
+		t.Fatalf("Expected 20x40 after rotation, got %dx%d", processed.width, processed.height)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(processed.data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, _, b, _ := img.At(10, 5).RGBA(); r < b {
+		t.Error("Expected the top of the rotated image to be red")
+	}
+	if r, _, b, _ := img.At(10, 35).RGBA(); b < r {
+		t.Error("Expected the bottom of the rotated image to be blue")
+	}
+
+	thumbnail, err := jpeg.DecodeConfig(bytes.NewReader(processed.thumbnail))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if thumbnail.Width != 5 || thumbnail.Height != 10 {
+		t.Errorf("Expected a 5x10 thumbnail, got %dx%d", thumbnail.Width, thumbnail.Height)
+	}
+}
+
+func TestProcessImageSniffsContent(t *testing.T) {
+	if _, err := processImage([]byte("<html><script>alert(1)</script></html>"), 2048, 320); !errors.Is(err, ErrUnsupportedMediaType) {
+		t.Errorf("Expected ErrUnsupportedMediaType for HTML, got %v", err)
+	}
+	if _, err := processImage([]byte("\x89PNG\r\n\x1a\nnot really a png"), 2048, 320); err == nil {
+		t.Error("Expected an error for a corrupt PNG")
+	}
+
+	var buf bytes.Buffer
+	png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 300, 100)))
+	processed, err := processImage(buf.Bytes(), 150, 30)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if processed.contentType != "image/png" || processed.width != 150 || processed.height != 50 {
+		t.Errorf("Expected a 150x50 PNG, got %s %dx%d", processed.contentType, processed.width, processed.height)
+	}
+}
+
+func TestMediaReadLimited(t *testing.T) {
+	s := &MediaService{policy: MediaPolicy{MaxUploadBytes: 4}}
+
+	if data, err := s.readLimited(strings.NewReader("1234")); err != nil || string(data) != "1234" {
+		t.Errorf("Expected a file at the limit to be accepted, got %q %v", data, err)
+	}
+	if _, err := s.readLimited(strings.NewReader("12345")); !errors.Is(err, ErrMediaTooLarge) {
+		t.Errorf("Expected ErrMediaTooLarge, got %v", err)
+	}
+}
+
+func TestIsChatAttachment(t *testing.T) {
+	eventID, otherEventID := int64(7), int64(8)
+	ready := func(mutate func(*models.MediaObject)) *models.MediaObject {
+		media := &models.MediaObject{
+			ID:      uuid.New(),
+			OwnerID: 1,
+			Purpose: models.MediaPurposeChat,
+			EventID: &eventID,
+			Status:  models.MediaStatusReady,
+		}
+		if mutate != nil {
+			mutate(media)
+		}
+		return media
+	}
+
+	if !isChatAttachment(ready(nil), 1, eventID) {
+		t.Error("Expected the sender's ready chat image to be attachable")
+	}
+
+	invalid := map[string]*models.MediaObject{
+		"missing":     nil,
+		"pending":     ready(func(m *models.MediaObject) { m.Status = models.MediaStatusPending }),
+		"avatar":      ready(func(m *models.MediaObject) { m.Purpose = models.MediaPurposeAvatar; m.EventID = nil }),
+		"other owner": ready(func(m *models.MediaObject) { m.OwnerID = 2 }),
+		"other event": ready(func(m *models.MediaObject) { m.EventID = &otherEventID }),
+	}
+	for name, media := range invalid {
+		if isChatAttachment(media, 1, eventID) {
+			t.Errorf("%s: expected the image not to be attachable", name)
+		}
+	}
+}
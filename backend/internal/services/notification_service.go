@@ -0,0 +1,126 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/repositories"
+	ws "github.com/khchoi-tnh/timingle/internal/websocket"
+)
+
+const (
+	defaultNotificationLimit = 20
+	maxNotificationLimit     = 100
+)
+
+// NotificationService manages the in-app notification inbox
+type NotificationService struct {
+	notificationRepo *repositories.NotificationRepository
+	hub              *ws.Hub
+}
+
+// NewNotificationService creates a new notification service
+// hub may be nil (e.g. in the worker), in which case entries are only stored
+func NewNotificationService(notificationRepo *repositories.NotificationRepository, hub *ws.Hub) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		hub:              hub,
+	}
+}
+
+// Notify stores a notification for each user and delivers it to their open user channels
+// Failures are logged, not returned, so they never fail the action that caused them
+func (s *NotificationService) Notify(userIDs []int64, notificationType models.NotificationType, eventID, actorID *int64, data map[string]string) {
+	if len(userIDs) == 0 {
+		return
+	}
+
+	created, err := s.notificationRepo.CreateForUsers(userIDs, &models.Notification{
+		Type:    notificationType,
+		EventID: eventID,
+		ActorID: actorID,
+		Data:    data,
+	})
+	if err != nil {
+		// Log error but continue
+		fmt.Printf("Warning: failed to create %s notifications: %v\n", notificationType, err)
+		return
+	}
+
+	if s.hub == nil {
+		return
+	}
+
+	for _, notification := range created {
+		message, err := json.Marshal(&models.NotificationMessage{
+			Type:         "notification",
+			Notification: notification,
+		})
+		if err != nil {
+			fmt.Printf("Warning: failed to marshal notification: %v\n", err)
+			continue
+		}
+		s.hub.BroadcastToUser(notification.UserID, message)
+	}
+}
+
+// GetNotifications returns a page of a user's notifications, newest first
+// cursor is the next_cursor of the previous page, empty for the first page
+func (s *NotificationService) GetNotifications(userID int64, cursor string, limit int, unreadOnly bool) (*models.NotificationPage, error) {
+	if limit <= 0 {
+		limit = defaultNotificationLimit
+	}
+	if limit > maxNotificationLimit {
+		limit = maxNotificationLimit
+	}
+
+	var beforeID int64
+	if cursor != "" {
+		var err error
+		beforeID, err = strconv.ParseInt(cursor, 10, 64)
+		if err != nil || beforeID <= 0 {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+	}
+
+	// Fetch one extra row to know whether another page exists
+	notifications, err := s.notificationRepo.FindByUserID(userID, beforeID, limit+1, unreadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	unread, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.NotificationPage{UnreadCount: unread}
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+		page.NextCursor = strconv.FormatInt(notifications[limit-1].ID, 10)
+	}
+	page.Notifications = notifications
+
+	return page, nil
+}
+
+// MarkRead marks one of the user's notifications as read
+func (s *NotificationService) MarkRead(userID, notificationID int64) error {
+	return s.notificationRepo.MarkRead(userID, notificationID)
+}
+
+// MarkAllRead marks all of the user's notifications as read
+func (s *NotificationService) MarkAllRead(userID int64) (int64, error) {
+	return s.notificationRepo.MarkAllRead(userID)
+}
+
+// eventNotificationData returns the event snapshot stored with a notification
+func eventNotificationData(event *models.Event) map[string]string {
+	return map[string]string{
+		"event_title": event.Title,
+		"start_time":  timeValue(event.StartTime),
+		"end_time":    timeValue(event.EndTime),
+	}
+}
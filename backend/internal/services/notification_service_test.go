@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+func TestNotificationTypeForChanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		changes  []fieldChange
+		expected models.NotificationType
+		ok       bool
+	}{
+		{
+			name:    "title only",
+			changes: []fieldChange{{Field: "title", OldValue: "Lunch", NewValue: "Dinner"}},
+		},
+		{
+			name:     "start time",
+			changes:  []fieldChange{{Field: "start_time"}, {Field: "end_time"}},
+			expected: models.NotificationEventTimeChanged,
+			ok:       true,
+		},
+		{
+			name: "canceled wins over time change",
+			changes: []fieldChange{
+				{Field: "start_time"},
+				{Field: "status", OldValue: "CONFIRMED", NewValue: "CANCELED"},
+			},
+			expected: models.NotificationEventCanceled,
+			ok:       true,
+		},
+		{
+			name:    "other status change",
+			changes: []fieldChange{{Field: "status", OldValue: "PROPOSED", NewValue: "CONFIRMED"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := notificationTypeForChanges(tt.changes)
+			if got != tt.expected || ok != tt.ok {
+				t.Errorf("Expected (%q, %v), got (%q, %v)", tt.expected, tt.ok, got, ok)
+			}
+		})
+	}
+}
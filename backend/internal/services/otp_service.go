@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/sms"
+	"github.com/khchoi-tnh/timingle/pkg/utils"
+)
+
+const (
+	otpCodeDigits = 6
+	otpCountTTL   = time.Hour
+)
+
+// ErrOTPRateLimited is returned when too many codes were requested
+var ErrOTPRateLimited = errors.New("too many verification requests, please try again later")
+
+// OTPPolicy holds the lifetimes and limits of SMS verification codes
+type OTPPolicy struct {
+	CodeTTL          time.Duration
+	VerificationTTL  time.Duration
+	MaxAttempts      int
+	ResendInterval   time.Duration
+	PhoneHourlyLimit int
+	IPHourlyLimit    int
+}
+
+// OTPService sends and verifies SMS one-time passwords
+// Codes are stored only as HMACs in Redis, so a Redis dump does not reveal them
+type OTPService struct {
+	redis  *redis.Client
+	sms    sms.Provider
+	secret []byte
+	policy OTPPolicy
+}
+
+// NewOTPService creates a new OTP service
+func NewOTPService(redis *redis.Client, smsProvider sms.Provider, secret string, policy OTPPolicy) *OTPService {
+	return &OTPService{
+		redis:  redis,
+		sms:    smsProvider,
+		secret: []byte(secret),
+		policy: policy,
+	}
+}
+
+func otpCodeKey(phone string) string       { return "otp:code:" + phone }
+func otpCooldownKey(phone string) string   { return "otp:cooldown:" + phone }
+func otpPhoneCountKey(phone string) string { return "otp:count:phone:" + phone }
+func otpIPCountKey(ip string) string       { return "otp:count:ip:" + ip }
+func otpVerifiedKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "otp:verified:" + hex.EncodeToString(sum[:])
+}
+
+// RequestCode sends a new verification code to a phone
+func (s *OTPService) RequestCode(ctx context.Context, req *models.OTPRequest, clientIP string) (*models.OTPRequestResponse, error) {
+	phone, err := normalizePhone(req.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resend throttling: one code per phone per interval
+	ok, err := s.redis.SetNX(ctx, otpCooldownKey(phone), 1, s.policy.ResendInterval).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check resend interval: %w", err)
+	}
+	if !ok {
+		return nil, ErrOTPRateLimited
+	}
+
+	if err := s.checkHourlyLimit(ctx, otpIPCountKey(clientIP), s.policy.IPHourlyLimit); err != nil {
+		return nil, err
+	}
+	if err := s.checkHourlyLimit(ctx, otpPhoneCountKey(phone), s.policy.PhoneHourlyLimit); err != nil {
+		return nil, err
+	}
+
+	code, err := generateOTPCode()
+	if err != nil {
+		return nil, err
+	}
+
+	// A new code replaces the previous one and resets its attempts
+	key := otpCodeKey(phone)
+	pipe := s.redis.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.HSet(ctx, key, "hash", s.hashCode(phone, code), "attempts", 0)
+	pipe.Expire(ctx, key, s.policy.CodeTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to store verification code: %w", err)
+	}
+
+	message := fmt.Sprintf("[timingle] 인증번호 %s (%d분 내 입력)", code, int(s.policy.CodeTTL.Minutes()))
+	if err := s.sms.Send(ctx, phone, message); err != nil {
+		// Allow an immediate retry when delivery failed
+		s.redis.Del(ctx, key, otpCooldownKey(phone))
+		return nil, fmt.Errorf("failed to send verification code: %w", err)
+	}
+
+	return &models.OTPRequestResponse{
+		ExpiresIn:   int64(s.policy.CodeTTL.Seconds()),
+		ResendAfter: int64(s.policy.ResendInterval.Seconds()),
+	}, nil
+}
+
+// checkHourlyLimit counts a request against a rolling hourly limit
+func (s *OTPService) checkHourlyLimit(ctx context.Context, key string, limit int) error {
+	count, err := s.redis.Incr(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check request limit: %w", err)
+	}
+	if count == 1 {
+		s.redis.Expire(ctx, key, otpCountTTL)
+	}
+	if limit > 0 && count > int64(limit) {
+		return ErrOTPRateLimited
+	}
+	return nil
+}
+
+// VerifyCode checks a code and returns a single-use token proving ownership of the phone
+func (s *OTPService) VerifyCode(ctx context.Context, req *models.OTPVerifyRequest) (*models.OTPVerifyResponse, error) {
+	phone, err := normalizePhone(req.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	key := otpCodeKey(phone)
+	stored, err := s.redis.HGet(ctx, key, "hash").Result()
+	if err == redis.Nil {
+		return nil, fmt.Errorf("verification code expired or not requested")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load verification code: %w", err)
+	}
+
+	// Count the attempt before comparing so parallel guesses are limited too
+	attempts, err := s.redis.HIncrBy(ctx, key, "attempts", 1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to record attempt: %w", err)
+	}
+	if attempts > int64(s.policy.MaxAttempts) {
+		s.redis.Del(ctx, key)
+		return nil, fmt.Errorf("too many attempts, please request a new code")
+	}
+
+	if !hmac.Equal([]byte(stored), []byte(s.hashCode(phone, strings.TrimSpace(req.Code)))) {
+		return nil, fmt.Errorf("invalid verification code")
+	}
+
+	// Codes are single use
+	if deleted, err := s.redis.Del(ctx, key).Result(); err != nil || deleted == 0 {
+		return nil, fmt.Errorf("verification code expired or not requested")
+	}
+
+	token, err := utils.GenerateRandomString(32)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.redis.Set(ctx, otpVerifiedKey(token), phone, s.policy.VerificationTTL).Err(); err != nil {
+		return nil, fmt.Errorf("failed to store verification: %w", err)
+	}
+
+	return &models.OTPVerifyResponse{
+		VerificationToken: token,
+		ExpiresIn:         int64(s.policy.VerificationTTL.Seconds()),
+	}, nil
+}
+
+// CheckVerification checks that a verification token was issued for the phone
+// without using it up. Returns the normalized phone number.
+func (s *OTPService) CheckVerification(ctx context.Context, phone, token string) (string, error) {
+	return s.verification(phone, s.redis.Get(ctx, otpVerifiedKey(token)))
+}
+
+// ConsumeVerification checks a verification token like CheckVerification and invalidates it
+func (s *OTPService) ConsumeVerification(ctx context.Context, phone, token string) (string, error) {
+	return s.verification(phone, s.redis.GetDel(ctx, otpVerifiedKey(token)))
+}
+
+// verification compares the phone stored for a token (the result of lookup) with phone
+func (s *OTPService) verification(phone string, lookup *redis.StringCmd) (string, error) {
+	normalized, err := normalizePhone(phone)
+	if err != nil {
+		return "", err
+	}
+
+	verified, err := lookup.Result()
+	if err == redis.Nil {
+		return "", fmt.Errorf("phone verification expired or invalid")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to check phone verification: %w", err)
+	}
+
+	if verified != normalized {
+		return "", fmt.Errorf("phone verification does not match phone number")
+	}
+
+	return normalized, nil
+}
+
+// hashCode derives the stored form of a code, bound to its phone number
+func (s *OTPService) hashCode(phone, code string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(phone + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// generateOTPCode returns a uniformly random numeric code
+func generateOTPCode() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < otpCodeDigits; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate verification code: %w", err)
+	}
+
+	return fmt.Sprintf("%0*d", otpCodeDigits, n), nil
+}
+
+// normalizePhone strips formatting from a phone number and validates it
+func normalizePhone(phone string) (string, error) {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(phone) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '(' || r == ')' || r == '.':
+			// Formatting characters
+		default:
+			return "", fmt.Errorf("invalid phone number")
+		}
+	}
+
+	normalized := b.String()
+	digits := strings.TrimPrefix(normalized, "+")
+	if len(digits) < 8 || len(digits) > 15 {
+		return "", fmt.Errorf("invalid phone number")
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"testing"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		valid    bool
+	}{
+		{"01012345678", "01012345678", true},
+		{"010-1234-5678", "01012345678", true},
+		{" +82 10 1234 5678 ", "+821012345678", true},
+		{"(02) 123.4567", "021234567", true},
+		{"1234", "", false},
+		{"010-1234-567a", "", false},
+		{"010+12345678", "", false},
+		{"+1234567890123456", "", false},
+	}
+
+	for _, tt := range tests {
+		got, err := normalizePhone(tt.input)
+		if tt.valid && (err != nil || got != tt.expected) {
+			t.Errorf("normalizePhone(%q) = %q, %v; want %q", tt.input, got, err, tt.expected)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("normalizePhone(%q) = %q; want error", tt.input, got)
+		}
+	}
+}
+
+func TestGenerateOTPCode(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		code, err := generateOTPCode()
+		if err != nil {
+			t.Fatalf("generateOTPCode failed: %v", err)
+		}
+		if len(code) != otpCodeDigits {
+			t.Fatalf("Expected %d digits, got %q", otpCodeDigits, code)
+		}
+		for _, r := range code {
+			if r < '0' || r > '9' {
+				t.Fatalf("Expected numeric code, got %q", code)
+			}
+		}
+	}
+}
+
+func TestOTPHashCode(t *testing.T) {
+	s := &OTPService{secret: []byte("secret")}
+
+	hash := s.hashCode("01012345678", "123456")
+	if hash == "123456" || hash != s.hashCode("01012345678", "123456") {
+		t.Fatalf("Expected deterministic, non-plaintext hash")
+	}
+
+	// A code is only valid for the phone it was sent to
+	if hash == s.hashCode("01099999999", "123456") {
+		t.Errorf("Expected hash to depend on phone")
+	}
+
+	other := &OTPService{secret: []byte("other")}
+	if hash == other.hashCode("01012345678", "123456") {
+		t.Errorf("Expected hash to depend on secret")
+	}
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/repositories"
+	ws "github.com/khchoi-tnh/timingle/internal/websocket"
+)
+
+// maxPollSlots limits the number of candidate slots per event
+const maxPollSlots = 20
+
+// PollService handles event time slot polling
+type PollService struct {
+	pollRepo     *repositories.PollRepository
+	eventRepo    *repositories.EventRepository
+	eventService *EventService
+	hub          *ws.Hub
+}
+
+// NewPollService creates a new poll service
+func NewPollService(
+	pollRepo *repositories.PollRepository,
+	eventRepo *repositories.EventRepository,
+	eventService *EventService,
+	hub *ws.Hub,
+) *PollService {
+	return &PollService{
+		pollRepo:     pollRepo,
+		eventRepo:    eventRepo,
+		eventService: eventService,
+		hub:          hub,
+	}
+}
+
+// CreatePoll proposes candidate slots for an event, replacing any previous poll
+func (s *PollService) CreatePoll(eventID, userID int64, req *models.CreatePollRequest) (*models.PollResponse, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("event not found")
+	}
+
+	if event.CreatorID != userID {
+		return nil, fmt.Errorf("only creator can create a poll")
+	}
+
+	if event.Status != models.EventStatusProposed {
+		return nil, fmt.Errorf("poll can only be created for proposed events")
+	}
+
+	if len(req.Slots) == 0 {
+		return nil, fmt.Errorf("at least one slot is required")
+	}
+	if len(req.Slots) > maxPollSlots {
+		return nil, fmt.Errorf("too many slots (max %d)", maxPollSlots)
+	}
+
+	slots := make([]*models.TimeSlot, 0, len(req.Slots))
+	for _, input := range req.Slots {
+		if input.EndTime.Before(input.StartTime) {
+			return nil, fmt.Errorf("end time must be after start time")
+		}
+		slots = append(slots, &models.TimeSlot{
+			StartTime: input.StartTime,
+			EndTime:   input.EndTime,
+		})
+	}
+
+	if err := s.pollRepo.ReplaceSlots(eventID, slots); err != nil {
+		return nil, err
+	}
+
+	poll := &models.PollResponse{EventID: eventID, Slots: tallySlots(slots, nil)}
+	s.broadcast(eventID, userID, poll)
+
+	return poll, nil
+}
+
+// GetPoll returns the candidate slots of an event with their vote tally
+func (s *PollService) GetPoll(eventID, userID int64) (*models.PollResponse, error) {
+	if err := s.verifyMember(eventID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.loadPoll(eventID)
+}
+
+// Vote records a participant's votes and broadcasts the updated tally
+func (s *PollService) Vote(eventID, userID int64, req *models.VotePollRequest) (*models.PollResponse, error) {
+	if err := s.verifyMember(eventID, userID); err != nil {
+		return nil, err
+	}
+
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("event not found")
+	}
+	if event.Status != models.EventStatusProposed {
+		return nil, fmt.Errorf("voting is closed for this event")
+	}
+
+	slots, err := s.pollRepo.FindSlotsByEventID(eventID)
+	if err != nil {
+		return nil, err
+	}
+	slotIDs := make(map[int64]bool, len(slots))
+	for _, slot := range slots {
+		slotIDs[slot.ID] = true
+	}
+
+	for _, input := range req.Votes {
+		if !input.Choice.IsValid() {
+			return nil, fmt.Errorf("invalid choice: %s", input.Choice)
+		}
+		if !slotIDs[input.SlotID] {
+			return nil, fmt.Errorf("slot %d does not belong to this event", input.SlotID)
+		}
+	}
+
+	for _, input := range req.Votes {
+		vote := &models.TimeSlotVote{
+			SlotID: input.SlotID,
+			UserID: userID,
+			Choice: input.Choice,
+		}
+		if err := s.pollRepo.UpsertVote(vote); err != nil {
+			return nil, err
+		}
+	}
+
+	poll, err := s.loadPoll(eventID)
+	if err != nil {
+		return nil, err
+	}
+	s.broadcast(eventID, userID, poll)
+
+	return poll, nil
+}
+
+func (s *PollService) verifyMember(eventID, userID int64) error {
+	isMember, err := s.eventService.IsUserEventMember(eventID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to verify event access: %w", err)
+	}
+	if !isMember {
+		return fmt.Errorf("user is not a member of this event")
+	}
+	return nil
+}
+
+func (s *PollService) loadPoll(eventID int64) (*models.PollResponse, error) {
+	slots, err := s.pollRepo.FindSlotsByEventID(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	votes, err := s.pollRepo.FindVotesByEventID(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PollResponse{EventID: eventID, Slots: tallySlots(slots, votes)}, nil
+}
+
+// broadcast sends the updated poll to the event room
+func (s *PollService) broadcast(eventID, userID int64, poll *models.PollResponse) {
+	msgBytes, err := json.Marshal(&models.PollUpdateMessage{
+		Type:    "poll_updated",
+		EventID: eventID,
+		UserID:  userID,
+		Poll:    poll,
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to marshal poll update: %v\n", err)
+		return
+	}
+
+	s.hub.BroadcastToEvent(eventID, msgBytes)
+}
+
+// tallySlots counts votes per slot and orders slots best first
+// Ranking: higher score, then fewer NO votes, then earlier start
+func tallySlots(slots []*models.TimeSlot, votes []*models.TimeSlotVote) []*models.TimeSlotResult {
+	results := make([]*models.TimeSlotResult, 0, len(slots))
+	bySlot := make(map[int64]*models.TimeSlotResult, len(slots))
+	for _, slot := range slots {
+		result := &models.TimeSlotResult{Slot: slot, Votes: []*models.TimeSlotVote{}}
+		results = append(results, result)
+		bySlot[slot.ID] = result
+	}
+
+	for _, vote := range votes {
+		result, ok := bySlot[vote.SlotID]
+		if !ok {
+			continue
+		}
+		switch vote.Choice {
+		case models.VoteChoiceYes:
+			result.Yes++
+			result.Score += 2
+		case models.VoteChoiceMaybe:
+			result.Maybe++
+			result.Score++
+		case models.VoteChoiceNo:
+			result.No++
+		}
+		result.Votes = append(result.Votes, vote)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.No != b.No {
+			return a.No < b.No
+		}
+		return a.Slot.StartTime.Before(b.Slot.StartTime)
+	})
+
+	return results
+}
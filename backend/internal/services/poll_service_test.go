@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+func TestTallySlots(t *testing.T) {
+	base := time.Date(2024, 1, 2, 19, 0, 0, 0, time.UTC)
+	slots := []*models.TimeSlot{
+		{ID: 1, StartTime: base, EndTime: base.Add(time.Hour)},
+		{ID: 2, StartTime: base.AddDate(0, 0, 1), EndTime: base.AddDate(0, 0, 1).Add(time.Hour)},
+		{ID: 3, StartTime: base.AddDate(0, 0, 2), EndTime: base.AddDate(0, 0, 2).Add(time.Hour)},
+	}
+	votes := []*models.TimeSlotVote{
+		{SlotID: 1, UserID: 10, Choice: models.VoteChoiceYes},
+		{SlotID: 1, UserID: 11, Choice: models.VoteChoiceNo},
+		{SlotID: 2, UserID: 10, Choice: models.VoteChoiceMaybe},
+		{SlotID: 2, UserID: 11, Choice: models.VoteChoiceMaybe},
+		{SlotID: 3, UserID: 10, Choice: models.VoteChoiceYes},
+		{SlotID: 3, UserID: 11, Choice: models.VoteChoiceYes},
+		{SlotID: 99, UserID: 10, Choice: models.VoteChoiceYes},
+	}
+
+	results := tallySlots(slots, votes)
+
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+
+	// Slot 3 has the highest score; slots 1 and 2 tie on score, slot 2 has fewer NO votes
+	expectedOrder := []int64{3, 2, 1}
+	for i, id := range expectedOrder {
+		if results[i].Slot.ID != id {
+			t.Errorf("Position %d: expected slot %d, got %d", i, id, results[i].Slot.ID)
+		}
+	}
+
+	if results[0].Yes != 2 || results[0].Score != 4 {
+		t.Errorf("Expected slot 3 to have 2 YES and score 4, got %d and %d", results[0].Yes, results[0].Score)
+	}
+	if results[2].No != 1 {
+		t.Errorf("Expected slot 1 to have 1 NO, got %d", results[2].No)
+	}
+}
+
+func TestTallySlots_TieBreaksOnStartTime(t *testing.T) {
+	base := time.Date(2024, 1, 2, 19, 0, 0, 0, time.UTC)
+	slots := []*models.TimeSlot{
+		{ID: 1, StartTime: base.AddDate(0, 0, 1)},
+		{ID: 2, StartTime: base},
+	}
+
+	results := tallySlots(slots, nil)
+
+	if results[0].Slot.ID != 2 {
+		t.Errorf("Expected earlier slot first, got slot %d", results[0].Slot.ID)
+	}
+}
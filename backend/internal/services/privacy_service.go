@@ -0,0 +1,283 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/repositories"
+	"github.com/khchoi-tnh/timingle/pkg/utils"
+)
+
+// accountPurgeBatchSize is the number of due account deletions processed per run
+const accountPurgeBatchSize = 100
+
+// ErrExportRateLimited is returned when a user requests exports too often
+var ErrExportRateLimited = errors.New("a data export was requested recently, please try again later")
+
+// PrivacyPolicy holds the account deletion grace period and export limits
+type PrivacyPolicy struct {
+	DeletionGrace  time.Duration // Time a deletion can still be canceled before the data is erased
+	ExportCooldown time.Duration // Minimum time between exports of the same user
+}
+
+// exportFile is a file in a personal data export archive
+type exportFile struct {
+	name string
+	data interface{}
+}
+
+// PrivacyService handles personal data exports and account deletion (PIPA)
+type PrivacyService struct {
+	userRepo       *repositories.UserRepository
+	eventRepo      *repositories.EventRepository
+	authRepo       *repositories.AuthRepository
+	oauthRepo      *repositories.OAuthRepository
+	chatRepo       *repositories.ChatRepository
+	calendar       *CalendarService
+	calendarSync   *CalendarSyncService
+	googleVerifier *utils.GoogleOAuthVerifier
+	redis          *redis.Client
+	policy         PrivacyPolicy
+}
+
+// NewPrivacyService creates a new privacy service
+func NewPrivacyService(
+	userRepo *repositories.UserRepository,
+	eventRepo *repositories.EventRepository,
+	authRepo *repositories.AuthRepository,
+	oauthRepo *repositories.OAuthRepository,
+	chatRepo *repositories.ChatRepository,
+	calendar *CalendarService,
+	calendarSync *CalendarSyncService,
+	googleVerifier *utils.GoogleOAuthVerifier,
+	redis *redis.Client,
+	policy PrivacyPolicy,
+) *PrivacyService {
+	return &PrivacyService{
+		userRepo:       userRepo,
+		eventRepo:      eventRepo,
+		authRepo:       authRepo,
+		oauthRepo:      oauthRepo,
+		chatRepo:       chatRepo,
+		calendar:       calendar,
+		calendarSync:   calendarSync,
+		googleVerifier: googleVerifier,
+		redis:          redis,
+		policy:         policy,
+	}
+}
+
+func privacyExportKey(userID int64) string {
+	return "privacy:export:" + strconv.FormatInt(userID, 10)
+}
+
+// Export builds a zip archive of a user's personal data: profile and linked accounts,
+// created events, participations and the chat messages they sent (also in events they left)
+// OAuth tokens are never included.
+func (s *PrivacyService) Export(ctx context.Context, userID int64) ([]byte, error) {
+	ok, err := s.redis.SetNX(ctx, privacyExportKey(userID), 1, s.policy.ExportCooldown).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check export limit: %w", err)
+	}
+	if !ok {
+		return nil, ErrExportRateLimited
+	}
+
+	archive, err := s.export(userID)
+	if err != nil {
+		// Let the user retry right away after a failed export
+		s.redis.Del(ctx, privacyExportKey(userID))
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+// export collects a user's data and writes the archive
+func (s *PrivacyService) export(userID int64) ([]byte, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.oauthRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	profile := &models.ExportedProfile{
+		User:           user.ToUserResponse(),
+		LinkedAccounts: []*models.OAuthAccountResponse{},
+		ExportedAt:     time.Now().UTC(),
+	}
+	for _, account := range accounts {
+		profile.LinkedAccounts = append(profile.LinkedAccounts, account.ToResponse())
+	}
+
+	events, err := s.eventRepo.FindByCreatorID(userID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	participations, err := s.eventRepo.FindParticipations(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	eventIDs, err := s.eventRepo.FindJoinedEventIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	messages := []*models.ChatMessage{}
+	for _, eventID := range eventIDs {
+		sent, err := s.chatRepo.FindMessagesBySender(eventID, userID)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, sent...)
+	}
+
+	return buildExportArchive([]exportFile{
+		{name: "profile.json", data: profile},
+		{name: "events.json", data: events},
+		{name: "participations.json", data: participations},
+		{name: "chat_messages.json", data: messages},
+	})
+}
+
+// buildExportArchive writes each file as indented JSON into a zip archive
+func buildExportArchive(files []exportFile) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	for _, file := range files {
+		data, err := json.MarshalIndent(file.data, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", file.name, err)
+		}
+
+		w, err := archive.Create(file.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", file.name, err)
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// RequestDeletion schedules the user's account deletion after the grace period and logs out all sessions
+// The user can log in again and cancel until the scheduled time.
+func (s *PrivacyService) RequestDeletion(userID int64) (*models.AccountDeletionResponse, error) {
+	scheduledAt, err := s.userRepo.ScheduleDeletion(userID, time.Now().Add(s.policy.DeletionGrace))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authRepo.DeleteUserRefreshTokens(userID); err != nil {
+		return nil, err
+	}
+
+	return &models.AccountDeletionResponse{
+		ScheduledAt: scheduledAt,
+		Message:     "account will be deleted at the scheduled time unless the deletion is canceled",
+	}, nil
+}
+
+// CancelDeletion cancels the user's scheduled account deletion
+func (s *PrivacyService) CancelDeletion(userID int64) error {
+	return s.userRepo.CancelDeletion(userID)
+}
+
+// PurgeDue erases the data of accounts whose grace period has ended and returns how many were erased
+// A failed account is logged and retried on the next run.
+func (s *PrivacyService) PurgeDue(ctx context.Context, now time.Time) (int, error) {
+	userIDs, err := s.userRepo.FindDueDeletions(now, accountPurgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, userID := range userIDs {
+		if err := s.purge(ctx, userID, now); err != nil {
+			// Log error but continue
+			fmt.Printf("Warning: failed to delete account %d: %v\n", userID, err)
+			continue
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// purge stops the user's Google Calendar channel and deletes their copies, revokes their Google access, anonymizes their chat authorship and erases their profile
+// Every step is idempotent, so a purge interrupted midway is safely repeated.
+func (s *PrivacyService) purge(ctx context.Context, userID int64, now time.Time) error {
+	// The user may have canceled since the due deletions were listed
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.DeletionScheduledAt == nil || user.DeletionScheduledAt.After(now) {
+		return fmt.Errorf("account deletion is not due")
+	}
+
+	// Channels and copies in the user's Google Calendar can only be removed while the token is still valid
+	if err := s.calendarSync.StopChannel(ctx, userID); err != nil {
+		// Log error but continue: Anonymize deletes the channel either way
+		fmt.Printf("Warning: failed to stop calendar channel of user %d: %v\n", userID, err)
+	}
+	if err := s.calendar.RemoveUserCopies(ctx, userID); err != nil {
+		// Log error but continue: the mappings are deleted either way
+		fmt.Printf("Warning: failed to delete calendar copies of user %d: %v\n", userID, err)
+	}
+
+	accounts, err := s.oauthRepo.FindByUserID(userID)
+	if err != nil {
+		return err
+	}
+	for _, account := range accounts {
+		if account.Provider != models.OAuthProviderGoogle {
+			continue
+		}
+		token := account.RefreshToken
+		if token == nil {
+			token = account.AccessToken
+		}
+		if token == nil {
+			continue
+		}
+		if err := s.googleVerifier.RevokeToken(ctx, *token); err != nil {
+			// Log error but continue: the tokens are deleted either way
+			fmt.Printf("Warning: failed to revoke Google token of user %d: %v\n", userID, err)
+		}
+	}
+
+	eventIDs, err := s.eventRepo.FindJoinedEventIDs(userID)
+	if err != nil {
+		return err
+	}
+	for _, eventID := range eventIDs {
+		if _, err := s.chatRepo.AnonymizeSender(eventID, userID, models.DeletedUserName); err != nil {
+			return err
+		}
+		if err := s.chatRepo.AnonymizeHistoryActor(eventID, userID, models.DeletedUserName); err != nil {
+			return err
+		}
+	}
+
+	return s.userRepo.Anonymize(userID, now)
+}
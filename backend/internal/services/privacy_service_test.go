@@ -0,0 +1,68 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+func TestBuildExportArchive(t *testing.T) {
+	name := "홍길동"
+	archive, err := buildExportArchive([]exportFile{
+		{name: "profile.json", data: &models.ExportedProfile{User: &models.UserResponse{ID: 7, Name: &name}}},
+		{name: "chat_messages.json", data: []*models.ChatMessage{}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("Expected a valid zip archive: %v", err)
+	}
+	if len(reader.File) != 2 || reader.File[0].Name != "profile.json" || reader.File[1].Name != "chat_messages.json" {
+		t.Fatalf("Expected files in order, got %d files", len(reader.File))
+	}
+
+	file, err := reader.File[0].Open()
+	if err != nil {
+		t.Fatalf("Failed to open profile.json: %v", err)
+	}
+	data, _ := io.ReadAll(file)
+	file.Close()
+
+	var profile models.ExportedProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		t.Fatalf("Expected profile.json to be JSON: %v", err)
+	}
+	if profile.User == nil || profile.User.ID != 7 || *profile.User.Name != name {
+		t.Errorf("Expected the exported profile, got %+v", profile.User)
+	}
+
+	file, err = reader.File[1].Open()
+	if err != nil {
+		t.Fatalf("Failed to open chat_messages.json: %v", err)
+	}
+	data, _ = io.ReadAll(file)
+	file.Close()
+	if string(data) != "[]" {
+		t.Errorf("Expected an empty list for no messages, got %s", data)
+	}
+}
+
+func TestDeletedUserHasNoPhoneLogin(t *testing.T) {
+	for phone, expected := range map[string]bool{
+		"01012345678":                   true,
+		models.OAuthPhonePrefix + "123": false,
+		models.DeletedPhonePrefix + "7": false,
+	} {
+		user := &models.User{Phone: phone}
+		if user.HasPhoneLogin() != expected {
+			t.Errorf("HasPhoneLogin(%q) = %v, expected %v", phone, !expected, expected)
+		}
+	}
+}
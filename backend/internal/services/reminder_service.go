@@ -0,0 +1,262 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/repositories"
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	// ReminderSubject is the EVENTS stream subject reminder jobs are published to
+	ReminderSubject = "event.reminder"
+
+	maxReminderOffsets   = 5
+	maxReminderMinutes   = 7 * 24 * 60
+	reminderClaimBatch   = 100
+	missedReminderWindow = 15 * time.Minute
+)
+
+// ReminderService plans event reminders and publishes them when due
+type ReminderService struct {
+	reminderRepo *repositories.ReminderRepository
+	eventRepo    *repositories.EventRepository
+	userRepo     *repositories.UserRepository
+	eventService *EventService
+	nats         nats.JetStreamContext
+	lookahead    time.Duration
+}
+
+// NewReminderService creates a new reminder service
+func NewReminderService(
+	reminderRepo *repositories.ReminderRepository,
+	eventRepo *repositories.EventRepository,
+	userRepo *repositories.UserRepository,
+	eventService *EventService,
+	nats nats.JetStreamContext,
+	lookahead time.Duration,
+) *ReminderService {
+	return &ReminderService{
+		reminderRepo: reminderRepo,
+		eventRepo:    eventRepo,
+		userRepo:     userRepo,
+		eventService: eventService,
+		nats:         nats,
+		lookahead:    lookahead,
+	}
+}
+
+// plannedReminder is a reminder time computed from a user's preferences
+type plannedReminder struct {
+	MinutesBefore int
+	RemindAt      time.Time
+}
+
+// planReminderTimes computes reminder times for an event start
+// Reminders more than missedReminderWindow in the past are dropped, so a late
+// confirmation does not send a "1 day before" reminder an hour before start
+func planReminderTimes(eventStart time.Time, minutes []int64, now time.Time) []plannedReminder {
+	planned := []plannedReminder{}
+	for _, m := range minutes {
+		if m <= 0 {
+			continue
+		}
+		remindAt := eventStart.Add(-time.Duration(m) * time.Minute)
+		if remindAt.Before(now.Add(-missedReminderWindow)) {
+			continue
+		}
+		planned = append(planned, plannedReminder{MinutesBefore: int(m), RemindAt: remindAt})
+	}
+	return planned
+}
+
+// PlanReminders schedules reminders for confirmed events starting within the lookahead
+// Safe to run concurrently and repeatedly: existing reminders are left untouched.
+// All-day events start at midnight in each recipient's timezone.
+func (s *ReminderService) PlanReminders(now time.Time) (int, error) {
+	window := &models.TimeWindow{Start: now, End: now.Add(s.lookahead)}
+
+	events, err := s.eventRepo.FindUpcomingConfirmed(window.Start, window.End)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, event := range events {
+		expansion := window
+		if event.AllDay {
+			expansion = &models.TimeWindow{Start: window.Start.Add(-maxZoneOffset), End: window.End.Add(maxZoneOffset)}
+		}
+
+		starts, err := s.eventService.upcomingStarts(event, expansion)
+		if err != nil {
+			// Log error but continue
+			fmt.Printf("Warning: failed to expand event %d for reminders: %v\n", event.ID, err)
+			continue
+		}
+		if len(starts) == 0 {
+			continue
+		}
+
+		recipientIDs, err := s.reminderRepo.FindRecipients(event.ID)
+		if err != nil {
+			return created, err
+		}
+		recipients, err := s.userRepo.FindByIDs(recipientIDs)
+		if err != nil {
+			return created, err
+		}
+
+		for _, start := range starts {
+			for _, user := range recipients {
+				userStart := event.StartIn(start, models.LoadZone(user.Timezone))
+				for _, planned := range planReminderTimes(userStart, user.ReminderMinutes, now) {
+					ok, err := s.reminderRepo.CreateIfAbsent(&models.EventReminder{
+						EventID:       event.ID,
+						UserID:        user.ID,
+						EventStart:    userStart,
+						MinutesBefore: planned.MinutesBefore,
+						RemindAt:      planned.RemindAt,
+					})
+					if err != nil {
+						return created, err
+					}
+					if ok {
+						created++
+					}
+				}
+			}
+		}
+	}
+
+	return created, nil
+}
+
+// DispatchDue publishes due reminders to the EVENTS stream
+// Each reminder is claimed by exactly one worker and published with its ID as
+// the JetStream message ID, so a retry after a crash is deduplicated
+func (s *ReminderService) DispatchDue(now time.Time) (int, error) {
+	if err := s.reminderRepo.SkipStarted(now); err != nil {
+		return 0, err
+	}
+
+	events := map[int64]*models.Event{}
+	users := map[int64]*models.User{}
+	total := 0
+	for {
+		sent, err := s.reminderRepo.ClaimDue(now, reminderClaimBatch, func(reminders []*models.EventReminder) error {
+			for _, reminder := range reminders {
+				event, ok := events[reminder.EventID]
+				if !ok {
+					var err error
+					event, err = s.eventRepo.FindByID(reminder.EventID)
+					if err != nil {
+						return err
+					}
+					events[reminder.EventID] = event
+				}
+
+				user, ok := users[reminder.UserID]
+				if !ok {
+					var err error
+					user, err = s.userRepo.FindByID(reminder.UserID)
+					if err != nil {
+						return err
+					}
+					users[reminder.UserID] = user
+				}
+
+				if err := s.publish(reminder, event, user); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		total += sent
+		if err != nil {
+			return total, err
+		}
+		if sent < reminderClaimBatch {
+			return total, nil
+		}
+	}
+}
+
+func (s *ReminderService) publish(reminder *models.EventReminder, event *models.Event, user *models.User) error {
+	job := &models.ReminderJob{
+		ReminderID:    reminder.ID,
+		EventID:       reminder.EventID,
+		UserID:        reminder.UserID,
+		Title:         event.Title,
+		Location:      event.Location,
+		EventStart:    reminder.EventStart,
+		AllDay:        event.AllDay,
+		Timezone:      models.LoadZone(user.Timezone).String(),
+		MinutesBefore: reminder.MinutesBefore,
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reminder: %w", err)
+	}
+
+	msgID := fmt.Sprintf("reminder-%d", reminder.ID)
+	if _, err := s.nats.Publish(ReminderSubject, data, nats.MsgId(msgID)); err != nil {
+		return fmt.Errorf("failed to publish reminder to NATS: %w", err)
+	}
+
+	return nil
+}
+
+// GetPreferences returns a user's reminder offsets in minutes
+func (s *ReminderService) GetPreferences(userID int64) ([]int64, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return user.ReminderMinutes, nil
+}
+
+// UpdatePreferences replaces a user's reminder offsets and replans their pending reminders
+func (s *ReminderService) UpdatePreferences(userID int64, req *models.UpdateReminderPreferencesRequest) ([]int64, error) {
+	minutes, err := normalizeReminderMinutes(req.Minutes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.UpdateReminderMinutes(userID, minutes); err != nil {
+		return nil, err
+	}
+
+	if err := s.reminderRepo.DeletePendingByUserID(userID); err != nil {
+		return nil, err
+	}
+
+	return minutes, nil
+}
+
+// normalizeReminderMinutes validates offsets and returns them unique, largest first
+func normalizeReminderMinutes(minutes []int64) ([]int64, error) {
+	if len(minutes) > maxReminderOffsets {
+		return nil, fmt.Errorf("too many reminders (max %d)", maxReminderOffsets)
+	}
+
+	seen := map[int64]bool{}
+	result := []int64{}
+	for _, m := range minutes {
+		if m <= 0 || m > maxReminderMinutes {
+			return nil, fmt.Errorf("reminder must be between 1 and %d minutes before start", maxReminderMinutes)
+		}
+		if !seen[m] {
+			seen[m] = true
+			result = append(result, m)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i] > result[j] })
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPlanReminderTimes(t *testing.T) {
+	start := time.Date(2024, 1, 10, 19, 0, 0, 0, time.UTC)
+
+	// Planned two days ahead: both reminders are in the future
+	planned := planReminderTimes(start, []int64{1440, 60}, start.Add(-48*time.Hour))
+	if len(planned) != 2 {
+		t.Fatalf("Expected 2 reminders, got %d", len(planned))
+	}
+	if !planned[0].RemindAt.Equal(start.Add(-24*time.Hour)) || planned[0].MinutesBefore != 1440 {
+		t.Errorf("Unexpected first reminder: %+v", planned[0])
+	}
+
+	// Confirmed 3 hours before start: the 1-day reminder is dropped
+	planned = planReminderTimes(start, []int64{1440, 60}, start.Add(-3*time.Hour))
+	if len(planned) != 1 || planned[0].MinutesBefore != 60 {
+		t.Errorf("Expected only the 1-hour reminder, got %+v", planned)
+	}
+
+	// Slightly late (within missedReminderWindow) is still sent
+	planned = planReminderTimes(start, []int64{60}, start.Add(-50*time.Minute))
+	if len(planned) != 1 {
+		t.Errorf("Expected late reminder within window to be kept, got %+v", planned)
+	}
+}
+
+func TestNormalizeReminderMinutes(t *testing.T) {
+	minutes, err := normalizeReminderMinutes([]int64{60, 1440, 60})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(minutes) != 2 || minutes[0] != 1440 || minutes[1] != 60 {
+		t.Errorf("Expected [1440 60], got %v", minutes)
+	}
+
+	if _, err := normalizeReminderMinutes([]int64{0}); err == nil {
+		t.Error("Expected error for zero offset")
+	}
+	if _, err := normalizeReminderMinutes([]int64{maxReminderMinutes + 1}); err == nil {
+		t.Error("Expected error for offset beyond 7 days")
+	}
+	if minutes, err := normalizeReminderMinutes(nil); err != nil || len(minutes) != 0 {
+		t.Errorf("Expected empty preferences to be allowed, got %v, %v", minutes, err)
+	}
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+	"github.com/khchoi-tnh/timingle/internal/repositories"
+)
+
+// Profile field limits, matching the users table columns
+const (
+	maxNameLength   = 100
+	maxEmailLength  = 255
+	maxRegionLength = 50
+)
+
+// UserService handles user profiles
+type UserService struct {
+	userRepo   *repositories.UserRepository
+	friendRepo *repositories.FriendRepository
+}
+
+// NewUserService creates a new user service
+func NewUserService(
+	userRepo *repositories.UserRepository,
+	friendRepo *repositories.FriendRepository,
+) *UserService {
+	return &UserService{
+		userRepo:   userRepo,
+		friendRepo: friendRepo,
+	}
+}
+
+// UpdateProfile validates and applies a profile update for the user
+func (s *UserService) UpdateProfile(userID int64, req *models.UpdateUserRequest) (*models.UserResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	if err := applyProfileUpdate(user, req); err != nil {
+		return nil, err
+	}
+
+	if req.Email != nil && user.Email != nil {
+		exists, err := s.userRepo.EmailExists(*user.Email, userID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, fmt.Errorf("email is already in use")
+		}
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	return user.ToUserResponse(), nil
+}
+
+// GetPublicProfile returns userID's profile as seen by viewerID
+// Inactive users and users with a block between the two are reported as not found
+func (s *UserService) GetPublicProfile(viewerID, userID int64) (*models.PublicUserResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || !user.IsActive() {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	if viewerID == userID {
+		return user.ToPublicResponse(false, true), nil
+	}
+
+	blocked, err := s.friendRepo.IsBlocked(viewerID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	isFriend, err := s.friendRepo.AreFriends(viewerID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return user.ToPublicResponse(isFriend, isFriend), nil
+}
+
+// applyProfileUpdate validates the fields set in req and copies them onto user
+func applyProfileUpdate(user *models.User, req *models.UpdateUserRequest) error {
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return fmt.Errorf("name cannot be empty")
+		}
+		if utf8.RuneCountInString(name) > maxNameLength {
+			return fmt.Errorf("name must be at most %d characters", maxNameLength)
+		}
+		user.Name = &name
+	}
+
+	if req.Email != nil {
+		email, err := normalizeEmail(*req.Email)
+		if err != nil {
+			return err
+		}
+		user.Email = optionalString(email)
+	}
+
+	if req.ProfileImageURL != nil {
+		imageURL := strings.TrimSpace(*req.ProfileImageURL)
+		if imageURL != "" {
+			parsed, err := url.Parse(imageURL)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				return fmt.Errorf("invalid profile image URL")
+			}
+		}
+		user.ProfileImageURL = optionalString(imageURL)
+	}
+
+	if req.Region != nil {
+		region := strings.TrimSpace(*req.Region)
+		if utf8.RuneCountInString(region) > maxRegionLength {
+			return fmt.Errorf("region must be at most %d characters", maxRegionLength)
+		}
+		user.Region = optionalString(region)
+	}
+
+	if req.Interests != nil {
+		interests, err := normalizeInterests(req.Interests)
+		if err != nil {
+			return err
+		}
+		user.Interests = interests
+	}
+
+	if req.Timezone != nil {
+		if err := validateTimezone(*req.Timezone); err != nil {
+			return err
+		}
+		user.Timezone = *req.Timezone
+	}
+
+	if req.Language != nil {
+		if !models.SupportedLanguages[*req.Language] {
+			return fmt.Errorf("unsupported language: %s", *req.Language)
+		}
+		user.Language = *req.Language
+	}
+
+	return nil
+}
+
+// normalizeEmail validates a bare email address and lowercases it; "" is allowed
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return "", nil
+	}
+	if len(email) > maxEmailLength {
+		return "", fmt.Errorf("email must be at most %d characters", maxEmailLength)
+	}
+
+	// Reject display-name forms like "Name <a@b.c>"
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return "", fmt.Errorf("invalid email address")
+	}
+
+	return email, nil
+}
+
+// normalizeInterests checks interests against the supported list and drops duplicates
+func normalizeInterests(interests []string) ([]string, error) {
+	seen := make(map[string]bool, len(interests))
+	normalized := make([]string, 0, len(interests))
+	for _, interest := range interests {
+		interest = strings.TrimSpace(interest)
+		if !models.SupportedInterests[interest] {
+			return nil, fmt.Errorf("unsupported interest: %s", interest)
+		}
+		if !seen[interest] {
+			seen[interest] = true
+			normalized = append(normalized, interest)
+		}
+	}
+
+	if len(normalized) > models.MaxInterests {
+		return nil, fmt.Errorf("at most %d interests are allowed", models.MaxInterests)
+	}
+
+	return normalized, nil
+}
+
+// validateTimezone checks that name is an IANA time zone
+func validateTimezone(name string) error {
+	// LoadLocation accepts "" and "Local" as the server's zone
+	if name == "" || name == "Local" {
+		return fmt.Errorf("invalid timezone: %q", name)
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return fmt.Errorf("invalid timezone: %s", name)
+	}
+	return nil
+}
+
+// optionalString returns nil for an empty string
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
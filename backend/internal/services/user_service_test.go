@@ -0,0 +1,74 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+func TestApplyProfileUpdate(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	user := &models.User{ID: 1, Email: str("old@example.com"), Region: str("Seoul"), Timezone: "UTC", Language: "ko"}
+	err := applyProfileUpdate(user, &models.UpdateUserRequest{
+		Name:      str("  홍길동 "),
+		Email:     str(" Hong@Example.COM "),
+		Region:    str(""),
+		Interests: []string{"운동", "독서", "운동"},
+		Timezone:  str("America/New_York"),
+		Language:  str("en"),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if *user.Name != "홍길동" {
+		t.Errorf("Expected trimmed name, got %q", *user.Name)
+	}
+	if *user.Email != "hong@example.com" {
+		t.Errorf("Expected lowercased email, got %q", *user.Email)
+	}
+	if user.Region != nil {
+		t.Errorf("Expected empty region to clear it, got %q", *user.Region)
+	}
+	if len(user.Interests) != 2 {
+		t.Errorf("Expected duplicate interests to collapse, got %v", user.Interests)
+	}
+	if user.Timezone != "America/New_York" || user.Language != "en" {
+		t.Errorf("Expected timezone and language to update, got %s %s", user.Timezone, user.Language)
+	}
+
+	// Omitted fields are left unchanged
+	if err := applyProfileUpdate(user, &models.UpdateUserRequest{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if *user.Email != "hong@example.com" || user.Timezone != "America/New_York" {
+		t.Error("Expected an empty update to change nothing")
+	}
+
+	tooMany := []string{}
+	for interest := range models.SupportedInterests {
+		tooMany = append(tooMany, interest)
+	}
+
+	invalid := map[string]*models.UpdateUserRequest{
+		"blank name":           {Name: str("   ")},
+		"long name":            {Name: str(strings.Repeat("가", maxNameLength+1))},
+		"bad email":            {Email: str("not-an-email")},
+		"display name email":   {Email: str("Hong <hong@example.com>")},
+		"long region":          {Region: str(strings.Repeat("a", maxRegionLength+1))},
+		"image without http":   {ProfileImageURL: str("javascript:alert(1)")},
+		"unknown interest":     {Interests: []string{"코딩"}},
+		"too many interests":   {Interests: tooMany},
+		"unknown timezone":     {Timezone: str("Mars/Olympus")},
+		"local timezone":       {Timezone: str("Local")},
+		"empty timezone":       {Timezone: str("")},
+		"abbreviation":         {Timezone: str("KST")},
+		"unsupported language": {Language: str("fr")},
+	}
+	for name, req := range invalid {
+		if err := applyProfileUpdate(&models.User{}, req); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package sms
+
+import (
+	"context"
+	"log"
+)
+
+// Provider sends SMS messages through an SMS gateway
+type Provider interface {
+	Send(ctx context.Context, phone, message string) error
+}
+
+// LogProvider writes messages to the log instead of sending them
+// For local development only: OTP codes appear in the server log
+type LogProvider struct{}
+
+// NewLogProvider creates a logging SMS provider
+func NewLogProvider() *LogProvider {
+	return &LogProvider{}
+}
+
+// Send logs the message
+func (p *LogProvider) Send(ctx context.Context, phone, message string) error {
+	log.Printf("📨 [sms] to %s: %s", phone, message)
+	return nil
+}
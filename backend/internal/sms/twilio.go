@@ -0,0 +1,95 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultTwilioBaseURL is the Twilio REST API endpoint
+const DefaultTwilioBaseURL = "https://api.twilio.com"
+
+// TwilioProvider sends SMS messages through the Twilio Messages API
+type TwilioProvider struct {
+	accountSID string
+	authToken  string
+	from       string
+	baseURL    string
+	client     *http.Client
+}
+
+// NewTwilioProvider creates a Twilio SMS provider
+// from is the sending Twilio number in E.164 form
+func NewTwilioProvider(accountSID, authToken, from, baseURL string, client *http.Client) (*TwilioProvider, error) {
+	if accountSID == "" || authToken == "" || from == "" {
+		return nil, fmt.Errorf("twilio account SID, auth token and phone number are required")
+	}
+	if baseURL == "" {
+		baseURL = DefaultTwilioBaseURL
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &TwilioProvider{
+		accountSID: accountSID,
+		authToken:  authToken,
+		from:       from,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		client:     client,
+	}, nil
+}
+
+type twilioErrorResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Send delivers a message to one phone
+func (p *TwilioProvider) Send(ctx context.Context, phone, message string) error {
+	form := url.Values{}
+	form.Set("To", toE164(phone))
+	form.Set("From", p.from)
+	form.Set("Body", message)
+
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", p.baseURL, p.accountSID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create Twilio request: %w", err)
+	}
+	req.SetBasicAuth(p.accountSID, p.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send SMS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	var twilioErr twilioErrorResponse
+	_ = json.Unmarshal(body, &twilioErr)
+
+	return fmt.Errorf("twilio returned %d: %s (code %d)", resp.StatusCode, twilioErr.Message, twilioErr.Code)
+}
+
+// toE164 converts a normalized phone number to E.164
+// Numbers in domestic form ("010...") are Korean, the same rule as user_phone_e164 (migration 023)
+func toE164(phone string) string {
+	switch {
+	case strings.HasPrefix(phone, "+"):
+		return phone
+	case strings.HasPrefix(phone, "0"):
+		return "+82" + phone[1:]
+	default:
+		return "+" + phone
+	}
+}
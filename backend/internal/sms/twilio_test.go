@@ -0,0 +1,65 @@
+package sms
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTwilioProviderSend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2010-04-01/Accounts/AC123/Messages.json" {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || user != "AC123" || pass != "secret" {
+			t.Errorf("Expected basic auth with the account SID, got %q %q", user, pass)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("Failed to parse form: %v", err)
+		}
+		if r.PostForm.Get("To") == "+821000000000" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":21211,"message":"Invalid 'To' Phone Number"}`))
+			return
+		}
+		if r.PostForm.Get("To") != "+821012345678" || r.PostForm.Get("From") != "+15005550006" || r.PostForm.Get("Body") != "code 123456" {
+			t.Errorf("Unexpected form: %v", r.PostForm)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"sid":"SM1"}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewTwilioProvider("AC123", "secret", "+15005550006", server.URL, server.Client())
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	if err := provider.Send(context.Background(), "01012345678", "code 123456"); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if err := provider.Send(context.Background(), "01000000000", "code 123456"); err == nil {
+		t.Error("Expected an error for a rejected number")
+	}
+}
+
+func TestNewTwilioProviderRequiresCredentials(t *testing.T) {
+	if _, err := NewTwilioProvider("AC123", "", "+15005550006", "", nil); err == nil {
+		t.Error("Expected an error without an auth token")
+	}
+}
+
+func TestToE164(t *testing.T) {
+	tests := map[string]string{
+		"01012345678":   "+821012345678",
+		"+821012345678": "+821012345678",
+		"14155550100":   "+14155550100",
+	}
+
+	for phone, expected := range tests {
+		if got := toE164(phone); got != expected {
+			t.Errorf("toE164(%q) = %q, expected %q", phone, got, expected)
+		}
+	}
+}
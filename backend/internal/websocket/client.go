@@ -1,6 +1,7 @@
 package websocket
 
 import (
+	"context"
 	"log"
 	"time"
 
@@ -20,10 +21,11 @@ type Client struct {
 	conn    *websocket.Conn
 	send    chan []byte
 	UserID  int64
-	EventID int64
+	EventID int64 // 0 for the user-level channel
 }
 
 // NewClient creates a new Client
+// Pass eventID 0 to connect to the user-level channel instead of an event room
 func NewClient(hub *Hub, conn *websocket.Conn, userID, eventID int64) *Client {
 	return &Client{
 		hub:     hub,
@@ -36,7 +38,9 @@ func NewClient(hub *Hub, conn *websocket.Conn, userID, eventID int64) *Client {
 
 // ReadPump pumps messages from the WebSocket connection to the hub
 func (c *Client) ReadPump(onMessage func([]byte)) {
+	c.updatePresence((*Presence).Join)
 	defer func() {
+		c.updatePresence((*Presence).Leave)
 		c.hub.UnregisterClient(c)
 		c.conn.Close()
 	}()
@@ -45,6 +49,7 @@ func (c *Client) ReadPump(onMessage func([]byte)) {
 	c.conn.SetReadDeadline(time.Now().Add(pongWait))
 	c.conn.SetPongHandler(func(string) error {
 		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		c.updatePresence((*Presence).Refresh)
 		return nil
 	})
 
@@ -60,6 +65,26 @@ func (c *Client) ReadPump(onMessage func([]byte)) {
 	}
 }
 
+// IsUserChannel reports whether the client is connected to its user-level channel
+func (c *Client) IsUserChannel() bool {
+	return c.EventID == 0
+}
+
+// updatePresence applies a presence update for this client, if presence is enabled
+// Presence is only tracked in event rooms
+func (c *Client) updatePresence(update func(*Presence, context.Context, int64, int64) error) {
+	if c.hub.presence == nil || c.IsUserChannel() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), presenceTimeout)
+	defer cancel()
+
+	if err := update(c.hub.presence, ctx, c.EventID, c.UserID); err != nil {
+		log.Printf("Failed to update presence for user %d in event %d: %v", c.UserID, c.EventID, err)
+	}
+}
+
 // WritePump pumps messages from the hub to the WebSocket connection
 func (c *Client) WritePump() {
 	ticker := time.NewTicker(pingPeriod)
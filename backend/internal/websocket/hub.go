@@ -8,26 +8,33 @@ import (
 // Hub manages WebSocket connections
 type Hub struct {
 	// Event ID -> Client map
-	rooms      map[int64]map[*Client]bool
+	rooms map[int64]map[*Client]bool
+	// User ID -> Client map (user-level channel, e.g. notifications)
+	users      map[int64]map[*Client]bool
 	register   chan *Client
 	unregister chan *Client
 	broadcast  chan *BroadcastMessage
+	presence   *Presence // nil disables presence tracking
 	mu         sync.RWMutex
 }
 
-// BroadcastMessage represents a message to broadcast to an event room
+// BroadcastMessage represents a message to broadcast to an event room,
+// or to a user's channel when UserID is set
 type BroadcastMessage struct {
 	EventID int64
+	UserID  int64
 	Data    []byte
 }
 
 // NewHub creates a new Hub
-func NewHub() *Hub {
+func NewHub(presence *Presence) *Hub {
 	return &Hub{
 		rooms:      make(map[int64]map[*Client]bool),
+		users:      make(map[int64]map[*Client]bool),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		broadcast:  make(chan *BroadcastMessage, 256),
+		presence:   presence,
 	}
 }
 
@@ -37,30 +44,43 @@ func (h *Hub) Run() {
 		select {
 		case client := <-h.register:
 			h.mu.Lock()
-			if _, ok := h.rooms[client.EventID]; !ok {
-				h.rooms[client.EventID] = make(map[*Client]bool)
+			channels, key := h.channelOf(client)
+			if _, ok := channels[key]; !ok {
+				channels[key] = make(map[*Client]bool)
 			}
-			h.rooms[client.EventID][client] = true
+			channels[key][client] = true
 			h.mu.Unlock()
-			log.Printf("✅ Client %d joined event %d", client.UserID, client.EventID)
+			if client.IsUserChannel() {
+				log.Printf("✅ Client %d joined user channel", client.UserID)
+			} else {
+				log.Printf("✅ Client %d joined event %d", client.UserID, client.EventID)
+			}
 
 		case client := <-h.unregister:
 			h.mu.Lock()
-			if clients, ok := h.rooms[client.EventID]; ok {
+			channels, key := h.channelOf(client)
+			if clients, ok := channels[key]; ok {
 				if _, ok := clients[client]; ok {
 					delete(clients, client)
 					close(client.send)
 					if len(clients) == 0 {
-						delete(h.rooms, client.EventID)
+						delete(channels, key)
 					}
 				}
 			}
 			h.mu.Unlock()
-			log.Printf("👋 Client %d left event %d", client.UserID, client.EventID)
+			if client.IsUserChannel() {
+				log.Printf("👋 Client %d left user channel", client.UserID)
+			} else {
+				log.Printf("👋 Client %d left event %d", client.UserID, client.EventID)
+			}
 
 		case message := <-h.broadcast:
 			h.mu.RLock()
 			clients := h.rooms[message.EventID]
+			if message.UserID != 0 {
+				clients = h.users[message.UserID]
+			}
 			h.mu.RUnlock()
 
 			for client := range clients {
@@ -75,6 +95,14 @@ func (h *Hub) Run() {
 	}
 }
 
+// channelOf returns the channel map and key a client belongs to
+func (h *Hub) channelOf(client *Client) (map[int64]map[*Client]bool, int64) {
+	if client.IsUserChannel() {
+		return h.users, client.UserID
+	}
+	return h.rooms, client.EventID
+}
+
 // RegisterClient registers a client to the hub
 func (h *Hub) RegisterClient(client *Client) {
 	h.register <- client
@@ -92,3 +120,11 @@ func (h *Hub) BroadcastToEvent(eventID int64, data []byte) {
 		Data:    data,
 	}
 }
+
+// BroadcastToUser sends a message to all of a user's user-level connections
+func (h *Hub) BroadcastToUser(userID int64, data []byte) {
+	h.broadcast <- &BroadcastMessage{
+		UserID: userID,
+		Data:   data,
+	}
+}
@@ -0,0 +1,72 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// presenceTTL expires presence of connections whose server died without cleanup
+	// It is refreshed on every pong, so it only needs to outlive pongWait
+	presenceTTL = 2 * pongWait
+	// presenceTimeout bounds Redis calls made from connection goroutines
+	presenceTimeout = 2 * time.Second
+)
+
+// Presence tracks which users have an open WebSocket in an event room
+// State lives in Redis so other processes (e.g. the push worker) can read it
+type Presence struct {
+	client *redis.Client
+}
+
+// NewPresence creates a new Redis-backed presence tracker
+func NewPresence(client *redis.Client) *Presence {
+	return &Presence{client: client}
+}
+
+func presenceKey(eventID, userID int64) string {
+	return fmt.Sprintf("ws:presence:%d:%d", eventID, userID)
+}
+
+// Join counts a new connection of a user in an event room
+func (p *Presence) Join(ctx context.Context, eventID, userID int64) error {
+	key := presenceKey(eventID, userID)
+	pipe := p.client.TxPipeline()
+	pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, presenceTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Refresh extends the presence of a user in an event room
+func (p *Presence) Refresh(ctx context.Context, eventID, userID int64) error {
+	return p.client.Expire(ctx, presenceKey(eventID, userID), presenceTTL).Err()
+}
+
+// Leave removes one connection of a user from an event room
+func (p *Presence) Leave(ctx context.Context, eventID, userID int64) error {
+	key := presenceKey(eventID, userID)
+	count, err := p.client.Decr(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	if count <= 0 {
+		return p.client.Del(ctx, key).Err()
+	}
+	return nil
+}
+
+// IsInRoom reports whether a user has at least one open connection in an event room
+func (p *Presence) IsInRoom(ctx context.Context, eventID, userID int64) (bool, error) {
+	count, err := p.client.Get(ctx, presenceKey(eventID, userID)).Int64()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,45 @@
+-- 반복 일정 지원 (RFC 5545 RRULE/EXDATE)
+-- recurrence_rule: "FREQ=WEEKLY;BYDAY=TU" 형식의 반복 규칙
+-- recurrence_exdates: 제외된 회차 시작 시간 (RFC3339 문자열)
+-- parent_event_id: "이후 모든 일정" 수정으로 분리된 경우 원본 시리즈
+ALTER TABLE events ADD COLUMN IF NOT EXISTS recurrence_rule TEXT;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS recurrence_exdates TEXT[];
+ALTER TABLE events ADD COLUMN IF NOT EXISTS parent_event_id BIGINT REFERENCES events(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_events_parent_event_id ON events(parent_event_id);
+CREATE INDEX IF NOT EXISTS idx_events_recurring ON events(id) WHERE recurrence_rule IS NOT NULL;
+
+COMMENT ON COLUMN events.recurrence_rule IS 'RFC 5545 RRULE (NULL이면 단일 일정)';
+COMMENT ON COLUMN events.recurrence_exdates IS '제외된 회차 시작 시간 목록 (EXDATE)';
+COMMENT ON COLUMN events.parent_event_id IS '분리되기 전 원본 반복 일정 ID';
+
+-- 반복 일정의 회차별 변경/취소
+CREATE TABLE IF NOT EXISTS event_occurrence_overrides (
+  event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  occurrence_start TIMESTAMPTZ NOT NULL,   -- 원래 회차 시작 시간 (RECURRENCE-ID)
+  title VARCHAR(200),
+  description TEXT,
+  start_time TIMESTAMPTZ,
+  end_time TIMESTAMPTZ,
+  location VARCHAR(200),
+  is_canceled BOOLEAN NOT NULL DEFAULT FALSE,
+  updated_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (event_id, occurrence_start)
+);
+
+-- 회차별 참여자 응답
+CREATE TABLE IF NOT EXISTS event_occurrence_responses (
+  event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  occurrence_start TIMESTAMPTZ NOT NULL,
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  status VARCHAR(20) NOT NULL,             -- 'ACCEPTED', 'DECLINED'
+  responded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (event_id, occurrence_start, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_occurrence_responses_user ON event_occurrence_responses(user_id);
+
+COMMENT ON TABLE event_occurrence_overrides IS '반복 일정의 회차별 변경/취소';
+COMMENT ON TABLE event_occurrence_responses IS '반복 일정의 회차별 참여 응답';
@@ -0,0 +1,25 @@
+-- 일정 시간 투표
+-- 생성자가 후보 시간대를 제안하고 참여자가 YES/MAYBE/NO로 투표
+CREATE TABLE IF NOT EXISTS event_time_slots (
+  id BIGSERIAL PRIMARY KEY,
+  event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  start_time TIMESTAMPTZ NOT NULL,
+  end_time TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT chk_time_slot_range CHECK (end_time >= start_time)
+);
+
+CREATE TABLE IF NOT EXISTS event_time_slot_votes (
+  slot_id BIGINT NOT NULL REFERENCES event_time_slots(id) ON DELETE CASCADE,
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  choice VARCHAR(10) NOT NULL CHECK (choice IN ('YES', 'MAYBE', 'NO')),
+  voted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (slot_id, user_id)
+);
+
+-- 인덱스
+CREATE INDEX IF NOT EXISTS idx_event_time_slots_event ON event_time_slots(event_id);
+CREATE INDEX IF NOT EXISTS idx_event_time_slot_votes_user ON event_time_slot_votes(user_id);
+
+COMMENT ON TABLE event_time_slots IS '일정 후보 시간대';
+COMMENT ON TABLE event_time_slot_votes IS '후보 시간대 투표 (YES, MAYBE, NO)';
@@ -0,0 +1,34 @@
+-- 출석 체크 (노쇼 방지)
+-- 참여자별 출석 상태: CHECKED_IN(정시), LATE(지각), NO_SHOW(노쇼), EXCUSED(사유 인정)
+CREATE TABLE IF NOT EXISTS event_attendance (
+  event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  status VARCHAR(20) NOT NULL CHECK (status IN ('CHECKED_IN', 'LATE', 'NO_SHOW', 'EXCUSED')),
+  method VARCHAR(20) CHECK (method IN ('CREATOR', 'QR', 'GEOFENCE', 'SYSTEM')),
+  checked_in_at TIMESTAMPTZ,
+  recorded_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+  note TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (event_id, user_id)
+);
+
+-- 지오펜스 체크인 위치 (일정별)
+CREATE TABLE IF NOT EXISTS event_geofences (
+  event_id BIGINT PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+  latitude DOUBLE PRECISION NOT NULL CHECK (latitude BETWEEN -90 AND 90),
+  longitude DOUBLE PRECISION NOT NULL CHECK (longitude BETWEEN -180 AND 180),
+  radius_meters INT NOT NULL DEFAULT 150 CHECK (radius_meters > 0),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- 사용자 신뢰도 점수 (0-100, 출석 기록이 없으면 NULL)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS reliability_score NUMERIC(5, 2);
+
+-- 인덱스
+CREATE INDEX IF NOT EXISTS idx_event_attendance_user ON event_attendance(user_id);
+CREATE INDEX IF NOT EXISTS idx_event_attendance_status ON event_attendance(status);
+
+COMMENT ON TABLE event_attendance IS '일정 출석 기록 (노쇼 증거)';
+COMMENT ON COLUMN event_attendance.method IS 'CREATOR: 생성자 확인, QR: QR 스캔, GEOFENCE: 위치 기반, SYSTEM: 완료 시 자동 노쇼 처리';
+COMMENT ON COLUMN users.reliability_score IS '출석 기록 기반 신뢰도 (EXCUSED 제외)';
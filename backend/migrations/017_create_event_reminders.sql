@@ -0,0 +1,25 @@
+-- 일정 리마인더
+-- 사용자별 리마인더 설정 (일정 시작 몇 분 전, 기본: 1일 전, 1시간 전)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS reminder_minutes INT[] NOT NULL DEFAULT '{1440,60}';
+
+-- 예약된 리마인더 (워커 재시작/다중 워커에서도 한 번만 발송)
+CREATE TABLE IF NOT EXISTS event_reminders (
+  id BIGSERIAL PRIMARY KEY,
+  event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  event_start TIMESTAMPTZ NOT NULL,  -- 반복 일정은 회차 시작 시간
+  minutes_before INT NOT NULL CHECK (minutes_before > 0),
+  remind_at TIMESTAMPTZ NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'SENT', 'SKIPPED')),
+  sent_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT uq_event_reminder UNIQUE (event_id, user_id, event_start, minutes_before)
+);
+
+-- 인덱스
+CREATE INDEX IF NOT EXISTS idx_event_reminders_due ON event_reminders(remind_at) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_event_reminders_event ON event_reminders(event_id);
+CREATE INDEX IF NOT EXISTS idx_event_reminders_user ON event_reminders(user_id);
+
+COMMENT ON TABLE event_reminders IS '일정 리마인더 예약 (PENDING: 대기, SENT: 발송됨, SKIPPED: 일정 시작 후 미발송)';
+COMMENT ON COLUMN users.reminder_minutes IS '리마인더 시점 (일정 시작 몇 분 전)';
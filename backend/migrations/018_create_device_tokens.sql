@@ -0,0 +1,16 @@
+-- 푸시 알림 디바이스 토큰
+CREATE TABLE IF NOT EXISTS device_tokens (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  provider VARCHAR(10) NOT NULL CHECK (provider IN ('FCM', 'APNS')),
+  platform VARCHAR(10) CHECK (platform IN ('ANDROID', 'IOS', 'WEB')),
+  token TEXT NOT NULL UNIQUE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- 인덱스
+CREATE INDEX IF NOT EXISTS idx_device_tokens_user ON device_tokens(user_id);
+
+COMMENT ON TABLE device_tokens IS '푸시 알림 디바이스 토큰 (FCM, APNs)';
+COMMENT ON COLUMN device_tokens.token IS '다른 사용자가 같은 기기에 로그인하면 소유자가 변경됨';
@@ -0,0 +1,18 @@
+-- 인앱 알림함
+CREATE TABLE IF NOT EXISTS notifications (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  type VARCHAR(30) NOT NULL CHECK (type IN ('EVENT_INVITED', 'EVENT_TIME_CHANGED', 'EVENT_CANCELED', 'PARTICIPANT_JOINED')),
+  event_id BIGINT REFERENCES events(id) ON DELETE CASCADE,
+  actor_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+  data JSONB NOT NULL DEFAULT '{}',  -- 알림 생성 시점의 일정 제목, 변경된 시간 등
+  read_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- 인덱스 (최신순 커서 페이지네이션, 안 읽은 알림 수)
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications(user_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+COMMENT ON TABLE notifications IS '인앱 알림함 (초대, 시간 변경, 취소, 링크 참여)';
+COMMENT ON COLUMN notifications.read_at IS 'NULL이면 안 읽음';
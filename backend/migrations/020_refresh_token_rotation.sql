@@ -0,0 +1,29 @@
+-- Refresh Token 회전 (rotation) 및 재사용 감지
+-- 토큰 원문 대신 SHA-256 해시만 저장
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS token_hash VARCHAR(64);
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS family_id UUID;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS rotated_at TIMESTAMPTZ;  -- 새 토큰으로 교체된 시각
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMPTZ;  -- 패밀리 전체 무효화 시각
+
+-- 기존 토큰은 해시로 변환 (로그인 유지), 각각 별도 패밀리
+DO $$
+BEGIN
+  IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'refresh_tokens' AND column_name = 'token') THEN
+    UPDATE refresh_tokens
+    SET token_hash = encode(sha256(convert_to(token, 'UTF8')), 'hex')
+    WHERE token_hash IS NULL;
+  END IF;
+END $$;
+
+UPDATE refresh_tokens SET family_id = gen_random_uuid() WHERE family_id IS NULL;
+
+ALTER TABLE refresh_tokens ALTER COLUMN token_hash SET NOT NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN family_id SET NOT NULL;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS token;
+
+-- 인덱스
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family ON refresh_tokens(family_id);
+
+COMMENT ON COLUMN refresh_tokens.token_hash IS 'Refresh Token의 SHA-256 해시 (원문은 저장하지 않음)';
+COMMENT ON COLUMN refresh_tokens.family_id IS '같은 로그인에서 회전된 토큰 묶음. 교체된 토큰이 재사용되면 패밀리 전체 무효화';
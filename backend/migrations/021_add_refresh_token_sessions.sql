@@ -0,0 +1,18 @@
+-- 로그인 세션(기기) 관리
+-- 세션 = 하나의 Refresh Token 패밀리. 회전될 때마다 기기 정보를 새 토큰으로 이어받음
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS platform VARCHAR(20);      -- ANDROID, IOS, WEB
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS user_agent TEXT;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS ip_address VARCHAR(45);    -- IPv6 최대 길이
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMPTZ;  -- 로그인 또는 마지막 토큰 갱신 시각
+
+UPDATE refresh_tokens SET last_used_at = created_at WHERE last_used_at IS NULL;
+
+ALTER TABLE refresh_tokens ALTER COLUMN last_used_at SET DEFAULT NOW();
+ALTER TABLE refresh_tokens ALTER COLUMN last_used_at SET NOT NULL;
+
+-- 사용자별 활성 세션 조회용
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_active ON refresh_tokens(user_id)
+  WHERE rotated_at IS NULL AND revoked_at IS NULL;
+
+COMMENT ON COLUMN refresh_tokens.platform IS '로그인한 클라이언트 플랫폼 (X-Client-Platform 헤더)';
+COMMENT ON COLUMN refresh_tokens.last_used_at IS '세션이 마지막으로 사용된 시각 (로그인 또는 토큰 갱신)';
@@ -0,0 +1,16 @@
+-- 친구 기능
+-- 친구 요청/수락 알림 타입 추가, friendships 상태값 제약조건 추가
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+  CHECK (type IN ('EVENT_INVITED', 'EVENT_TIME_CHANGED', 'EVENT_CANCELED', 'PARTICIPANT_JOINED',
+                  'FRIEND_REQUESTED', 'FRIEND_ACCEPTED'));
+
+ALTER TABLE friendships DROP CONSTRAINT IF EXISTS chk_friendships_status;
+ALTER TABLE friendships ADD CONSTRAINT chk_friendships_status
+  CHECK (status IN ('PENDING', 'ACCEPTED', 'BLOCKED'));
+
+-- 차단 여부 확인용 (양방향 조회)
+CREATE INDEX IF NOT EXISTS idx_friendships_blocked ON friendships(friend_id, user_id)
+  WHERE status = 'BLOCKED';
+
+COMMENT ON TABLE notifications IS '인앱 알림함 (초대, 시간 변경, 취소, 링크 참여, 친구 요청/수락)';
@@ -0,0 +1,45 @@
+-- 연락처 동기화 (친구 찾기)
+-- 앱은 주소록 번호를 E.164로 정규화한 뒤 SHA-256(hex)만 전송, 서버는 같은 방식으로 계산한 phone_hash와 비교
+-- 전화번호 원문은 전송/비교하지 않음
+
+-- 저장된 번호를 E.164로 변환 (가입 시 국내 형식 "010..."으로 저장된 번호는 +82 기준)
+-- OAuth 가입자의 임시 번호(oauth_...)는 NULL
+CREATE OR REPLACE FUNCTION user_phone_e164(phone TEXT)
+RETURNS TEXT AS $$
+BEGIN
+  IF phone IS NULL OR phone LIKE 'oauth\_%' THEN
+    RETURN NULL;
+  ELSIF phone LIKE '+%' THEN
+    RETURN phone;
+  ELSIF phone LIKE '0%' THEN
+    RETURN '+82' || substring(phone FROM 2);
+  END IF;
+  RETURN '+' || phone;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_hash VARCHAR(64);
+
+-- 번호가 바뀔 때마다 해시 갱신
+CREATE OR REPLACE FUNCTION update_user_phone_hash()
+RETURNS TRIGGER AS $$
+BEGIN
+  NEW.phone_hash = encode(sha256(convert_to(user_phone_e164(NEW.phone), 'UTF8')), 'hex');
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS update_users_phone_hash ON users;
+CREATE TRIGGER update_users_phone_hash
+  BEFORE INSERT OR UPDATE OF phone ON users
+  FOR EACH ROW
+  EXECUTE FUNCTION update_user_phone_hash();
+
+-- 기존 사용자 해시 채우기
+UPDATE users
+SET phone_hash = encode(sha256(convert_to(user_phone_e164(phone), 'UTF8')), 'hex')
+WHERE phone_hash IS NULL AND user_phone_e164(phone) IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_phone_hash ON users(phone_hash);
+
+COMMENT ON COLUMN users.phone_hash IS 'SHA-256(E.164 전화번호) hex. 연락처 동기화 조회용, OAuth 임시 번호는 NULL';
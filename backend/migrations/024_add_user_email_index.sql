@@ -0,0 +1,2 @@
+-- 프로필 수정 시 이메일 중복 확인 (대소문자 무시)
+CREATE INDEX IF NOT EXISTS idx_users_email_lower ON users(LOWER(email));
@@ -0,0 +1,23 @@
+-- 업로드된 미디어 (프로필 사진, 채팅 이미지)
+-- 파일 자체는 스토리지(로컬 디렉터리 또는 S3/MinIO)에 저장하고 메타데이터만 관리
+CREATE TABLE IF NOT EXISTS media_objects (
+  id UUID PRIMARY KEY,
+  owner_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  purpose VARCHAR(20) NOT NULL CHECK (purpose IN ('AVATAR', 'CHAT')),
+  event_id BIGINT REFERENCES events(id) ON DELETE CASCADE,  -- CHAT: 이 일정의 멤버만 조회 가능
+  status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'READY')),
+  content_type VARCHAR(50) NOT NULL,  -- PENDING: 클라이언트가 선언한 타입, READY: 내용 기준으로 판별한 타입
+  size_bytes BIGINT NOT NULL,
+  width INTEGER NOT NULL DEFAULT 0,
+  height INTEGER NOT NULL DEFAULT 0,
+  has_thumbnail BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT chk_media_chat_event CHECK (purpose <> 'CHAT' OR event_id IS NOT NULL)
+);
+
+-- 인덱스
+CREATE INDEX IF NOT EXISTS idx_media_objects_owner ON media_objects(owner_id);
+CREATE INDEX IF NOT EXISTS idx_media_objects_event ON media_objects(event_id) WHERE event_id IS NOT NULL;
+
+COMMENT ON TABLE media_objects IS '업로드된 미디어 메타데이터 (EXIF 제거, 썸네일 생성 후 READY)';
+COMMENT ON COLUMN media_objects.status IS 'PENDING: Presigned URL 발급 후 업로드 완료 대기, READY: 처리 완료';
@@ -0,0 +1,26 @@
+-- 회원 탈퇴 (개인정보보호법)
+-- DELETE /me 시 유예 기간 후 삭제 예정 시각을 기록, 유예 기간이 지나면 워커가 개인정보를 익명화
+-- 다른 참여자의 이벤트/채팅이 남아야 하므로 users 행은 삭제하지 않고 status = 'DELETED'로 유지
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_scheduled_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled_at
+  ON users(deletion_scheduled_at) WHERE deletion_scheduled_at IS NOT NULL;
+
+COMMENT ON COLUMN users.deletion_scheduled_at IS '탈퇴 예정 시각. 이 시각 전까지 탈퇴 취소 가능, NULL이면 탈퇴 요청 없음';
+
+-- 탈퇴 처리된 사용자의 임시 번호(deleted_...)도 연락처 동기화 대상에서 제외
+CREATE OR REPLACE FUNCTION user_phone_e164(phone TEXT)
+RETURNS TEXT AS $$
+BEGIN
+  IF phone IS NULL OR phone LIKE 'oauth\_%' OR phone LIKE 'deleted\_%' THEN
+    RETURN NULL;
+  ELSIF phone LIKE '+%' THEN
+    RETURN phone;
+  ELSIF phone LIKE '0%' THEN
+    RETURN '+82' || substring(phone FROM 2);
+  END IF;
+  RETURN '+' || phone;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+COMMENT ON COLUMN users.phone IS '전화번호 또는 placeholder (OAuth 가입: oauth_xxxxx, 탈퇴: deleted_<id>)';
@@ -0,0 +1,23 @@
+-- Google Calendar 양방향 동기화: 사용자별 푸시 알림 채널 (events.watch)과 증분 동기화 토큰
+-- Google이 캘린더 변경 시 웹훅(POST /api/v1/calendar/webhook)을 호출하면 sync_token 이후 변경분만 가져옴
+CREATE TABLE IF NOT EXISTS calendar_sync_channels (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  provider VARCHAR(20) NOT NULL DEFAULT 'google',
+  calendar_id VARCHAR(255) NOT NULL DEFAULT 'primary',
+  channel_id VARCHAR(64) NOT NULL UNIQUE,   -- 등록 시 생성한 UUID (X-Goog-Channel-ID)
+  resource_id VARCHAR(255),                 -- Google이 발급한 리소스 ID (채널 중지 시 필요)
+  token VARCHAR(64) NOT NULL,               -- 웹훅 검증용 비밀값 (X-Goog-Channel-Token)
+  expires_at TIMESTAMPTZ NOT NULL,          -- 만료 전에 워커가 갱신
+  sync_token TEXT,                          -- 마지막 증분 동기화 토큰, NULL이면 전체 동기화
+  last_synced_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (user_id, provider)
+);
+
+-- 인덱스
+CREATE INDEX IF NOT EXISTS idx_calendar_sync_channels_expires_at ON calendar_sync_channels(expires_at);
+
+COMMENT ON TABLE calendar_sync_channels IS 'Google Calendar 푸시 알림 채널과 증분 동기화 상태 (사용자당 1개)';
+COMMENT ON COLUMN calendar_sync_channels.sync_token IS 'events.list nextSyncToken. 만료(410) 시 NULL로 초기화 후 전체 동기화';
@@ -0,0 +1,31 @@
+-- 참여자별 캘린더 복사본 매핑: (일정, 사용자, 제공자)마다 외부 캘린더 이벤트 ID와 동기화 상태
+-- events.google_calendar_id는 일정당 1개라서 두 번째 참여자가 동기화하면 첫 사용자의 ID를 덮어씀
+CREATE TABLE IF NOT EXISTS calendar_event_mappings (
+  id BIGSERIAL PRIMARY KEY,
+  event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  provider VARCHAR(20) NOT NULL DEFAULT 'google',
+  calendar_id VARCHAR(255) NOT NULL DEFAULT 'primary',
+  external_event_id VARCHAR(255) NOT NULL,  -- 사용자 캘린더의 복사본 ID
+  sync_state VARCHAR(20) NOT NULL DEFAULT 'SYNCED' CHECK (sync_state IN ('SYNCED', 'FAILED')),
+  etag VARCHAR(255),                        -- 마지막으로 쓴 복사본의 ETag (우리가 쓴 변경의 알림은 무시)
+  last_synced_at TIMESTAMPTZ,
+  last_error TEXT,                          -- 마지막 반영 실패 사유, 성공하면 NULL
+  error_count INT NOT NULL DEFAULT 0,       -- 연속 실패 횟수
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (event_id, user_id, provider)
+);
+
+-- 인덱스
+CREATE INDEX IF NOT EXISTS idx_calendar_event_mappings_external ON calendar_event_mappings(user_id, provider, external_event_id);
+
+-- 기존 연동 이관: google_calendar_id는 일정 생성자의 캘린더에 만든 복사본으로 간주
+INSERT INTO calendar_event_mappings (event_id, user_id, provider, external_event_id, last_synced_at)
+SELECT id, creator_id, 'google', google_calendar_id, updated_at
+FROM events
+WHERE google_calendar_id IS NOT NULL AND google_calendar_id <> ''
+ON CONFLICT (event_id, user_id, provider) DO NOTHING;
+
+COMMENT ON TABLE calendar_event_mappings IS '참여자별 외부 캘린더 복사본과 동기화 상태';
+COMMENT ON COLUMN events.google_calendar_id IS '더 이상 사용하지 않음 (calendar_event_mappings로 이관)';
@@ -0,0 +1,5 @@
+-- Google 계정 재동의 필요 표시: refresh token이 폐기되면(invalid_grant) 기록하고 캘린더 동기화 중단
+-- 사용자가 Calendar 권한으로 다시 로그인해 새 토큰을 저장하면 NULL로 초기화
+ALTER TABLE oauth_accounts ADD COLUMN IF NOT EXISTS reconsent_required_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN oauth_accounts.reconsent_required_at IS 'refresh token 폐기를 감지한 시각. NULL이 아니면 다시 로그인할 때까지 Calendar API 호출 안 함';
@@ -0,0 +1,16 @@
+-- 일정 시간대와 종일 일정
+-- timezone: 일정을 만든 사용자의 IANA 시간대. 반복 일정은 이 시간대의 벽시계 시간으로 펼침 (일광 절약 시간 전환 후에도 09:00 유지)
+-- all_day: 종일 일정. start_time/end_time은 날짜의 UTC 자정 (end_time은 마지막 날 다음 날, 배타적)
+ALTER TABLE events ADD COLUMN IF NOT EXISTS timezone VARCHAR(50) NOT NULL DEFAULT 'UTC';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS all_day BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- 기존 일정은 만든 사용자의 현재 시간대로 채움
+UPDATE events e
+SET timezone = u.timezone
+FROM users u
+WHERE e.creator_id = u.id
+  AND u.timezone IS NOT NULL AND u.timezone <> ''
+  AND e.timezone = 'UTC';
+
+COMMENT ON COLUMN events.timezone IS '일정 시간대 (IANA, 예: Asia/Seoul). 반복 규칙 계산과 캘린더 내보내기 기준';
+COMMENT ON COLUMN events.all_day IS '종일 일정 여부. TRUE면 start_time/end_time은 날짜만 의미 (UTC 자정)';
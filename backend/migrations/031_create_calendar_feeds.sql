@@ -0,0 +1,11 @@
+-- iCalendar 구독 피드 (webcal): Apple Calendar, Outlook 등이 GET /feeds/:token.ics 를 주기적으로 가져감
+-- 사용자당 1개. 토큰 원문은 발급 시 한 번만 보여주고 SHA-256 해시만 저장 (재발급하면 이전 URL은 즉시 무효)
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+  user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  token_hash VARCHAR(64) NOT NULL UNIQUE,   -- 피드 토큰의 SHA-256 (hex)
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  last_accessed_at TIMESTAMPTZ              -- 캘린더 앱이 마지막으로 가져간 시각
+);
+
+COMMENT ON TABLE calendar_feeds IS '사용자별 비밀 iCalendar 구독 URL. 삭제하면 구독 해지';
+COMMENT ON COLUMN calendar_feeds.token_hash IS '피드 URL 토큰의 SHA-256. 원문은 저장하지 않음';
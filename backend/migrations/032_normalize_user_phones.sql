@@ -0,0 +1,51 @@
+-- 기존 전화번호 정규화
+-- 로그인/회원가입은 입력 번호를 normalizePhone(otp_service.go)과 같은 방식으로 정규화한 뒤 조회하므로
+-- "010-1234-5678"처럼 구분 문자가 포함된 채 저장된 번호는 로그인할 수 없음
+-- 규칙: 앞뒤 공백 제거, 공백/하이픈/괄호/마침표 제거, 맨 앞 '+'만 유지, 숫자 8~15자리
+-- 임시 번호(oauth_..., deleted_...)와 규칙에 맞지 않는 번호는 그대로 둠
+
+-- 정규화 결과 (변환할 수 없는 번호는 NULL)
+CREATE OR REPLACE FUNCTION normalize_user_phone(phone TEXT)
+RETURNS TEXT AS $$
+DECLARE
+  normalized TEXT;
+BEGIN
+  IF phone IS NULL OR btrim(phone) !~ '^\+?[0-9 ().-]+$' THEN
+    RETURN NULL;
+  END IF;
+  normalized := regexp_replace(btrim(phone), '[ ().-]', '', 'g');
+  IF length(ltrim(normalized, '+')) NOT BETWEEN 8 AND 15 THEN
+    RETURN NULL;
+  END IF;
+  RETURN normalized;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- 충돌 확인 후 정규화
+-- 정규화하면 같은 번호가 되는 사용자들은 어느 계정이 번호를 가질지 정할 수 없으므로 변경하지 않고 경고만 출력
+-- (관리자가 계정 병합 또는 번호 수정 후 이 마이그레이션을 다시 실행)
+DO $$
+DECLARE
+  collision RECORD;
+BEGIN
+  FOR collision IN
+    SELECT normalize_user_phone(phone) AS normalized, array_agg(id ORDER BY id) AS user_ids
+    FROM users
+    WHERE normalize_user_phone(phone) IS NOT NULL
+    GROUP BY normalize_user_phone(phone)
+    HAVING COUNT(*) > 1
+  LOOP
+    RAISE WARNING 'phone % is shared by users % after normalization, left unchanged', collision.normalized, collision.user_ids;
+  END LOOP;
+
+  UPDATE users u
+  SET phone = n.normalized
+  FROM (
+    SELECT id, normalize_user_phone(phone) AS normalized,
+           COUNT(*) OVER (PARTITION BY normalize_user_phone(phone)) AS shared
+    FROM users
+    WHERE normalize_user_phone(phone) IS NOT NULL
+  ) n
+  WHERE u.id = n.id AND n.shared = 1 AND u.phone <> n.normalized;
+END;
+$$;
@@ -0,0 +1,11 @@
+-- 반복 일정 회차별 출석
+-- 반복 일정은 회차마다 체크인하므로 출석 기록을 (일정, 사용자, 회차 시작 시각)으로 구분
+-- occurrence_start: 회차의 원래 시작 시각 (RECURRENCE-ID), 단일 일정은 NULL
+ALTER TABLE event_attendance ADD COLUMN IF NOT EXISTS occurrence_start TIMESTAMPTZ;
+
+-- 기존 기본 키 (event_id, user_id) 대신 회차를 포함한 유니크 제약
+-- NULLS NOT DISTINCT: 단일 일정(NULL)도 사용자당 한 건만 허용 (PostgreSQL 15+)
+ALTER TABLE event_attendance DROP CONSTRAINT IF EXISTS event_attendance_pkey;
+ALTER TABLE event_attendance DROP CONSTRAINT IF EXISTS event_attendance_occurrence_key;
+ALTER TABLE event_attendance
+ADD CONSTRAINT event_attendance_occurrence_key UNIQUE NULLS NOT DISTINCT (event_id, user_id, occurrence_start);
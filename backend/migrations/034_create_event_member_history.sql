@@ -0,0 +1,32 @@
+-- 일정 참여 이력
+-- 참여자가 나가거나 제외되면 event_participants에서 삭제되지만, 그동안 보낸 채팅과 변경 이력(ScyllaDB)은 남음
+-- 개인정보 내보내기와 계정 삭제(채팅 익명화)가 이런 일정까지 찾을 수 있도록 참여했던 일정을 기록
+CREATE TABLE IF NOT EXISTS event_member_history (
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  joined_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (user_id, event_id)
+);
+
+-- 참여자가 추가될 때마다 기록 (삭제되어도 이력은 유지)
+CREATE OR REPLACE FUNCTION record_event_member_history()
+RETURNS TRIGGER AS $$
+BEGIN
+  INSERT INTO event_member_history (user_id, event_id)
+  VALUES (NEW.user_id, NEW.event_id)
+  ON CONFLICT DO NOTHING;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS record_event_participants_history ON event_participants;
+CREATE TRIGGER record_event_participants_history
+  AFTER INSERT OR UPDATE OF user_id ON event_participants
+  FOR EACH ROW
+  EXECUTE FUNCTION record_event_member_history();
+
+-- 기존 참여자 채우기 (이 마이그레이션 전에 이미 나간 참여자는 알 수 없음)
+INSERT INTO event_member_history (user_id, event_id, joined_at)
+SELECT user_id, event_id, COALESCE(invited_at, NOW())
+FROM event_participants
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,327 @@
+// Package ical writes the subset of RFC 5545 iCalendar used to export timingle
+// events: VCALENDAR with VEVENTs (timed, all-day, recurring with EXDATE and
+// RECURRENCE-ID overrides) and the VTIMEZONEs their TZIDs refer to.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Event statuses (STATUS property)
+const (
+	StatusTentative = "TENTATIVE"
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+const (
+	prodID = "-//timingle//timingle//EN"
+
+	utcLayout   = "20060102T150405Z"
+	localLayout = "20060102T150405"
+	dateLayout  = "20060102"
+
+	// maxLineOctets is the longest content line allowed before folding
+	maxLineOctets = 75
+)
+
+// Calendar is a VCALENDAR object
+type Calendar struct {
+	Name     string // X-WR-CALNAME, shown by calendar apps for subscriptions
+	Method   string // e.g. PUBLISH; empty leaves it out
+	Events   []*Event
+	Stamp    time.Time     // DTSTAMP of all events
+	Refresh  time.Duration // REFRESH-INTERVAL for subscriptions; 0 leaves it out
+	Timezone string        // X-WR-TIMEZONE; empty leaves it out
+}
+
+// Event is a VEVENT
+// Timed events with a Zone other than UTC are written with TZID (needed for
+// recurrences to keep their wall-clock time across DST); others in UTC.
+type Event struct {
+	UID            string
+	Summary        string
+	Description    string
+	Location       string
+	Status         string
+	Start          time.Time
+	End            time.Time
+	AllDay         bool           // Start/End are dates (End exclusive)
+	Zone           *time.Location // Zone of a timed event; nil means UTC
+	RecurrenceRule string         // RRULE value without the "RRULE:" prefix
+	ExDates        []time.Time
+	RecurrenceID   *time.Time // Original start of an overridden occurrence
+	Created        time.Time
+	LastModified   time.Time
+	URL            string
+}
+
+// Bytes encodes the calendar
+func (c *Calendar) Bytes() []byte {
+	w := &writer{}
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:" + prodID)
+	w.line("CALSCALE:GREGORIAN")
+	if c.Method != "" {
+		w.line("METHOD:" + c.Method)
+	}
+	if c.Name != "" {
+		w.line("X-WR-CALNAME:" + escapeText(c.Name))
+	}
+	if c.Timezone != "" {
+		w.line("X-WR-TIMEZONE:" + c.Timezone)
+	}
+	if c.Refresh > 0 {
+		w.line("REFRESH-INTERVAL;VALUE=DURATION:" + formatDuration(c.Refresh))
+		w.line("X-PUBLISHED-TTL:" + formatDuration(c.Refresh))
+	}
+
+	for _, loc := range c.zones() {
+		writeTimezone(w, loc, c.Stamp.Year())
+	}
+	for _, event := range c.Events {
+		writeEvent(w, event, c.Stamp)
+	}
+
+	w.line("END:VCALENDAR")
+	return w.buf.Bytes()
+}
+
+// zones returns the distinct zones referenced with TZID, sorted by name
+func (c *Calendar) zones() []*time.Location {
+	byName := map[string]*time.Location{}
+	for _, event := range c.Events {
+		if event.usesTZID() {
+			byName[event.Zone.String()] = event.Zone
+		}
+	}
+
+	zones := make([]*time.Location, 0, len(byName))
+	for _, loc := range byName {
+		zones = append(zones, loc)
+	}
+	sort.Slice(zones, func(i, j int) bool { return zones[i].String() < zones[j].String() })
+	return zones
+}
+
+func (e *Event) usesTZID() bool {
+	return !e.AllDay && e.Zone != nil && e.Zone != time.UTC && e.Zone.String() != "UTC"
+}
+
+// timeParams returns the parameters of DTSTART-like properties in the event's form
+func (e *Event) timeParams() string {
+	switch {
+	case e.AllDay:
+		return ";VALUE=DATE"
+	case e.usesTZID():
+		return ";TZID=" + e.Zone.String()
+	default:
+		return ""
+	}
+}
+
+// timeValue formats a time in the event's form: a date, local time in Zone or UTC
+func (e *Event) timeValue(t time.Time) string {
+	switch {
+	case e.AllDay:
+		return t.UTC().Format(dateLayout)
+	case e.usesTZID():
+		return t.In(e.Zone).Format(localLayout)
+	default:
+		return t.UTC().Format(utcLayout)
+	}
+}
+
+// timeProperty formats a DTSTART-like property
+func (e *Event) timeProperty(name string, t time.Time) string {
+	return name + e.timeParams() + ":" + e.timeValue(t)
+}
+
+func writeEvent(w *writer, e *Event, stamp time.Time) {
+	w.line("BEGIN:VEVENT")
+	w.line("UID:" + e.UID)
+	w.line("DTSTAMP:" + stamp.UTC().Format(utcLayout))
+	if e.RecurrenceID != nil {
+		w.line(e.timeProperty("RECURRENCE-ID", *e.RecurrenceID))
+	}
+	w.line(e.timeProperty("DTSTART", e.Start))
+	w.line(e.timeProperty("DTEND", e.End))
+	if e.RecurrenceRule != "" {
+		w.line("RRULE:" + strings.TrimPrefix(e.RecurrenceRule, "RRULE:"))
+	}
+	if len(e.ExDates) > 0 {
+		values := make([]string, len(e.ExDates))
+		for i, exdate := range e.ExDates {
+			values[i] = e.timeValue(exdate)
+		}
+		w.line("EXDATE" + e.timeParams() + ":" + strings.Join(values, ","))
+	}
+	w.line("SUMMARY:" + escapeText(e.Summary))
+	if e.Description != "" {
+		w.line("DESCRIPTION:" + escapeText(e.Description))
+	}
+	if e.Location != "" {
+		w.line("LOCATION:" + escapeText(e.Location))
+	}
+	if e.Status != "" {
+		w.line("STATUS:" + e.Status)
+	}
+	if e.URL != "" {
+		w.line("URL:" + e.URL)
+	}
+	if !e.Created.IsZero() {
+		w.line("CREATED:" + e.Created.UTC().Format(utcLayout))
+	}
+	if !e.LastModified.IsZero() {
+		w.line("LAST-MODIFIED:" + e.LastModified.UTC().Format(utcLayout))
+	}
+	w.line("END:VEVENT")
+}
+
+// writeTimezone writes a VTIMEZONE with yearly rules derived from the
+// transitions of loc in the given year; zones without DST get a single STANDARD
+func writeTimezone(w *writer, loc *time.Location, year int) {
+	w.line("BEGIN:VTIMEZONE")
+	w.line("TZID:" + loc.String())
+
+	transitions := zoneTransitions(loc, year)
+	if len(transitions) == 0 {
+		name, offset := time.Date(year, time.January, 1, 0, 0, 0, 0, loc).Zone()
+		w.line("BEGIN:STANDARD")
+		w.line("DTSTART:19700101T000000")
+		w.line("TZOFFSETFROM:" + formatOffset(offset))
+		w.line("TZOFFSETTO:" + formatOffset(offset))
+		w.line("TZNAME:" + name)
+		w.line("END:STANDARD")
+	}
+
+	for _, tr := range transitions {
+		component := "STANDARD"
+		if tr.at.In(loc).IsDST() {
+			component = "DAYLIGHT"
+		}
+
+		// DTSTART and the rule use the wall clock before the transition
+		local := tr.at.In(time.FixedZone("", tr.offsetFrom))
+		n := (local.Day()-1)/7 + 1
+		if local.Day()+7 > daysIn(local.Year(), local.Month()) {
+			n = -1
+		}
+		first := nthWeekday(1970, local.Month(), local.Weekday(), n)
+		dtstart := time.Date(1970, local.Month(), first, local.Hour(), local.Minute(), local.Second(), 0, time.UTC)
+
+		w.line("BEGIN:" + component)
+		w.line("DTSTART:" + dtstart.Format(localLayout))
+		w.line(fmt.Sprintf("RRULE:FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s", local.Month(), n, weekdayCode(local.Weekday())))
+		w.line("TZOFFSETFROM:" + formatOffset(tr.offsetFrom))
+		w.line("TZOFFSETTO:" + formatOffset(tr.offsetTo))
+		w.line("TZNAME:" + tr.name)
+		w.line("END:" + component)
+	}
+
+	w.line("END:VTIMEZONE")
+}
+
+// transition is a change of UTC offset
+type transition struct {
+	at         time.Time
+	offsetFrom int
+	offsetTo   int
+	name       string // Zone abbreviation after the transition
+}
+
+// zoneTransitions returns the offset changes of loc during a year
+func zoneTransitions(loc *time.Location, year int) []transition {
+	var transitions []transition
+	t := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	end := time.Date(year+1, time.January, 1, 0, 0, 0, 0, loc)
+	for {
+		_, next := t.ZoneBounds()
+		if next.IsZero() || !next.Before(end) {
+			return transitions
+		}
+		_, from := t.Zone()
+		name, to := next.Zone()
+		if from != to {
+			transitions = append(transitions, transition{at: next, offsetFrom: from, offsetTo: to, name: name})
+		}
+		t = next
+	}
+}
+
+// nthWeekday returns the day of the month of the n-th (or last, n = -1) weekday
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) int {
+	if n < 0 {
+		last := daysIn(year, month)
+		offset := (int(time.Date(year, month, last, 0, 0, 0, 0, time.UTC).Weekday()) - int(weekday) + 7) % 7
+		return last - offset
+	}
+	offset := (int(weekday) - int(time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Weekday()) + 7) % 7
+	return 1 + offset + (n-1)*7
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func weekdayCode(weekday time.Weekday) string {
+	return [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}[weekday]
+}
+
+// formatOffset formats a UTC offset in seconds as +HHMM
+func formatOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset%3600/60)
+}
+
+// formatDuration formats a duration as an RFC 5545 DURATION (hours and minutes)
+func formatDuration(d time.Duration) string {
+	minutes := int(d.Minutes())
+	if minutes%60 == 0 {
+		return fmt.Sprintf("PT%dH", minutes/60)
+	}
+	return fmt.Sprintf("PT%dM", minutes)
+}
+
+// escapeText escapes a TEXT value (backslash, semicolon, comma and newlines)
+func escapeText(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(value)
+}
+
+// writer writes CRLF-terminated content lines folded at 75 octets
+type writer struct {
+	buf bytes.Buffer
+}
+
+func (w *writer) line(content string) {
+	octets := 0
+	for len(content) > 0 {
+		_, size := utf8.DecodeRuneInString(content)
+		// Continuation lines start with a space, which counts toward the limit
+		if octets+size > maxLineOctets {
+			w.buf.WriteString("\r\n ")
+			octets = 1
+		}
+		w.buf.WriteString(content[:size])
+		octets += size
+		content = content[size:]
+	}
+	w.buf.WriteString("\r\n")
+}
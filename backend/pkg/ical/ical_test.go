@@ -0,0 +1,229 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s not available: %v", name, err)
+	}
+	return loc
+}
+
+// unfold joins folded content lines back together
+func unfold(data []byte) []string {
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(string(data), "\r\n ", ""), "\r\n"), "\r\n")
+}
+
+func containsLine(lines []string, line string) bool {
+	for _, l := range lines {
+		if l == line {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCalendarBytes_TimedEvent(t *testing.T) {
+	calendar := &Calendar{
+		Name:   "timingle",
+		Method: "PUBLISH",
+		Stamp:  time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		Events: []*Event{{
+			UID:         "event-1@timingle",
+			Summary:     "Lunch; team, all",
+			Description: "line1\nline2",
+			Status:      StatusConfirmed,
+			Start:       time.Date(2025, 3, 3, 3, 0, 0, 0, time.UTC),
+			End:         time.Date(2025, 3, 3, 4, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	data := calendar.Bytes()
+	if !strings.HasSuffix(string(data), "END:VCALENDAR\r\n") {
+		t.Errorf("Expected CRLF line endings, got %q", data)
+	}
+
+	lines := unfold(data)
+	for _, expected := range []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:timingle",
+		"UID:event-1@timingle",
+		"DTSTAMP:20250301T120000Z",
+		"DTSTART:20250303T030000Z",
+		"DTEND:20250303T040000Z",
+		`SUMMARY:Lunch\; team\, all`,
+		`DESCRIPTION:line1\nline2`,
+		"STATUS:CONFIRMED",
+	} {
+		if !containsLine(lines, expected) {
+			t.Errorf("Expected line %q in:\n%s", expected, data)
+		}
+	}
+	if strings.Contains(string(data), "VTIMEZONE") {
+		t.Error("Expected no VTIMEZONE for UTC events")
+	}
+}
+
+func TestCalendarBytes_AllDayEvent(t *testing.T) {
+	calendar := &Calendar{
+		Stamp: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		Events: []*Event{{
+			UID:            "event-2@timingle",
+			Summary:        "Trip",
+			Start:          time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+			End:            time.Date(2025, 5, 4, 0, 0, 0, 0, time.UTC),
+			AllDay:         true,
+			Zone:           time.FixedZone("KST", 9*3600),
+			RecurrenceRule: "FREQ=YEARLY;UNTIL=20280501",
+			ExDates:        []time.Time{time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)},
+		}},
+	}
+
+	lines := unfold(calendar.Bytes())
+	for _, expected := range []string{
+		"DTSTART;VALUE=DATE:20250501",
+		"DTEND;VALUE=DATE:20250504",
+		"RRULE:FREQ=YEARLY;UNTIL=20280501",
+		"EXDATE;VALUE=DATE:20260501",
+	} {
+		if !containsLine(lines, expected) {
+			t.Errorf("Expected line %q in %v", expected, lines)
+		}
+	}
+}
+
+func TestCalendarBytes_RecurringWithTimezone(t *testing.T) {
+	newYork := mustLoad(t, "America/New_York")
+	start := time.Date(2025, 3, 3, 9, 0, 0, 0, newYork)
+	recurrenceID := time.Date(2025, 3, 10, 9, 0, 0, 0, newYork)
+
+	calendar := &Calendar{
+		Stamp: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		Events: []*Event{
+			{
+				UID:            "event-3@timingle",
+				Summary:        "Standup",
+				Start:          start,
+				End:            start.Add(30 * time.Minute),
+				Zone:           newYork,
+				RecurrenceRule: "RRULE:FREQ=WEEKLY;BYDAY=MO",
+				ExDates:        []time.Time{start.AddDate(0, 0, 14).UTC(), start.AddDate(0, 0, 21).UTC()},
+			},
+			{
+				UID:          "event-3@timingle",
+				Summary:      "Standup",
+				Status:       StatusCancelled,
+				Start:        recurrenceID,
+				End:          recurrenceID.Add(30 * time.Minute),
+				Zone:         newYork,
+				RecurrenceID: &recurrenceID,
+			},
+		},
+	}
+
+	data := calendar.Bytes()
+	lines := unfold(data)
+	for _, expected := range []string{
+		"BEGIN:VTIMEZONE",
+		"TZID:America/New_York",
+		"BEGIN:DAYLIGHT",
+		"DTSTART:19700308T020000",
+		"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU",
+		"TZOFFSETFROM:-0500",
+		"TZOFFSETTO:-0400",
+		"BEGIN:STANDARD",
+		"DTSTART:19701101T020000",
+		"RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU",
+		"DTSTART;TZID=America/New_York:20250303T090000",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO",
+		// Stored in UTC, written as local wall-clock times after the DST change
+		"EXDATE;TZID=America/New_York:20250317T090000,20250324T090000",
+		"RECURRENCE-ID;TZID=America/New_York:20250310T090000",
+		"STATUS:CANCELLED",
+	} {
+		if !containsLine(lines, expected) {
+			t.Errorf("Expected line %q in:\n%s", expected, data)
+		}
+	}
+	if n := strings.Count(string(data), "BEGIN:VTIMEZONE"); n != 1 {
+		t.Errorf("Expected 1 VTIMEZONE, got %d", n)
+	}
+}
+
+func TestCalendarBytes_ZoneWithoutDST(t *testing.T) {
+	seoul := mustLoad(t, "Asia/Seoul")
+	start := time.Date(2025, 3, 3, 9, 0, 0, 0, seoul)
+
+	calendar := &Calendar{
+		Stamp: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		Events: []*Event{{
+			UID:            "event-4@timingle",
+			Summary:        "회의",
+			Start:          start,
+			End:            start.Add(time.Hour),
+			Zone:           seoul,
+			RecurrenceRule: "FREQ=DAILY",
+		}},
+	}
+
+	lines := unfold(calendar.Bytes())
+	for _, expected := range []string{
+		"TZID:Asia/Seoul",
+		"BEGIN:STANDARD",
+		"TZOFFSETFROM:+0900",
+		"TZOFFSETTO:+0900",
+		"DTSTART;TZID=Asia/Seoul:20250303T090000",
+	} {
+		if !containsLine(lines, expected) {
+			t.Errorf("Expected line %q in %v", expected, lines)
+		}
+	}
+	if containsLine(lines, "BEGIN:DAYLIGHT") {
+		t.Error("Expected no DAYLIGHT component for a zone without DST")
+	}
+}
+
+func TestWriterFoldsLongLines(t *testing.T) {
+	w := &writer{}
+	summary := "SUMMARY:" + strings.Repeat("가", 40) // 3 octets per character
+	w.line(summary)
+
+	physical := strings.Split(strings.TrimSuffix(w.buf.String(), "\r\n"), "\r\n")
+	if len(physical) < 2 {
+		t.Fatalf("Expected the line to be folded, got %q", w.buf.String())
+	}
+	for i, line := range physical {
+		if len(line) > maxLineOctets {
+			t.Errorf("Line %d is %d octets, longer than %d", i, len(line), maxLineOctets)
+		}
+		if i > 0 && !strings.HasPrefix(line, " ") {
+			t.Errorf("Continuation line %d does not start with a space: %q", i, line)
+		}
+	}
+
+	if unfolded := unfold(w.buf.Bytes()); len(unfolded) != 1 || unfolded[0] != summary {
+		t.Errorf("Expected unfolding to restore the line, got %q", unfolded)
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	tests := map[string]string{
+		`a\b`:       `a\\b`,
+		"a;b,c":     `a\;b\,c`,
+		"a\r\nb\nc": `a\nb\nc`,
+	}
+
+	for input, expected := range tests {
+		if got := escapeText(input); got != expected {
+			t.Errorf("escapeText(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,503 @@
+// Package rrule implements the subset of RFC 5545 recurrence rules used by
+// timingle events: FREQ, INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH
+// and WKST.
+package rrule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency represents the FREQ part of a recurrence rule
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxPeriods bounds rule iteration so that rules which never produce an
+// occurrence (e.g. BYMONTH=2;BYMONTHDAY=30) cannot loop forever
+const maxPeriods = 50000
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// WeekdayNum represents a BYDAY entry such as MO, 2TU or -1FR
+// N is 0 when the entry applies to every matching weekday in the period
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+// Rule represents a parsed recurrence rule
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int       // 0 = unlimited
+	Until      time.Time // zero = no end
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	WeekStart  time.Weekday
+}
+
+// Parse parses an RRULE value. The "RRULE:" prefix is optional.
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(value, "RRULE:")
+	if value == "" {
+		return nil, fmt.Errorf("empty recurrence rule")
+	}
+
+	rule := &Rule{Interval: 1, WeekStart: time.Monday}
+
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+		key, val := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+
+		switch key {
+		case "FREQ":
+			switch Frequency(val) {
+			case Daily, Weekly, Monthly, Yearly:
+				rule.Freq = Frequency(val)
+			default:
+				return nil, fmt.Errorf("unsupported FREQ %q", val)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", val)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q", val)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = until
+		case "BYDAY":
+			for _, item := range strings.Split(val, ",") {
+				wd, err := parseWeekdayNum(item)
+				if err != nil {
+					return nil, err
+				}
+				rule.ByDay = append(rule.ByDay, wd)
+			}
+		case "BYMONTHDAY":
+			for _, item := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(item)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("invalid BYMONTHDAY %q", item)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "BYMONTH":
+			for _, item := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(item)
+				if err != nil || n < 1 || n > 12 {
+					return nil, fmt.Errorf("invalid BYMONTH %q", item)
+				}
+				rule.ByMonth = append(rule.ByMonth, time.Month(n))
+			}
+		case "WKST":
+			wd, ok := weekdayCodes[val]
+			if !ok {
+				return nil, fmt.Errorf("invalid WKST %q", val)
+			}
+			rule.WeekStart = wd
+		default:
+			return nil, fmt.Errorf("unsupported rule part %q", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("FREQ is required")
+	}
+	if rule.Count > 0 && !rule.Until.IsZero() {
+		return nil, fmt.Errorf("COUNT and UNTIL cannot be used together")
+	}
+	if rule.Freq == Weekly {
+		for _, wd := range rule.ByDay {
+			if wd.N != 0 {
+				return nil, fmt.Errorf("ordinal BYDAY is not allowed with FREQ=WEEKLY")
+			}
+		}
+	}
+
+	return rule, nil
+}
+
+// parseUntil parses UNTIL in UTC date-time, floating date-time or date form
+func parseUntil(value string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("20060102T150405", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("20060102", value); err == nil {
+		// A date-only UNTIL includes the whole day
+		return t.Add(24*time.Hour - time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid UNTIL %q", value)
+}
+
+// parseWeekdayNum parses a BYDAY entry such as MO, 2TU or -1FR
+func parseWeekdayNum(value string) (WeekdayNum, error) {
+	if len(value) < 2 {
+		return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", value)
+	}
+	code := value[len(value)-2:]
+	wd, ok := weekdayCodes[code]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", value)
+	}
+
+	n := 0
+	if prefix := value[:len(value)-2]; prefix != "" {
+		var err error
+		n, err = strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -53 || n > 53 {
+			return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", value)
+		}
+	}
+
+	return WeekdayNum{Weekday: wd, N: n}, nil
+}
+
+// String returns the canonical RRULE value (without the "RRULE:" prefix)
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, wd := range r.ByDay {
+			days[i] = weekdayNames[wd.Weekday]
+			if wd.N != 0 {
+				days[i] = strconv.Itoa(wd.N) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]string, len(r.ByMonth))
+		for i, m := range r.ByMonth {
+			months[i] = strconv.Itoa(int(m))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayNames[r.WeekStart])
+	}
+	return strings.Join(parts, ";")
+}
+
+// Between returns the occurrences of the rule anchored at dtstart whose start
+// lies in [from, to). Wall-clock times are computed in dtstart's location, so a
+// 09:00 series stays at 09:00 local time across DST transitions.
+func (r *Rule) Between(dtstart, from, to time.Time) []time.Time {
+	var result []time.Time
+	r.iterate(dtstart, func(t time.Time) bool {
+		if !t.Before(to) {
+			return false
+		}
+		if !t.Before(from) {
+			result = append(result, t)
+		}
+		return true
+	})
+	return result
+}
+
+// Includes reports whether t is an occurrence of the rule anchored at dtstart
+func (r *Rule) Includes(dtstart, t time.Time) bool {
+	occurrences := r.Between(dtstart, t, t.Add(time.Second))
+	return len(occurrences) > 0 && occurrences[0].Equal(t)
+}
+
+// iterate calls fn for each occurrence in order until fn returns false or the
+// rule is exhausted
+func (r *Rule) iterate(dtstart time.Time, fn func(time.Time) bool) {
+	emitted := 0
+	for period := 0; period < maxPeriods; period++ {
+		for _, t := range r.periodCandidates(dtstart, period) {
+			if t.Before(dtstart) {
+				continue
+			}
+			if !r.Until.IsZero() && t.After(r.Until) {
+				return
+			}
+			if r.Count > 0 && emitted >= r.Count {
+				return
+			}
+			emitted++
+			if !fn(t) {
+				return
+			}
+		}
+	}
+}
+
+// periodCandidates returns the sorted occurrences produced by the n-th period
+// (day, week, month or year) after dtstart
+func (r *Rule) periodCandidates(dtstart time.Time, n int) []time.Time {
+	loc := dtstart.Location()
+	year, month, day := dtstart.Date()
+	hour, minute, sec := dtstart.Clock()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hour, minute, sec, 0, loc)
+	}
+
+	var candidates []time.Time
+
+	switch r.Freq {
+	case Daily:
+		t := at(year, month, day+n*r.Interval)
+		if r.matchesMonth(t.Month()) && r.matchesMonthDay(t) && r.matchesWeekday(t.Weekday()) {
+			candidates = append(candidates, t)
+		}
+
+	case Weekly:
+		offset := (int(dtstart.Weekday()) - int(r.WeekStart) + 7) % 7
+		first := day - offset + n*7*r.Interval
+		for i := 0; i < 7; i++ {
+			t := at(year, month, first+i)
+			if len(r.ByDay) == 0 {
+				if t.Weekday() != dtstart.Weekday() {
+					continue
+				}
+			} else if !r.matchesWeekday(t.Weekday()) {
+				continue
+			}
+			if r.matchesMonth(t.Month()) {
+				candidates = append(candidates, t)
+			}
+		}
+
+	case Monthly:
+		first := time.Date(year, month+time.Month(n*r.Interval), 1, 0, 0, 0, 0, loc)
+		if !r.matchesMonth(first.Month()) {
+			return nil
+		}
+		for _, d := range r.monthDays(first.Year(), first.Month(), day) {
+			candidates = append(candidates, at(first.Year(), first.Month(), d))
+		}
+
+	case Yearly:
+		y := year + n*r.Interval
+		if len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0 && len(r.ByDay) > 0 {
+			for _, doy := range r.yearWeekdays(y) {
+				candidates = append(candidates, at(y, time.January, doy))
+			}
+			break
+		}
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{month}
+		}
+		for _, m := range months {
+			for _, d := range r.monthDays(y, m, day) {
+				candidates = append(candidates, at(y, m, d))
+			}
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+	return candidates
+}
+
+// monthDays returns the days of the month selected by BYMONTHDAY/BYDAY,
+// falling back to defaultDay when neither is set
+func (r *Rule) monthDays(year int, month time.Month, defaultDay int) []int {
+	count := daysIn(year, month)
+
+	var byMonthDay []int
+	for _, d := range r.ByMonthDay {
+		if d < 0 {
+			d = count + d + 1
+		}
+		if d >= 1 && d <= count {
+			byMonthDay = append(byMonthDay, d)
+		}
+	}
+
+	var days []int
+	switch {
+	case len(r.ByDay) > 0:
+		byDay := r.monthWeekdays(year, month, count)
+		if len(r.ByMonthDay) == 0 {
+			days = byDay
+			break
+		}
+		for _, d := range byDay {
+			if containsInt(byMonthDay, d) {
+				days = append(days, d)
+			}
+		}
+	case len(r.ByMonthDay) > 0:
+		days = byMonthDay
+	default:
+		// RFC 5545: a start day that does not exist in the month is skipped
+		if defaultDay <= count {
+			days = []int{defaultDay}
+		}
+	}
+
+	sort.Ints(days)
+	return uniqueInts(days)
+}
+
+// monthWeekdays expands BYDAY (including ordinals) within a month
+func (r *Rule) monthWeekdays(year int, month time.Month, count int) []int {
+	firstWeekday := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Weekday()
+
+	var days []int
+	for _, wd := range r.ByDay {
+		var matches []int
+		for d := 1 + (int(wd.Weekday)-int(firstWeekday)+7)%7; d <= count; d += 7 {
+			matches = append(matches, d)
+		}
+		days = append(days, pickOrdinal(matches, wd.N)...)
+	}
+	return days
+}
+
+// yearWeekdays expands BYDAY (including ordinals) within a year, returning
+// days of the year
+func (r *Rule) yearWeekdays(year int) []int {
+	count := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	firstWeekday := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC).Weekday()
+
+	var days []int
+	for _, wd := range r.ByDay {
+		var matches []int
+		for d := 1 + (int(wd.Weekday)-int(firstWeekday)+7)%7; d <= count; d += 7 {
+			matches = append(matches, d)
+		}
+		days = append(days, pickOrdinal(matches, wd.N)...)
+	}
+	sort.Ints(days)
+	return uniqueInts(days)
+}
+
+func (r *Rule) matchesMonth(m time.Month) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, bm := range r.ByMonth {
+		if bm == m {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonthDay(t time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	count := daysIn(t.Year(), t.Month())
+	for _, d := range r.ByMonthDay {
+		if d < 0 {
+			d = count + d + 1
+		}
+		if d == t.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesWeekday(wd time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, bd := range r.ByDay {
+		if bd.Weekday == wd {
+			return true
+		}
+	}
+	return false
+}
+
+// pickOrdinal selects the n-th (1-based, negative from the end) element, or
+// all elements when n is 0
+func pickOrdinal(matches []int, n int) []int {
+	switch {
+	case n == 0:
+		return matches
+	case n > 0 && n <= len(matches):
+		return []int{matches[n-1]}
+	case n < 0 && -n <= len(matches):
+		return []int{matches[len(matches)+n]}
+	}
+	return nil
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func uniqueInts(sorted []int) []int {
+	if len(sorted) < 2 {
+		return sorted
+	}
+	out := sorted[:1]
+	for _, v := range sorted[1:] {
+		if v != out[len(out)-1] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,150 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, value string) *Rule {
+	t.Helper()
+	rule, err := Parse(value)
+	if err != nil {
+		t.Fatalf("Parse(%q) returned error: %v", value, err)
+	}
+	return rule
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;COUNT=3;UNTIL=20240101T000000Z",
+		"FREQ=WEEKLY;BYDAY=2MO",
+		"FREQ=MONTHLY;BYSETPOS=1",
+	}
+
+	for _, value := range tests {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("Parse(%q) expected error", value)
+		}
+	}
+}
+
+func TestParse_RoundTrip(t *testing.T) {
+	rule := mustParse(t, "RRULE:FREQ=monthly;INTERVAL=2;BYDAY=-1FR;UNTIL=20241231T000000Z")
+
+	expected := "FREQ=MONTHLY;INTERVAL=2;UNTIL=20241231T000000Z;BYDAY=-1FR"
+	if rule.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, rule.String())
+	}
+}
+
+func TestBetween_WeeklyByDay(t *testing.T) {
+	// Monday 2024-01-01 19:00 UTC, every Monday and Wednesday, 5 occurrences
+	dtstart := time.Date(2024, 1, 1, 19, 0, 0, 0, time.UTC)
+	rule := mustParse(t, "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=5")
+
+	got := rule.Between(dtstart, dtstart, dtstart.AddDate(1, 0, 0))
+	expected := []time.Time{
+		time.Date(2024, 1, 1, 19, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 3, 19, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 8, 19, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 10, 19, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 15, 19, 0, 0, 0, time.UTC),
+	}
+
+	assertTimes(t, expected, got)
+}
+
+func TestBetween_MonthlyLastFriday(t *testing.T) {
+	dtstart := time.Date(2024, 1, 26, 18, 30, 0, 0, time.UTC)
+	rule := mustParse(t, "FREQ=MONTHLY;BYDAY=-1FR")
+
+	got := rule.Between(dtstart, dtstart, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+	expected := []time.Time{
+		time.Date(2024, 1, 26, 18, 30, 0, 0, time.UTC),
+		time.Date(2024, 2, 23, 18, 30, 0, 0, time.UTC),
+		time.Date(2024, 3, 29, 18, 30, 0, 0, time.UTC),
+		time.Date(2024, 4, 26, 18, 30, 0, 0, time.UTC),
+	}
+
+	assertTimes(t, expected, got)
+}
+
+func TestBetween_MonthlySkipsMissingDays(t *testing.T) {
+	dtstart := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	rule := mustParse(t, "FREQ=MONTHLY;COUNT=3")
+
+	got := rule.Between(dtstart, dtstart, dtstart.AddDate(1, 0, 0))
+	expected := []time.Time{
+		time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, 5, 31, 12, 0, 0, 0, time.UTC),
+	}
+
+	assertTimes(t, expected, got)
+}
+
+func TestBetween_UntilAndWindow(t *testing.T) {
+	dtstart := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	rule := mustParse(t, "FREQ=DAILY;INTERVAL=2;UNTIL=20240110T090000Z")
+
+	got := rule.Between(dtstart, time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	expected := []time.Time{
+		time.Date(2024, 1, 5, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 7, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 9, 9, 0, 0, 0, time.UTC),
+	}
+
+	assertTimes(t, expected, got)
+}
+
+func TestBetween_KeepsWallClockAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	// DST starts on 2024-03-10 in New York
+	dtstart := time.Date(2024, 3, 8, 9, 0, 0, 0, loc)
+	rule := mustParse(t, "FREQ=DAILY;COUNT=4")
+
+	got := rule.Between(dtstart, dtstart, dtstart.AddDate(0, 1, 0))
+	if len(got) != 4 {
+		t.Fatalf("Expected 4 occurrences, got %d", len(got))
+	}
+	for _, occurrence := range got {
+		if occurrence.Hour() != 9 {
+			t.Errorf("Expected 09:00 local time, got %s", occurrence)
+		}
+	}
+	if got[2].Sub(got[1]) != 23*time.Hour {
+		t.Errorf("Expected 23h gap across DST start, got %s", got[2].Sub(got[1]))
+	}
+}
+
+func TestIncludes(t *testing.T) {
+	dtstart := time.Date(2024, 1, 1, 19, 0, 0, 0, time.UTC)
+	rule := mustParse(t, "FREQ=WEEKLY")
+
+	if !rule.Includes(dtstart, time.Date(2024, 1, 15, 19, 0, 0, 0, time.UTC)) {
+		t.Error("Expected 2024-01-15 19:00 to be an occurrence")
+	}
+	if rule.Includes(dtstart, time.Date(2024, 1, 15, 20, 0, 0, 0, time.UTC)) {
+		t.Error("Expected 2024-01-15 20:00 not to be an occurrence")
+	}
+}
+
+func assertTimes(t *testing.T, expected, got []time.Time) {
+	t.Helper()
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d occurrences, got %d: %v", len(expected), len(got), got)
+	}
+	for i := range expected {
+		if !got[i].Equal(expected[i]) {
+			t.Errorf("Occurrence %d: expected %s, got %s", i, expected[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package utils
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+const (
+	// DefaultAppleAuthURL is Apple's identity service, also the issuer of its identity tokens
+	DefaultAppleAuthURL = "https://appleid.apple.com"
+
+	// appleKeysTTL is how long fetched signing keys are trusted before refetching
+	appleKeysTTL = 24 * time.Hour
+	// appleKeysMinRefresh limits refetches triggered by unknown key IDs
+	appleKeysMinRefresh = time.Minute
+)
+
+// AppleOAuthVerifier verifies Sign in with Apple identity tokens
+// Signing keys are fetched from {baseURL}/auth/keys and cached; the base URL
+// doubles as the expected issuer so tests can point it at a local stand-in.
+type AppleOAuthVerifier struct {
+	clientIDs  []string // Bundle ID (iOS) and Services ID (web)
+	baseURL    string
+	httpClient *http.Client
+
+	mu        sync.RWMutex
+	keys      map[string]*rsa.PublicKey // By key ID
+	fetchedAt time.Time
+}
+
+// NewAppleOAuthVerifier creates a new Apple identity token verifier
+func NewAppleOAuthVerifier(baseURL string, httpClient *http.Client, clientIDs ...string) *AppleOAuthVerifier {
+	if baseURL == "" {
+		baseURL = DefaultAppleAuthURL
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	// Filter out empty client IDs
+	validIDs := make([]string, 0, len(clientIDs))
+	for _, id := range clientIDs {
+		if id != "" {
+			validIDs = append(validIDs, id)
+		}
+	}
+
+	return &AppleOAuthVerifier{
+		clientIDs:  validIDs,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: httpClient,
+		keys:       map[string]*rsa.PublicKey{},
+	}
+}
+
+// appleClaims are the claims of an Apple identity token
+// Apple sends the boolean claims as either JSON booleans or "true"/"false" strings
+type appleClaims struct {
+	Email          string          `json:"email"`
+	EmailVerified  json.RawMessage `json:"email_verified"`
+	IsPrivateEmail json.RawMessage `json:"is_private_email"`
+	Nonce          string          `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// VerifyIDToken verifies an Apple identity token and returns its payload
+// When nonce is set, the token must carry its SHA-256 hex digest, as Apple's SDKs send it
+func (v *AppleOAuthVerifier) VerifyIDToken(ctx context.Context, idToken, nonce string) (*models.AppleTokenPayload, error) {
+	if len(v.clientIDs) == 0 {
+		return nil, fmt.Errorf("no Apple client IDs configured")
+	}
+
+	claims := &appleClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return v.publicKey(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithIssuer(v.baseURL),
+		jwt.WithAudience(v.clientIDs...),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify Apple identity token: %w", err)
+	}
+
+	if nonce != "" {
+		sum := sha256.Sum256([]byte(nonce))
+		if claims.Nonce != hex.EncodeToString(sum[:]) {
+			return nil, fmt.Errorf("Apple identity token nonce mismatch")
+		}
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("missing subject in Apple identity token")
+	}
+
+	payload := &models.AppleTokenPayload{
+		Issuer:         claims.Issuer,
+		Subject:        claims.Subject,
+		Email:          claims.Email,
+		EmailVerified:  parseAppleBool(claims.EmailVerified),
+		IsPrivateEmail: parseAppleBool(claims.IsPrivateEmail),
+	}
+	if len(claims.Audience) > 0 {
+		payload.Audience = claims.Audience[0]
+	}
+	if claims.IssuedAt != nil {
+		payload.IssuedAt = claims.IssuedAt.Unix()
+	}
+	if claims.ExpiresAt != nil {
+		payload.Expiration = claims.ExpiresAt.Unix()
+	}
+
+	// Older tokens omit is_private_email; the relay domain is authoritative
+	if strings.HasSuffix(strings.ToLower(payload.Email), "@"+models.ApplePrivateRelayDomain) {
+		payload.IsPrivateEmail = true
+	}
+
+	return payload, nil
+}
+
+// parseAppleBool parses a claim sent as true, false, "true" or "false"
+func parseAppleBool(raw json.RawMessage) bool {
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		return b
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s == "true"
+	}
+	return false
+}
+
+// publicKey returns the signing key with the given ID, refetching the key set
+// when the cache is stale or the key is unknown (Apple rotates keys)
+func (v *AppleOAuthVerifier) publicKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	v.mu.RLock()
+	key, ok := v.keys[kid]
+	age := time.Since(v.fetchedAt)
+	v.mu.RUnlock()
+
+	if ok && age < appleKeysTTL {
+		return key, nil
+	}
+	if !ok && age < appleKeysMinRefresh {
+		return nil, fmt.Errorf("unknown Apple signing key: %s", kid)
+	}
+
+	if err := v.refreshKeys(ctx); err != nil {
+		if ok {
+			// Log error but continue with the cached key
+			fmt.Printf("Warning: failed to refresh Apple signing keys: %v\n", err)
+			return key, nil
+		}
+		return nil, err
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	key, ok = v.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown Apple signing key: %s", kid)
+	}
+	return key, nil
+}
+
+// appleJWKS is the JSON Web Key Set served by Apple
+type appleJWKS struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Alg string `json:"alg"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+// refreshKeys fetches Apple's current signing keys
+func (v *AppleOAuthVerifier) refreshKeys(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", v.baseURL+"/auth/keys", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create Apple keys request: %w", err)
+	}
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch Apple signing keys: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch Apple signing keys: status %d", resp.StatusCode)
+	}
+
+	var jwks appleJWKS
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return fmt.Errorf("failed to parse Apple signing keys: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" {
+			continue
+		}
+		key, err := parseRSAPublicKey(jwk.N, jwk.E)
+		if err != nil {
+			return fmt.Errorf("invalid Apple signing key %s: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	v.mu.Unlock()
+
+	return nil
+}
+
+// parseRSAPublicKey builds an RSA key from base64url-encoded JWK modulus and exponent
+func parseRSAPublicKey(n, e string) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent: %w", err)
+	}
+
+	exponent := new(big.Int).SetBytes(eBytes)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("exponent too large")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(exponent.Int64()),
+	}, nil
+}
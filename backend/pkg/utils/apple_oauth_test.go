@@ -0,0 +1,190 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// appleStandIn serves a JWKS the way appleid.apple.com does and signs tokens with its key
+type appleStandIn struct {
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	kid      string
+	requests atomic.Int32
+}
+
+func newAppleStandIn(t *testing.T) *appleStandIn {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	stand := &appleStandIn{key: key, kid: "test-key"}
+	stand.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/auth/keys" {
+			http.NotFound(w, r)
+			return
+		}
+		stand.requests.Add(1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": stand.kid,
+				"alg": "RS256",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	t.Cleanup(stand.server.Close)
+
+	return stand
+}
+
+func (a *appleStandIn) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = a.kid
+	signed, err := token.SignedString(a.key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func (a *appleStandIn) claims(overrides jwt.MapClaims) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"iss":            a.server.URL,
+		"aud":            "com.timingle.app",
+		"sub":            "001234.abcdef.0123",
+		"email":          "abc123@privaterelay.appleid.com",
+		"email_verified": "true",
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(10 * time.Minute).Unix(),
+	}
+	for k, v := range overrides {
+		claims[k] = v
+	}
+	return claims
+}
+
+func TestAppleOAuthVerifier_VerifyIDToken(t *testing.T) {
+	stand := newAppleStandIn(t)
+	verifier := NewAppleOAuthVerifier(stand.server.URL, stand.server.Client(), "com.timingle.app", "com.timingle.web")
+
+	nonce := "raw-nonce"
+	nonceSum := sha256.Sum256([]byte(nonce))
+
+	tests := []struct {
+		name         string
+		claims       jwt.MapClaims
+		nonce        string
+		wantErr      bool
+		wantPrivate  bool
+		wantVerified bool
+	}{
+		{
+			name:         "private relay email with string booleans",
+			claims:       stand.claims(nil),
+			wantPrivate:  true,
+			wantVerified: true,
+		},
+		{
+			name: "real email with JSON booleans and web audience",
+			claims: stand.claims(jwt.MapClaims{
+				"aud":              "com.timingle.web",
+				"email":            "user@example.com",
+				"email_verified":   true,
+				"is_private_email": false,
+			}),
+			wantVerified: true,
+		},
+		{
+			name:    "wrong audience",
+			claims:  stand.claims(jwt.MapClaims{"aud": "com.other.app"}),
+			wantErr: true,
+		},
+		{
+			name:    "wrong issuer",
+			claims:  stand.claims(jwt.MapClaims{"iss": "https://evil.example.com"}),
+			wantErr: true,
+		},
+		{
+			name:    "expired",
+			claims:  stand.claims(jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}),
+			wantErr: true,
+		},
+		{
+			name:         "matching nonce",
+			claims:       stand.claims(jwt.MapClaims{"nonce": hex.EncodeToString(nonceSum[:])}),
+			nonce:        nonce,
+			wantPrivate:  true,
+			wantVerified: true,
+		},
+		{
+			name:    "nonce mismatch",
+			claims:  stand.claims(jwt.MapClaims{"nonce": "something-else"}),
+			nonce:   nonce,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := verifier.VerifyIDToken(context.Background(), stand.sign(t, tt.claims), tt.nonce)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error, got payload %+v", payload)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if payload.Subject != "001234.abcdef.0123" {
+				t.Errorf("Expected subject, got %q", payload.Subject)
+			}
+			if payload.IsPrivateEmail != tt.wantPrivate {
+				t.Errorf("Expected is_private_email %v, got %v", tt.wantPrivate, payload.IsPrivateEmail)
+			}
+			if payload.EmailVerified != tt.wantVerified {
+				t.Errorf("Expected email_verified %v, got %v", tt.wantVerified, payload.EmailVerified)
+			}
+		})
+	}
+
+	if got := stand.requests.Load(); got != 1 {
+		t.Errorf("Expected signing keys to be fetched once and cached, got %d fetches", got)
+	}
+}
+
+func TestAppleOAuthVerifier_RejectsForeignKey(t *testing.T) {
+	stand := newAppleStandIn(t)
+	verifier := NewAppleOAuthVerifier(stand.server.URL, stand.server.Client(), "com.timingle.app")
+
+	// Same key ID, different key
+	other := newAppleStandIn(t)
+	other.kid = stand.kid
+	token := other.sign(t, stand.claims(nil))
+
+	if _, err := verifier.VerifyIDToken(context.Background(), token, ""); err == nil {
+		t.Fatal("Expected token signed by another key to be rejected")
+	}
+}
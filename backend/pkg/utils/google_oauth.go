@@ -3,6 +3,7 @@ package utils
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -15,6 +16,10 @@ import (
 	"github.com/khchoi-tnh/timingle/internal/models"
 )
 
+// ErrGoogleTokenRevoked is returned when Google rejects a refresh token as revoked or expired (invalid_grant)
+// The user has to sign in again to grant access.
+var ErrGoogleTokenRevoked = errors.New("google refresh token was revoked")
+
 // GoogleOAuthVerifier verifies Google ID tokens and manages OAuth tokens
 type GoogleOAuthVerifier struct {
 	clientIDs    []string // All valid client IDs (Android, iOS, Web)
@@ -160,6 +165,12 @@ func (v *GoogleOAuthVerifier) RefreshAccessToken(ctx context.Context, refreshTok
 	}
 
 	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		if resp.StatusCode == http.StatusBadRequest && json.Unmarshal(body, &errResp) == nil && errResp.Error == "invalid_grant" {
+			return nil, fmt.Errorf("token refresh failed: %w", ErrGoogleTokenRevoked)
+		}
 		return nil, fmt.Errorf("token refresh failed: %s", string(body))
 	}
 
@@ -171,6 +182,43 @@ func (v *GoogleOAuthVerifier) RefreshAccessToken(ctx context.Context, refreshTok
 	return &tokenResp, nil
 }
 
+// RevokeToken revokes a Google access or refresh token, ending the app's access to the account
+// Revoking a refresh token also revokes the access tokens issued from it.
+// Tokens Google no longer knows (already revoked or expired) are treated as revoked.
+func (v *GoogleOAuthVerifier) RevokeToken(ctx context.Context, token string) error {
+	data := url.Values{"token": {token}}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://oauth2.googleapis.com/revoke", strings.NewReader(data.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create revoke request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var errResp struct {
+		Error string `json:"error"`
+	}
+	if resp.StatusCode == http.StatusBadRequest && json.Unmarshal(body, &errResp) == nil && errResp.Error == "invalid_token" {
+		return nil
+	}
+
+	return fmt.Errorf("token revocation failed: %s", string(body))
+}
+
 // GetTokenExpiry calculates token expiry time from expires_in seconds
 func GetTokenExpiry(expiresIn int) time.Time {
 	return time.Now().Add(time.Duration(expiresIn) * time.Second)
@@ -11,13 +11,16 @@ import (
 )
 
 // JWTManager handles JWT token operations
+// Tokens are signed with HS256 and a shared secret, or with the asymmetric keys
+// of a KeySet (RS256/EdDSA, identified by kid) when one is configured
 type JWTManager struct {
 	secret        string
+	keys          *KeySet
 	accessExpiry  time.Duration
 	refreshExpiry time.Duration
 }
 
-// NewJWTManager creates a new JWT manager
+// NewJWTManager creates a new JWT manager signing HS256 tokens with a shared secret
 func NewJWTManager(secret string, accessExpiry, refreshExpiry time.Duration) *JWTManager {
 	return &JWTManager{
 		secret:        secret,
@@ -26,20 +29,32 @@ func NewJWTManager(secret string, accessExpiry, refreshExpiry time.Duration) *JW
 	}
 }
 
+// NewJWTManagerWithKeys creates a new JWT manager signing with asymmetric keys
+// HS256 tokens are rejected, so the shared secret no longer grants access
+func NewJWTManagerWithKeys(keys *KeySet, accessExpiry, refreshExpiry time.Duration) *JWTManager {
+	return &JWTManager{
+		keys:          keys,
+		accessExpiry:  accessExpiry,
+		refreshExpiry: refreshExpiry,
+	}
+}
+
 // Claims represents JWT claims structure
 type Claims struct {
-	UserID int64            `json:"user_id"`
-	Phone  string           `json:"phone"`
-	Role   models.UserRole  `json:"role"`
+	UserID    int64           `json:"user_id"`
+	Phone     string          `json:"phone"`
+	Role      models.UserRole `json:"role"`
+	SessionID string          `json:"sid,omitempty"` // Refresh token family the token was issued for
 	jwt.RegisteredClaims
 }
 
-// GenerateAccessToken generates a new access token
-func (m *JWTManager) GenerateAccessToken(user *models.User) (string, error) {
+// GenerateAccessToken generates a new access token for a login session
+func (m *JWTManager) GenerateAccessToken(user *models.User, sessionID string) (string, error) {
 	claims := Claims{
-		UserID: user.ID,
-		Phone:  user.Phone,
-		Role:   user.Role,
+		UserID:    user.ID,
+		Phone:     user.Phone,
+		Role:      user.Role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.accessExpiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -49,8 +64,19 @@ func (m *JWTManager) GenerateAccessToken(user *models.User) (string, error) {
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(m.secret))
+	if m.keys == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(m.secret))
+	}
+
+	key, err := m.keys.SigningKey(time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.method(), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.private)
 }
 
 // GenerateRefreshToken generates a new random refresh token
@@ -64,13 +90,7 @@ func (m *JWTManager) GenerateRefreshToken() (string, error) {
 
 // ValidateAccessToken validates an access token and returns claims
 func (m *JWTManager) ValidateAccessToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// Verify signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(m.secret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, m.verificationKey)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
@@ -84,6 +104,36 @@ func (m *JWTManager) ValidateAccessToken(tokenString string) (*Claims, error) {
 	return claims, nil
 }
 
+// verificationKey returns the key a token must be signed with
+func (m *JWTManager) verificationKey(token *jwt.Token) (interface{}, error) {
+	if m.keys == nil {
+		// Verify signing method
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(m.secret), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := m.keys.verificationKey(kid, time.Now())
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key: %q", kid)
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.private.Public(), nil
+}
+
+// JWKS returns the public keys tokens can be verified with
+// It is empty when tokens are signed with a shared secret
+func (m *JWTManager) JWKS() *JWKS {
+	if m.keys == nil {
+		return &JWKS{Keys: []JWK{}}
+	}
+	return m.keys.JWKS(time.Now())
+}
+
 // GetAccessExpiry returns access token expiry duration
 func (m *JWTManager) GetAccessExpiry() time.Duration {
 	return m.accessExpiry
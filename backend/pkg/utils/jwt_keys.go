@@ -0,0 +1,247 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported asymmetric signing algorithms
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// SigningKey is an asymmetric JWT signing key with its place in the rotation schedule
+// A key is published in the JWKS as soon as it is loaded, signs new tokens from
+// ActiveFrom until a newer key becomes active, and verifies tokens until RetireAt.
+type SigningKey struct {
+	ID         string
+	Algorithm  string
+	ActiveFrom time.Time
+	RetireAt   time.Time // Zero means never retired
+	private    crypto.Signer
+}
+
+// NewSigningKey parses a PEM private key (PKCS#8, or PKCS#1 for RSA)
+// The algorithm is inferred from the key type when empty
+func NewSigningKey(id, algorithm string, privatePEM []byte, activeFrom, retireAt time.Time) (*SigningKey, error) {
+	if id == "" {
+		return nil, fmt.Errorf("signing key ID is required")
+	}
+
+	block, _ := pem.Decode(privatePEM)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %s: no PEM block found", id)
+	}
+
+	var private crypto.Signer
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %s: %w", id, err)
+		}
+		private = key
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %s: %w", id, err)
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("signing key %s: unsupported key type", id)
+		}
+		private = signer
+	default:
+		return nil, fmt.Errorf("signing key %s: unsupported PEM block %q", id, block.Type)
+	}
+
+	var inferred string
+	switch key := private.(type) {
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("signing key %s: RSA keys must be at least 2048 bits", id)
+		}
+		inferred = AlgorithmRS256
+	case ed25519.PrivateKey:
+		inferred = AlgorithmEdDSA
+	default:
+		return nil, fmt.Errorf("signing key %s: only RSA and Ed25519 keys are supported", id)
+	}
+
+	if algorithm == "" {
+		algorithm = inferred
+	}
+	if algorithm != inferred {
+		return nil, fmt.Errorf("signing key %s: algorithm %s does not match key type", id, algorithm)
+	}
+
+	return &SigningKey{
+		ID:         id,
+		Algorithm:  algorithm,
+		ActiveFrom: activeFrom,
+		RetireAt:   retireAt,
+		private:    private,
+	}, nil
+}
+
+// method returns the jwt signing method of the key
+func (k *SigningKey) method() jwt.SigningMethod {
+	if k.Algorithm == AlgorithmEdDSA {
+		return jwt.SigningMethodEdDSA
+	}
+	return jwt.SigningMethodRS256
+}
+
+// retired reports whether the key may no longer verify tokens at now
+func (k *SigningKey) retired(now time.Time) bool {
+	return !k.RetireAt.IsZero() && !now.Before(k.RetireAt)
+}
+
+// KeySet holds the signing keys of the rotation schedule, ordered by ActiveFrom
+type KeySet struct {
+	keys []*SigningKey
+}
+
+// NewKeySet creates a key set; key IDs must be unique
+func NewKeySet(keys ...*SigningKey) (*KeySet, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("at least one signing key is required")
+	}
+
+	seen := map[string]bool{}
+	sorted := make([]*SigningKey, len(keys))
+	copy(sorted, keys)
+	for _, key := range sorted {
+		if seen[key.ID] {
+			return nil, fmt.Errorf("duplicate signing key ID: %s", key.ID)
+		}
+		seen[key.ID] = true
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ActiveFrom.Before(sorted[j].ActiveFrom)
+	})
+
+	return &KeySet{keys: sorted}, nil
+}
+
+// keyManifestEntry is one key of a JWT_KEYS_FILE manifest
+type keyManifestEntry struct {
+	ID             string    `json:"kid"`
+	Algorithm      string    `json:"alg,omitempty"`
+	PrivateKeyFile string    `json:"private_key_file"` // Relative to the manifest
+	ActiveFrom     time.Time `json:"active_from"`
+	RetireAt       time.Time `json:"retire_at,omitempty"`
+}
+
+// LoadKeySet loads signing keys from a JSON manifest listing each key's PEM file and schedule
+func LoadKeySet(manifestPath string) (*KeySet, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT key manifest: %w", err)
+	}
+
+	var entries []keyManifestEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse JWT key manifest: %w", err)
+	}
+
+	dir := filepath.Dir(manifestPath)
+	keys := make([]*SigningKey, 0, len(entries))
+	for _, entry := range entries {
+		path := entry.PrivateKeyFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		privatePEM, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signing key %s: %w", entry.ID, err)
+		}
+
+		key, err := NewSigningKey(entry.ID, entry.Algorithm, privatePEM, entry.ActiveFrom, entry.RetireAt)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(keys...)
+}
+
+// SigningKey returns the key that signs new tokens at now: the most recently activated, unretired key
+func (s *KeySet) SigningKey(now time.Time) (*SigningKey, error) {
+	for i := len(s.keys) - 1; i >= 0; i-- {
+		key := s.keys[i]
+		if !key.ActiveFrom.After(now) && !key.retired(now) {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("no active JWT signing key")
+}
+
+// verificationKey returns the unretired key with the given ID
+func (s *KeySet) verificationKey(kid string, now time.Time) (*SigningKey, bool) {
+	for _, key := range s.keys {
+		if key.ID == kid && !key.retired(now) {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// JWK is a public key in JSON Web Key format
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // OKP curve
+	X   string `json:"x,omitempty"`   // OKP public key
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys other services may verify tokens with at now
+// Keys scheduled for later activation are included so verifiers can cache them ahead of rotation
+func (s *KeySet) JWKS(now time.Time) *JWKS {
+	jwks := &JWKS{Keys: []JWK{}}
+	for _, key := range s.keys {
+		if key.retired(now) {
+			continue
+		}
+
+		jwk := JWK{Kid: key.ID, Use: "sig", Alg: key.Algorithm}
+		switch public := key.private.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+	return jwks
+}
@@ -0,0 +1,223 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/khchoi-tnh/timingle/internal/models"
+)
+
+func rsaKeyPEM(t *testing.T) []byte {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func ed25519KeyPEM(t *testing.T) []byte {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func mustSigningKey(t *testing.T, id string, privatePEM []byte, activeFrom, retireAt time.Time) *SigningKey {
+	t.Helper()
+
+	key, err := NewSigningKey(id, "", privatePEM, activeFrom, retireAt)
+	if err != nil {
+		t.Fatalf("failed to create signing key: %v", err)
+	}
+	return key
+}
+
+func TestKeySet_SigningKeySchedule(t *testing.T) {
+	now := time.Now()
+	old := mustSigningKey(t, "2025-01", rsaKeyPEM(t), now.Add(-60*24*time.Hour), now.Add(-24*time.Hour))
+	current := mustSigningKey(t, "2025-02", rsaKeyPEM(t), now.Add(-30*24*time.Hour), time.Time{})
+	next := mustSigningKey(t, "2025-03", ed25519KeyPEM(t), now.Add(24*time.Hour), time.Time{})
+
+	keys, err := NewKeySet(next, old, current)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	key, err := keys.SigningKey(now)
+	if err != nil || key.ID != "2025-02" {
+		t.Fatalf("Expected current key to sign, got %v (%v)", key, err)
+	}
+	if key, _ := keys.SigningKey(now.Add(48 * time.Hour)); key.ID != "2025-03" {
+		t.Errorf("Expected next key to sign after activation, got %s", key.ID)
+	}
+
+	// Retired keys are not published; upcoming keys are
+	jwks := keys.JWKS(now)
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("Expected 2 published keys, got %d", len(jwks.Keys))
+	}
+	if jwks.Keys[0].Kid != "2025-02" || jwks.Keys[0].Kty != "RSA" || jwks.Keys[0].N == "" {
+		t.Errorf("Unexpected RSA key: %+v", jwks.Keys[0])
+	}
+	if jwks.Keys[1].Kid != "2025-03" || jwks.Keys[1].Kty != "OKP" || jwks.Keys[1].Crv != "Ed25519" || jwks.Keys[1].X == "" {
+		t.Errorf("Unexpected Ed25519 key: %+v", jwks.Keys[1])
+	}
+
+	if _, err := NewKeySet(current, current); err == nil {
+		t.Error("Expected duplicate key IDs to be rejected")
+	}
+}
+
+func TestNewSigningKey_Validation(t *testing.T) {
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	weakPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(weak)})
+
+	if _, err := NewSigningKey("weak", "", weakPEM, time.Time{}, time.Time{}); err == nil {
+		t.Error("Expected RSA keys under 2048 bits to be rejected")
+	}
+	if _, err := NewSigningKey("mismatch", AlgorithmEdDSA, rsaKeyPEM(t), time.Time{}, time.Time{}); err == nil {
+		t.Error("Expected algorithm mismatch to be rejected")
+	}
+	if _, err := NewSigningKey("garbage", "", []byte("not a key"), time.Time{}, time.Time{}); err == nil {
+		t.Error("Expected invalid PEM to be rejected")
+	}
+}
+
+func TestJWTManagerWithKeys_RoundTrip(t *testing.T) {
+	user := &models.User{ID: 42, Phone: "+821012345678"}
+
+	tests := []struct {
+		name string
+		pem  []byte
+		alg  string
+	}{
+		{name: "RS256", pem: rsaKeyPEM(t), alg: AlgorithmRS256},
+		{name: "EdDSA", pem: ed25519KeyPEM(t), alg: AlgorithmEdDSA},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := NewKeySet(mustSigningKey(t, "k1", tt.pem, time.Now().Add(-time.Hour), time.Time{}))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			manager := NewJWTManagerWithKeys(keys, 15*time.Minute, time.Hour)
+
+			token, err := manager.GenerateAccessToken(user, "session-1")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if parsed.Header["kid"] != "k1" || parsed.Header["alg"] != tt.alg {
+				t.Errorf("Unexpected header: %v", parsed.Header)
+			}
+
+			claims, err := manager.ValidateAccessToken(token)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if claims.UserID != 42 || claims.SessionID != "session-1" {
+				t.Errorf("Unexpected claims: %+v", claims)
+			}
+		})
+	}
+}
+
+func TestJWTManagerWithKeys_RejectsUntrustedTokens(t *testing.T) {
+	user := &models.User{ID: 42}
+	now := time.Now()
+
+	retiring := mustSigningKey(t, "old", rsaKeyPEM(t), now.Add(-time.Hour), now.Add(time.Hour))
+	keys, err := NewKeySet(retiring)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	manager := NewJWTManagerWithKeys(keys, 15*time.Minute, time.Hour)
+
+	// A token signed with the shared secret must not pass
+	hsToken, err := NewJWTManager("secret", 15*time.Minute, time.Hour).GenerateAccessToken(user, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := manager.ValidateAccessToken(hsToken); err == nil {
+		t.Error("Expected HS256 token to be rejected")
+	}
+
+	// A token from a key outside the set must not pass
+	otherKeys, _ := NewKeySet(mustSigningKey(t, "other", rsaKeyPEM(t), now.Add(-time.Hour), time.Time{}))
+	otherToken, err := NewJWTManagerWithKeys(otherKeys, 15*time.Minute, time.Hour).GenerateAccessToken(user, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := manager.ValidateAccessToken(otherToken); err == nil {
+		t.Error("Expected token with unknown kid to be rejected")
+	}
+
+	// A token from a retired key must not pass
+	token, err := manager.GenerateAccessToken(user, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	retiring.RetireAt = now.Add(-time.Second)
+	if _, err := manager.ValidateAccessToken(token); err == nil {
+		t.Error("Expected token signed with a retired key to be rejected")
+	}
+	if _, err := manager.GenerateAccessToken(user, ""); err == nil {
+		t.Error("Expected signing to fail without an active key")
+	}
+}
+
+func TestLoadKeySet(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "current.pem"), ed25519KeyPEM(t), 0600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+
+	manifest, _ := json.Marshal([]map[string]string{{
+		"kid":              "current",
+		"private_key_file": "current.pem",
+		"active_from":      "2025-01-01T00:00:00Z",
+	}})
+	manifestPath := filepath.Join(dir, "jwt-keys.json")
+	if err := os.WriteFile(manifestPath, manifest, 0600); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+
+	keys, err := LoadKeySet(manifestPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	key, err := keys.SigningKey(time.Now())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if key.ID != "current" || key.Algorithm != AlgorithmEdDSA {
+		t.Errorf("Unexpected key: %s %s", key.ID, key.Algorithm)
+	}
+}